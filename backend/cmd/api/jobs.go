@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Aolakije/City-Buzz/internal/event"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/internal/post"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/internal/trending"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StartJobs launches the background jobs. They stop when ctx is cancelled.
+func StartJobs(ctx context.Context, db *pgxpool.Pool, hub *realtime.Hub, emailer *notification.Emailer, pusher *notification.Pusher, cfg *config.Config) {
+	// Relay of real-time events published by every API instance
+	go hub.Run(ctx)
+
+	// Trending topics refresh
+	trendingService := trending.NewService(trending.NewRepository(db), cfg)
+	go trendingService.Run(ctx)
+
+	// Scheduled posts publication
+	notificationService := notification.NewService(notification.NewRepository(db), hub, emailer, pusher)
+	postScheduler := post.NewScheduler(post.NewRepository(db), cfg, notificationService)
+	go postScheduler.Run(ctx)
+
+	// Purge of posts deleted longer than the trash retention
+	postPurger := post.NewPurger(post.NewRepository(db), cfg)
+	go postPurger.Run(ctx)
+
+	// Reminders to users going to upcoming events
+	eventReminder := event.NewReminder(event.NewRepository(db), cfg, notificationService)
+	go eventReminder.Run(ctx)
+
+	// Daily and weekly email digests
+	digester := notification.NewDigester(notification.NewRepository(db), emailer, cfg)
+	go digester.Run(ctx)
+}
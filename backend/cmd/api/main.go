@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/Aolakije/City-Buzz/internal/auth"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
 	"github.com/Aolakije/City-Buzz/pkg/config"
 	"github.com/Aolakije/City-Buzz/pkg/database"
+	"github.com/Aolakije/City-Buzz/pkg/mailer"
+	"github.com/Aolakije/City-Buzz/pkg/webpush"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -48,8 +54,46 @@ func main() {
 		},
 	})
 
+	// Real-time events are fanned out across API instances through Redis, which
+	// also tracks who is online on any of them
+	hub := realtime.NewHub(redisClient)
+	presence := realtime.NewPresence(redisClient)
+
+	// Suspensions are recorded in Redis too, revoking the tokens already issued
+	suspensions := auth.NewSuspensions(redisClient, cfg)
+
+	// Notification emails go through the configured mailer
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create mailer: %v", err)
+	}
+	emailer, err := notification.NewEmailer(mail, cfg)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+
+	// Urgent notifications are also pushed to subscribed browsers when a VAPID
+	// key pair is configured
+	sender, err := webpush.NewSender(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create push sender: %v", err)
+	}
+	if sender == nil {
+		log.Println("VAPID_PRIVATE_KEY not set, push notifications are disabled")
+	}
+	pusher, err := notification.NewPusher(sender, notification.NewRepository(db), cfg)
+	if err != nil {
+		log.Fatalf("Failed to load push templates: %v", err)
+	}
+
 	// Setup all routes
-	SetupRoutes(app, db, cfg)
+	SetupRoutes(app, db, hub, presence, suspensions, emailer, pusher, cfg)
+
+	// Start background jobs; stopping them also ends the real-time streams so
+	// the server can shut down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	StartJobs(jobsCtx, db, hub, emailer, pusher, cfg)
 
 	// Start server
 	port := cfg.Server.Port
@@ -70,6 +114,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 	if err := app.Shutdown(); err != nil {
 		log.Fatalf("Server shutdown error: %v", err)
 	}
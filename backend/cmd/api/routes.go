@@ -1,24 +1,36 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/Aolakije/City-Buzz/internal/auth"
+	"github.com/Aolakije/City-Buzz/internal/bookmark"
+	"github.com/Aolakije/City-Buzz/internal/chat"
+	"github.com/Aolakije/City-Buzz/internal/contentfilter"
 	"github.com/Aolakije/City-Buzz/internal/event"
+	"github.com/Aolakije/City-Buzz/internal/linkpreview"
 	"github.com/Aolakije/City-Buzz/internal/middleware"
+	"github.com/Aolakije/City-Buzz/internal/moderation"
 	"github.com/Aolakije/City-Buzz/internal/news"
+	"github.com/Aolakije/City-Buzz/internal/notification"
 	"github.com/Aolakije/City-Buzz/internal/post"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/internal/search"
+	"github.com/Aolakije/City-Buzz/internal/trending"
 	"github.com/Aolakije/City-Buzz/internal/upload"
+	"github.com/Aolakije/City-Buzz/internal/user"
 	"github.com/Aolakije/City-Buzz/pkg/config"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
+func SetupRoutes(app *fiber.App, db *pgxpool.Pool, hub *realtime.Hub, presence *realtime.Presence, suspensions *auth.Suspensions, emailer *notification.Emailer, pusher *notification.Pusher, cfg *config.Config) {
 	// Middleware
 	app.Use(recover.New())
 	app.Use(logger.New(logger.Config{
@@ -43,9 +55,37 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	authService := auth.NewService(authRepo, cfg)
 	authHandler := auth.NewHandler(authService, cfg)
 
+	// Initialize notification module; notifications are produced by the user,
+	// event and post modules
+	notificationRepo := notification.NewRepository(db)
+	notificationService := notification.NewService(notificationRepo, hub, emailer, pusher)
+	notificationHandler := notification.NewHandler(notificationService)
+
+	// Initialize user module
+	userRepo := user.NewRepository(db)
+	userService := user.NewService(userRepo, notificationService)
+	userHandler := user.NewHandler(userService)
+
+	// Content filters run on new posts, comments and events
+	contentFilter := contentfilter.NewDefaultPipeline(db, cfg)
+
+	// Link previews are fetched in the background for URLs shared in posts
+	previewRepo := linkpreview.NewRepository(db)
+	previewService := linkpreview.NewService(previewRepo, cfg)
+
+	// Initialize chat module; who can message whom is decided by the user module
+	chatRepo := chat.NewRepository(db)
+	chatService := chat.NewService(chatRepo, userService, hub, presence)
+	chatHandler := chat.NewHandler(chatService, cfg)
+
+	// Initialize event module; attendees going to an event are in its group chat
+	eventRepo := event.NewRepository(db)
+	eventService := event.NewService(eventRepo, cfg, contentFilter, notificationService, hub, chatService)
+	eventHandler := event.NewHandler(eventService)
+
 	// Initialize post module
 	postRepo := post.NewRepository(db)
-	postService := post.NewService(postRepo)
+	postService := post.NewService(postRepo, cfg, contentFilter, previewService, eventService, userService, notificationService, hub)
 	postHandler := post.NewHandler(postService)
 
 	// Initialize news module
@@ -56,13 +96,42 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	}
 	newsHandler := news.NewHandler(newsService)
 
-	// Initialize event module
-	eventRepo := event.NewRepository(db)
-	eventService := event.NewService(eventRepo, cfg)
-	eventHandler := event.NewHandler(eventService)
+	// Initialize bookmark module
+	bookmarkRepo := bookmark.NewRepository(db)
+	bookmarkService := bookmark.NewService(bookmarkRepo, postService, eventService)
+	bookmarkHandler := bookmark.NewHandler(bookmarkService)
+
+	// Initialize moderation module
+	moderationRepo := moderation.NewRepository(db)
+	moderationService := moderation.NewService(moderationRepo, postService, hub, suspensions)
+	moderationHandler := moderation.NewHandler(moderationService)
+
+	// Initialize search module
+	searchRepo := search.NewRepository(db)
+	searchService := search.NewService(searchRepo)
+	searchHandler := search.NewHandler(searchService)
+
+	// Initialize trending module
+	trendingRepo := trending.NewRepository(db)
+	trendingService := trending.NewService(trendingRepo, cfg)
+	trendingHandler := trending.NewHandler(trendingService)
+
+	// Real-time events stream to clients connected to any API instance; posts
+	// and events can only be followed by users allowed to see them
+	realtimeHandler := realtime.NewHandler(hub,
+		func(ctx context.Context, postID, userID uuid.UUID) error {
+			_, err := postService.GetPostByID(ctx, postID, userID)
+			return err
+		},
+		func(ctx context.Context, eventID, _ uuid.UUID) error {
+			_, err := eventService.GetEvent(ctx, eventID)
+			return err
+		},
+	)
 
 	// Initialize upload handler
-	uploadHandler := upload.NewHandler(cfg)
+	uploadRepo := upload.NewRepository(db)
+	uploadHandler := upload.NewHandler(uploadRepo, cfg)
 
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -82,24 +151,58 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	authRoutes.Post("/logout", authHandler.Logout)
 
 	// User routes (protected)
-	userRoutes := api.Group("/users", middleware.AuthMiddleware(cfg))
+	userRoutes := api.Group("/users", middleware.AuthMiddleware(cfg, suspensions))
 	userRoutes.Get("/me", authHandler.GetMe)
+	userRoutes.Get("/me/blocks", userHandler.GetBlockedUsers)
+	userRoutes.Get("/me/privacy", userHandler.GetPrivacy)
+	userRoutes.Put("/me/privacy", userHandler.UpdatePrivacy)
+	userRoutes.Post("/:username/follow", userHandler.FollowUser)
+	userRoutes.Delete("/:username/follow", userHandler.UnfollowUser)
+	userRoutes.Get("/:username/followers", userHandler.GetFollowers)
+	userRoutes.Get("/:username/following", userHandler.GetFollowing)
+	userRoutes.Post("/:username/block", userHandler.BlockUser)
+	userRoutes.Delete("/:username/block", userHandler.UnblockUser)
+	userRoutes.Get("/:username/posts", postHandler.GetUserPosts)
 
 	// Post routes (protected)
-	postRoutes := api.Group("/posts", middleware.AuthMiddleware(cfg))
+	postRoutes := api.Group("/posts", middleware.AuthMiddleware(cfg, suspensions))
 	postRoutes.Post("/", postHandler.CreatePost)
 	postRoutes.Get("/", postHandler.GetFeed)
+	postRoutes.Get("/drafts", postHandler.GetDrafts)
+	postRoutes.Get("/trash", postHandler.GetTrash)
+	postRoutes.Get("/nearby", postHandler.GetNearbyPosts)
 	postRoutes.Get("/:id", postHandler.GetPost)
 	postRoutes.Put("/:id", postHandler.UpdatePost)
+	postRoutes.Get("/:id/revisions", postHandler.GetPostRevisions)
 	postRoutes.Delete("/:id", postHandler.DeletePost)
+	postRoutes.Post("/:id/restore", postHandler.RestorePost)
+	postRoutes.Post("/:id/pin", postHandler.PinProfilePost)
+	postRoutes.Delete("/:id/pin", postHandler.UnpinProfilePost)
+	postRoutes.Post("/:id/publish", postHandler.PublishPost)
+	postRoutes.Put("/:id/schedule", postHandler.SchedulePost)
+	postRoutes.Delete("/:id/schedule", postHandler.UnschedulePost)
 	postRoutes.Post("/:id/like", postHandler.LikePost)
 	postRoutes.Delete("/:id/like", postHandler.UnlikePost)
+	postRoutes.Put("/:id/reaction", postHandler.ReactToPost)
+	postRoutes.Delete("/:id/reaction", postHandler.RemovePostReaction)
+	postRoutes.Post("/:id/repost", postHandler.Repost)
+	postRoutes.Delete("/:id/repost", postHandler.DeleteRepost)
+	postRoutes.Post("/:id/poll/vote", postHandler.VotePoll)
 	postRoutes.Post("/:id/comments", postHandler.CreateComment)
 	postRoutes.Get("/:id/comments", postHandler.GetComments)
 
 	// Comment routes (protected)
-	commentRoutes := api.Group("/comments", middleware.AuthMiddleware(cfg))
+	commentRoutes := api.Group("/comments", middleware.AuthMiddleware(cfg, suspensions))
+	commentRoutes.Put("/:id", postHandler.UpdateComment)
 	commentRoutes.Delete("/:id", postHandler.DeleteComment)
+	commentRoutes.Get("/:id/revisions", postHandler.GetCommentRevisions)
+	commentRoutes.Post("/:id/like", postHandler.LikeComment)
+	commentRoutes.Delete("/:id/like", postHandler.UnlikeComment)
+	commentRoutes.Put("/:id/reaction", postHandler.ReactToComment)
+	commentRoutes.Delete("/:id/reaction", postHandler.RemoveCommentReaction)
+
+	// Reaction types (protected)
+	api.Get("/reactions", middleware.AuthMiddleware(cfg, suspensions), postHandler.GetReactionTypes)
 
 	// News routes
 	newsRoutes := api.Group("/news")
@@ -112,9 +215,9 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	newsRoutes.Get("/search", newsHandler.SearchNews)
 
 	// Protected routes (auth required)
-	newsRoutes.Post("/save", middleware.AuthMiddleware(cfg), newsHandler.SaveArticle)
-	newsRoutes.Get("/saved", middleware.AuthMiddleware(cfg), newsHandler.GetSavedArticles)
-	newsRoutes.Delete("/saved", middleware.AuthMiddleware(cfg), newsHandler.DeleteSavedArticle)
+	newsRoutes.Post("/save", middleware.AuthMiddleware(cfg, suspensions), newsHandler.SaveArticle)
+	newsRoutes.Get("/saved", middleware.AuthMiddleware(cfg, suspensions), newsHandler.GetSavedArticles)
+	newsRoutes.Delete("/saved", middleware.AuthMiddleware(cfg, suspensions), newsHandler.DeleteSavedArticle)
 
 	// Event routes
 	eventRoutes := api.Group("/events")
@@ -122,26 +225,101 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	// Public routes
 	eventRoutes.Get("/rouen", eventHandler.GetRouenEvents)
 	eventRoutes.Get("/trending", eventHandler.GetTrendingEvents)
+	eventRoutes.Get("/nearby", eventHandler.GetNearbyEvents)
 
 	// Protected specific routes - MUST come before /:id
-	eventRoutes.Get("/my-events", middleware.AuthMiddleware(cfg), eventHandler.GetUserEvents)
-	eventRoutes.Get("/my-rsvps", middleware.AuthMiddleware(cfg), eventHandler.GetUserRSVPs)
+	eventRoutes.Get("/my-events", middleware.AuthMiddleware(cfg, suspensions), eventHandler.GetUserEvents)
+	eventRoutes.Get("/my-rsvps", middleware.AuthMiddleware(cfg, suspensions), eventHandler.GetUserRSVPs)
 
 	// Public dynamic route
 	eventRoutes.Get("/:id", eventHandler.GetEventByID)
 	eventRoutes.Get("/:id/attendees", eventHandler.GetEventAttendees) // ADD THIS LINE
 
 	// Protected CRUD routes
-	eventRoutes.Post("/", middleware.AuthMiddleware(cfg), eventHandler.CreateEvent)
-	eventRoutes.Put("/:id", middleware.AuthMiddleware(cfg), eventHandler.UpdateEvent)
-	eventRoutes.Delete("/:id", middleware.AuthMiddleware(cfg), eventHandler.DeleteEvent)
+	eventRoutes.Post("/", middleware.AuthMiddleware(cfg, suspensions), eventHandler.CreateEvent)
+	eventRoutes.Put("/:id", middleware.AuthMiddleware(cfg, suspensions), eventHandler.UpdateEvent)
+	eventRoutes.Delete("/:id", middleware.AuthMiddleware(cfg, suspensions), eventHandler.DeleteEvent)
+
+	// Protected discussion thread routes
+	eventRoutes.Get("/:id/posts", middleware.AuthMiddleware(cfg, suspensions), postHandler.GetEventPosts)
+	eventRoutes.Post("/:id/posts/:postId/pin", middleware.AuthMiddleware(cfg, suspensions), postHandler.PinEventPost)
+	eventRoutes.Delete("/:id/posts/:postId/pin", middleware.AuthMiddleware(cfg, suspensions), postHandler.UnpinEventPost)
 
 	// Protected RSVP routes
-	eventRoutes.Post("/:id/rsvp", middleware.AuthMiddleware(cfg), eventHandler.CreateOrUpdateRSVP)
-	eventRoutes.Delete("/:id/rsvp", middleware.AuthMiddleware(cfg), eventHandler.DeleteRSVP)
-	eventRoutes.Get("/:id/rsvp", middleware.AuthMiddleware(cfg), eventHandler.GetUserRSVP)
+	eventRoutes.Post("/:id/rsvp", middleware.AuthMiddleware(cfg, suspensions), eventHandler.CreateOrUpdateRSVP)
+	eventRoutes.Delete("/:id/rsvp", middleware.AuthMiddleware(cfg, suspensions), eventHandler.DeleteRSVP)
+	eventRoutes.Get("/:id/rsvp", middleware.AuthMiddleware(cfg, suspensions), eventHandler.GetUserRSVP)
+
+	// Group chat of the attendees going, created by the organizer
+	eventRoutes.Post("/:id/chat", middleware.AuthMiddleware(cfg, suspensions), eventHandler.CreateEventChat)
+
+	// Bookmark routes (protected)
+	bookmarkRoutes := api.Group("/bookmarks", middleware.AuthMiddleware(cfg, suspensions))
+	bookmarkRoutes.Get("/", bookmarkHandler.GetBookmarks)
+	bookmarkRoutes.Post("/", bookmarkHandler.CreateBookmark)
+	bookmarkRoutes.Get("/collections", bookmarkHandler.GetCollections)
+	bookmarkRoutes.Post("/collections", bookmarkHandler.CreateCollection)
+	bookmarkRoutes.Put("/collections/:id", bookmarkHandler.RenameCollection)
+	bookmarkRoutes.Delete("/collections/:id", bookmarkHandler.DeleteCollection)
+	bookmarkRoutes.Delete("/:id", bookmarkHandler.DeleteBookmark)
+	bookmarkRoutes.Put("/:id/collection", bookmarkHandler.MoveBookmark)
+
+	// Notification routes (protected)
+	notificationRoutes := api.Group("/notifications", middleware.AuthMiddleware(cfg, suspensions))
+	notificationRoutes.Get("/", notificationHandler.GetNotifications)
+	notificationRoutes.Get("/unread-count", notificationHandler.GetUnreadCount)
+	notificationRoutes.Post("/read-all", notificationHandler.MarkAllRead)
+	notificationRoutes.Get("/preferences", notificationHandler.GetSettings)
+	notificationRoutes.Put("/preferences", notificationHandler.UpdateSettings)
+	notificationRoutes.Put("/:id/read", notificationHandler.MarkRead)
+
+	// Conversation routes (protected)
+	conversationRoutes := api.Group("/conversations", middleware.AuthMiddleware(cfg, suspensions))
+	conversationRoutes.Get("/", chatHandler.GetConversations)
+	conversationRoutes.Post("/", chatHandler.StartConversation)
+	conversationRoutes.Get("/unread-count", chatHandler.GetUnreadCount)
+	conversationRoutes.Get("/live", chatHandler.Live)
+	conversationRoutes.Post("/groups", chatHandler.CreateGroup)
+	conversationRoutes.Get("/:id", chatHandler.GetConversation)
+	conversationRoutes.Put("/:id", chatHandler.UpdateGroup)
+	conversationRoutes.Post("/:id/members", chatHandler.AddMembers)
+	conversationRoutes.Delete("/:id/members/:username", chatHandler.RemoveMember)
+	conversationRoutes.Put("/:id/members/:username/role", chatHandler.UpdateMemberRole)
+	conversationRoutes.Post("/:id/leave", chatHandler.LeaveGroup)
+	conversationRoutes.Get("/:id/messages", chatHandler.GetMessages)
+	conversationRoutes.Post("/:id/messages", chatHandler.SendMessage)
+	conversationRoutes.Post("/:id/read", chatHandler.MarkRead)
+
+	// Web Push routes; the public key is needed before the user subscribes
+	api.Get("/push/vapid-public-key", notificationHandler.GetPushPublicKey)
+	pushRoutes := api.Group("/push", middleware.AuthMiddleware(cfg, suspensions))
+	pushRoutes.Post("/subscriptions", notificationHandler.SubscribePush)
+	pushRoutes.Delete("/subscriptions", notificationHandler.UnsubscribePush)
+
+	// Real-time routes (protected)
+	api.Get("/realtime/stream", middleware.AuthMiddleware(cfg, suspensions), realtimeHandler.Stream)
+
+	// Trending routes (public)
+	api.Get("/trending/topics", trendingHandler.GetTopics)
+
+	// Search routes (protected)
+	api.Get("/search", middleware.AuthMiddleware(cfg, suspensions), searchHandler.Search)
+
+	// Report routes (protected)
+	api.Post("/reports", middleware.AuthMiddleware(cfg, suspensions), moderationHandler.CreateReport)
+
+	// Moderation routes (protected, moderators only)
+	moderationRoutes := api.Group("/moderation", middleware.AuthMiddleware(cfg, suspensions))
+	moderationRoutes.Get("/reports", moderationHandler.GetReports)
+	moderationRoutes.Post("/reports/:id/dismiss", moderationHandler.DismissReport)
+	moderationRoutes.Post("/reports/:id/action", moderationHandler.ActOnReport)
+	moderationRoutes.Get("/actions", moderationHandler.GetActions)
+	moderationRoutes.Get("/held", moderationHandler.GetHeldItems)
+	moderationRoutes.Post("/held/:type/:id/approve", moderationHandler.ApproveHeldItem)
+	moderationRoutes.Post("/held/:type/:id/reject", moderationHandler.RejectHeldItem)
 
 	// Upload routes
-	uploadRoutes := api.Group("/upload", middleware.AuthMiddleware(cfg))
+	uploadRoutes := api.Group("/upload", middleware.AuthMiddleware(cfg, suspensions))
 	uploadRoutes.Post("/event-image", uploadHandler.UploadEventImage)
+	uploadRoutes.Post("/chat-attachment", uploadHandler.UploadChatAttachment)
 }
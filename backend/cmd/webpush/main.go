@@ -0,0 +1,51 @@
+// Command webpush helps set up Web Push locally.
+//
+//	webpush generate-vapid-keys       prints a new VAPID key pair for the .env file
+//	webpush standin [-addr :8090]     runs a local push service to subscribe and push to
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/Aolakije/City-Buzz/pkg/webpush"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "generate-vapid-keys":
+		publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+		if err != nil {
+			log.Fatalf("Failed to generate VAPID keys: %v", err)
+		}
+		fmt.Printf("VAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n", publicKey, privateKey)
+
+	case "standin":
+		flags := flag.NewFlagSet("standin", flag.ExitOnError)
+		addr := flags.String("addr", "localhost:8090", "address to listen on")
+		baseURL := flags.String("url", "", "base URL of the endpoints handed out (default http://<addr>)")
+		flags.Parse(os.Args[2:])
+
+		if *baseURL == "" {
+			*baseURL = "http://" + *addr
+		}
+
+		log.Printf("Push stand-in listening on %s; create a subscription with: curl -X POST %s/subscriptions", *addr, *baseURL)
+		log.Fatal(http.ListenAndServe(*addr, webpush.NewStandIn(*baseURL)))
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: webpush generate-vapid-keys | webpush standin [-addr host:port] [-url base-url]")
+	os.Exit(2)
+}
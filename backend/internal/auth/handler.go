@@ -44,7 +44,7 @@ func (h *Handler) Register(c *fiber.Ctx) error {
 	log.Printf("New user registered: %s (ID: %s)", user.Username, user.ID)
 
 	return utils.SuccessResponse(c, fiber.StatusCreated, "Account created successfully", fiber.Map{
-		"user": user.ToResponse(),
+		"user": user.ToSelfResponse(),
 	})
 }
 
@@ -83,7 +83,7 @@ c.Cookie(cookie)
 	log.Printf("User logged in: %s (ID: %s)", user.Username, user.ID)
 
 	return utils.SuccessResponse(c, fiber.StatusOK, "Login successful", fiber.Map{
-		"user": user.ToResponse(),
+		"user": user.ToSelfResponse(),
 	})
 }
 
@@ -126,6 +126,6 @@ func (h *Handler) GetMe(c *fiber.Ctx) error {
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
-		"user": user.ToResponse(),
+		"user": user.ToSelfResponse(),
 	})
 }
@@ -26,7 +26,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
 			gender, date_of_birth, language, confirm_method
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-		) RETURNING id, created_at, updated_at
+		) RETURNING id, role, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(
@@ -34,7 +34,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
 		user.Email, user.Phone, user.Username, user.PasswordHash,
 		user.FirstName, user.LastName, user.Gender, user.DateOfBirth,
 		user.Language, user.ConfirmMethod,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -47,7 +47,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
 func (r *Repository) FindUserByIdentifier(ctx context.Context, identifier string) (*models.User, error) {
 	query := `
 		SELECT id, email, phone, username, password_hash, first_name, last_name,
-		       gender, date_of_birth, bio, avatar_url, language, confirm_method,
+		       gender, date_of_birth, bio, avatar_url, language, confirm_method, role,
 		       created_at, updated_at, last_login, is_active, email_verified, phone_verified
 		FROM users
 		WHERE (email = $1 OR phone = $1 OR username = $1) AND is_active = true
@@ -57,7 +57,7 @@ func (r *Repository) FindUserByIdentifier(ctx context.Context, identifier string
 	err := r.db.QueryRow(ctx, query, identifier).Scan(
 		&user.ID, &user.Email, &user.Phone, &user.Username, &user.PasswordHash,
 		&user.FirstName, &user.LastName, &user.Gender, &user.DateOfBirth,
-		&user.Bio, &user.AvatarURL, &user.Language, &user.ConfirmMethod,
+		&user.Bio, &user.AvatarURL, &user.Language, &user.ConfirmMethod, &user.Role,
 		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.IsActive,
 		&user.EmailVerified, &user.PhoneVerified,
 	)
@@ -76,7 +76,7 @@ func (r *Repository) FindUserByIdentifier(ctx context.Context, identifier string
 func (r *Repository) FindUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	query := `
 		SELECT id, email, phone, username, password_hash, first_name, last_name,
-		       gender, date_of_birth, bio, avatar_url, language, confirm_method,
+		       gender, date_of_birth, bio, avatar_url, language, confirm_method, role,
 		       created_at, updated_at, last_login, is_active, email_verified, phone_verified
 		FROM users
 		WHERE id = $1 AND is_active = true
@@ -86,7 +86,7 @@ func (r *Repository) FindUserByID(ctx context.Context, userID uuid.UUID) (*model
 	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&user.ID, &user.Email, &user.Phone, &user.Username, &user.PasswordHash,
 		&user.FirstName, &user.LastName, &user.Gender, &user.DateOfBirth,
-		&user.Bio, &user.AvatarURL, &user.Language, &user.ConfirmMethod,
+		&user.Bio, &user.AvatarURL, &user.Language, &user.ConfirmMethod, &user.Role,
 		&user.CreatedAt, &user.UpdatedAt, &user.LastLogin, &user.IsActive,
 		&user.EmailVerified, &user.PhoneVerified,
 	)
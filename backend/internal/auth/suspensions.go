@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Suspensions records suspended accounts in Redis so that the tokens issued
+// before a suspension stop working. Suspended accounts cannot log in again, so
+// a record only has to outlive the tokens already issued.
+type Suspensions struct {
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+func NewSuspensions(client *redis.Client, cfg *config.Config) *Suspensions {
+	return &Suspensions{redis: client, ttl: cfg.JWT.Expiry}
+}
+
+func suspendedKey(userID uuid.UUID) string {
+	return "auth:suspended:" + userID.String()
+}
+
+// Suspend revokes the tokens already issued to a user
+func (s *Suspensions) Suspend(ctx context.Context, userID uuid.UUID) error {
+	if err := s.redis.Set(ctx, suspendedKey(userID), 1, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to record suspension: %w", err)
+	}
+	return nil
+}
+
+// IsSuspended reports whether a user was suspended after their token was issued
+func (s *Suspensions) IsSuspended(ctx context.Context, userID uuid.UUID) (bool, error) {
+	count, err := s.redis.Exists(ctx, suspendedKey(userID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check suspension: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,282 @@
+package bookmark
+
+import (
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateBookmark handles saving a post, event or news article
+// POST /api/v1/bookmarks
+func (h *Handler) CreateBookmark(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.CreateBookmarkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	bookmark, err := h.service.CreateBookmark(c.Context(), userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "item id required":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "item_id is required for posts and events")
+		case "article details required":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "article_url and article_title are required for articles")
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		case "event not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Event not found")
+		case "collection not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Collection not found")
+		case "already bookmarked":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Already bookmarked")
+		}
+		log.Printf("Create bookmark error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create bookmark")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Bookmark saved", fiber.Map{
+		"bookmark": bookmark,
+	})
+}
+
+// GetBookmarks handles retrieval of saved posts, events and articles in saved order
+// GET /api/v1/bookmarks?type=post&collection_id=...&page=1&limit=20
+func (h *Handler) GetBookmarks(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	itemType := c.Query("type")
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	var collectionID *uuid.UUID
+	if raw := c.Query("collection_id"); raw != "" {
+		id, err := utils.ParseUUID(raw)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid collection ID")
+		}
+		collectionID = &id
+	}
+
+	bookmarks, err := h.service.GetBookmarks(c.Context(), userID, itemType, collectionID, page, limit)
+	if err != nil {
+		switch err.Error() {
+		case "invalid bookmark type":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Type must be post, event or article")
+		case "collection not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Collection not found")
+		}
+		log.Printf("Get bookmarks error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get bookmarks")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"bookmarks": bookmarks,
+		"type":      itemType,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// DeleteBookmark handles removing a bookmark
+// DELETE /api/v1/bookmarks/:id
+func (h *Handler) DeleteBookmark(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	bookmarkID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid bookmark ID")
+	}
+
+	if err := h.service.DeleteBookmark(c.Context(), bookmarkID, userID); err != nil {
+		if err.Error() == "bookmark not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Bookmark not found")
+		}
+		log.Printf("Delete bookmark error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete bookmark")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Bookmark removed", nil)
+}
+
+// MoveBookmark handles moving a bookmark into or out of a collection
+// PUT /api/v1/bookmarks/:id/collection
+func (h *Handler) MoveBookmark(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	bookmarkID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid bookmark ID")
+	}
+
+	var req models.MoveBookmarkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.service.MoveBookmark(c.Context(), bookmarkID, userID, req.CollectionID); err != nil {
+		switch err.Error() {
+		case "bookmark not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Bookmark not found")
+		case "collection not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Collection not found")
+		}
+		log.Printf("Move bookmark error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to move bookmark")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Bookmark moved", nil)
+}
+
+// CreateCollection handles creating a bookmark collection
+// POST /api/v1/bookmarks/collections
+func (h *Handler) CreateCollection(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.BookmarkCollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	collection, err := h.service.CreateCollection(c.Context(), userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "collection name required":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Collection name is required")
+		case "collection already exists":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "A collection with this name already exists")
+		}
+		log.Printf("Create collection error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create collection")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Collection created", fiber.Map{
+		"collection": collection,
+	})
+}
+
+// GetCollections handles listing the user's bookmark collections
+// GET /api/v1/bookmarks/collections
+func (h *Handler) GetCollections(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	collections, err := h.service.GetCollections(c.Context(), userID)
+	if err != nil {
+		log.Printf("Get collections error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get collections")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"collections": collections,
+	})
+}
+
+// RenameCollection handles renaming a bookmark collection
+// PUT /api/v1/bookmarks/collections/:id
+func (h *Handler) RenameCollection(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	collectionID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid collection ID")
+	}
+
+	var req models.BookmarkCollectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	collection, err := h.service.RenameCollection(c.Context(), collectionID, userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "collection name required":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Collection name is required")
+		case "collection not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Collection not found")
+		case "collection already exists":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "A collection with this name already exists")
+		}
+		log.Printf("Rename collection error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to rename collection")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Collection renamed", fiber.Map{
+		"collection": collection,
+	})
+}
+
+// DeleteCollection handles deleting a bookmark collection; its bookmarks are kept
+// DELETE /api/v1/bookmarks/collections/:id
+func (h *Handler) DeleteCollection(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	collectionID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid collection ID")
+	}
+
+	if err := h.service.DeleteCollection(c.Context(), collectionID, userID); err != nil {
+		if err.Error() == "collection not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Collection not found")
+		}
+		log.Printf("Delete collection error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete collection")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Collection deleted", nil)
+}
@@ -0,0 +1,256 @@
+package bookmark
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateBookmark saves an item for a user
+func (r *Repository) CreateBookmark(ctx context.Context, bookmark *models.Bookmark) error {
+	query := `
+		INSERT INTO bookmarks (user_id, item_type, post_id, event_id, article_url, article_title,
+		                       article_image, article_source, collection_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT DO NOTHING
+		RETURNING id, saved_at
+	`
+
+	var articleURL, articleTitle, articleImage, articleSource *string
+	if bookmark.Article != nil {
+		articleURL = &bookmark.Article.ArticleURL
+		articleTitle = &bookmark.Article.ArticleTitle
+		articleImage = &bookmark.Article.ArticleImage
+		articleSource = &bookmark.Article.ArticleSource
+	}
+
+	err := r.db.QueryRow(ctx, query,
+		bookmark.UserID, bookmark.Type, bookmark.PostID, bookmark.EventID,
+		articleURL, articleTitle, articleImage, articleSource, bookmark.CollectionID,
+	).Scan(&bookmark.ID, &bookmark.SavedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("already bookmarked")
+		}
+		return fmt.Errorf("failed to create bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// GetBookmarks retrieves a user's bookmarks, most recently saved first.
+// An empty itemType matches every type; a nil collectionID matches every collection.
+func (r *Repository) GetBookmarks(ctx context.Context, userID uuid.UUID, itemType string, collectionID *uuid.UUID, limit, offset int) ([]models.Bookmark, error) {
+	query := `
+		SELECT id, user_id, item_type, post_id, event_id, article_url, article_title,
+		       article_image, article_source, collection_id, saved_at
+		FROM bookmarks
+		WHERE user_id = $1
+		  AND ($2 = '' OR item_type = $2)
+		  AND ($3::uuid IS NULL OR collection_id = $3)
+		ORDER BY saved_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, itemType, collectionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []models.Bookmark{}
+	for rows.Next() {
+		var bookmark models.Bookmark
+		var articleURL, articleTitle, articleImage, articleSource *string
+
+		err := rows.Scan(
+			&bookmark.ID,
+			&bookmark.UserID,
+			&bookmark.Type,
+			&bookmark.PostID,
+			&bookmark.EventID,
+			&articleURL,
+			&articleTitle,
+			&articleImage,
+			&articleSource,
+			&bookmark.CollectionID,
+			&bookmark.SavedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+
+		if bookmark.Type == models.BookmarkTypeArticle && articleURL != nil {
+			bookmark.Article = &models.SavedArticle{
+				ID:            bookmark.ID,
+				UserID:        bookmark.UserID,
+				ArticleURL:    *articleURL,
+				ArticleTitle:  derefString(articleTitle),
+				ArticleImage:  derefString(articleImage),
+				ArticleSource: derefString(articleSource),
+				SavedAt:       bookmark.SavedAt,
+			}
+		}
+
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+// DeleteBookmark removes one of a user's bookmarks
+func (r *Repository) DeleteBookmark(ctx context.Context, bookmarkID, userID uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM bookmarks WHERE id = $1 AND user_id = $2`, bookmarkID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("bookmark not found")
+	}
+
+	return nil
+}
+
+// MoveBookmark sets the collection of one of a user's bookmarks
+func (r *Repository) MoveBookmark(ctx context.Context, bookmarkID, userID uuid.UUID, collectionID *uuid.UUID) error {
+	query := `UPDATE bookmarks SET collection_id = $1 WHERE id = $2 AND user_id = $3`
+
+	result, err := r.db.Exec(ctx, query, collectionID, bookmarkID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to move bookmark: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("bookmark not found")
+	}
+
+	return nil
+}
+
+// CreateCollection creates a bookmark collection
+func (r *Repository) CreateCollection(ctx context.Context, collection *models.BookmarkCollection) error {
+	query := `
+		INSERT INTO bookmark_collections (user_id, name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, collection.UserID, collection.Name).
+		Scan(&collection.ID, &collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("collection already exists")
+		}
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return nil
+}
+
+// GetCollections retrieves a user's collections with their bookmark counts, by name
+func (r *Repository) GetCollections(ctx context.Context, userID uuid.UUID) ([]models.BookmarkCollection, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.created_at, c.updated_at,
+		       (SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id)
+		FROM bookmark_collections c
+		WHERE c.user_id = $1
+		ORDER BY LOWER(c.name) ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.BookmarkCollection{}
+	for rows.Next() {
+		var collection models.BookmarkCollection
+		err := rows.Scan(
+			&collection.ID,
+			&collection.UserID,
+			&collection.Name,
+			&collection.CreatedAt,
+			&collection.UpdatedAt,
+			&collection.BookmarksCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, collection)
+	}
+
+	return collections, rows.Err()
+}
+
+// CollectionExists checks whether a collection belongs to the user
+func (r *Repository) CollectionExists(ctx context.Context, collectionID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM bookmark_collections WHERE id = $1 AND user_id = $2)`
+	err := r.db.QueryRow(ctx, query, collectionID, userID).Scan(&exists)
+	return exists, err
+}
+
+// RenameCollection renames one of a user's collections
+func (r *Repository) RenameCollection(ctx context.Context, collection *models.BookmarkCollection) error {
+	query := `
+		UPDATE bookmark_collections SET name = $1
+		WHERE id = $2 AND user_id = $3
+		  AND NOT EXISTS (
+		      SELECT 1 FROM bookmark_collections
+		      WHERE user_id = $3 AND name = $1 AND id <> $2
+		  )
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, collection.Name, collection.ID, collection.UserID).
+		Scan(&collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			exists, existsErr := r.CollectionExists(ctx, collection.ID, collection.UserID)
+			if existsErr == nil && exists {
+				return fmt.Errorf("collection already exists")
+			}
+			return fmt.Errorf("collection not found")
+		}
+		return fmt.Errorf("failed to rename collection: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes one of a user's collections; its bookmarks are kept
+// outside of any collection
+func (r *Repository) DeleteCollection(ctx context.Context, collectionID, userID uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM bookmark_collections WHERE id = $1 AND user_id = $2`, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("collection not found")
+	}
+
+	return nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
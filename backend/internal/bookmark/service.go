@@ -0,0 +1,245 @@
+package bookmark
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/event"
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/post"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo   *Repository
+	posts  *post.Service
+	events event.Service
+}
+
+func NewService(repo *Repository, posts *post.Service, events event.Service) *Service {
+	return &Service{
+		repo:   repo,
+		posts:  posts,
+		events: events,
+	}
+}
+
+// CreateBookmark saves a post, event or news article for the user
+func (s *Service) CreateBookmark(ctx context.Context, userID uuid.UUID, req *models.CreateBookmarkRequest) (*models.Bookmark, error) {
+	bookmark := &models.Bookmark{
+		UserID:       userID,
+		Type:         req.Type,
+		CollectionID: req.CollectionID,
+	}
+
+	switch req.Type {
+	case models.BookmarkTypePost:
+		if req.ItemID == nil {
+			return nil, fmt.Errorf("item id required")
+		}
+		// Only posts the user can see may be saved
+		post, err := s.posts.GetPostByID(ctx, *req.ItemID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !post.IsPublished() {
+			return nil, fmt.Errorf("post not found")
+		}
+		bookmark.PostID = &post.ID
+		bookmark.Post = post
+
+	case models.BookmarkTypeEvent:
+		if req.ItemID == nil {
+			return nil, fmt.Errorf("item id required")
+		}
+		if err := s.events.EnsureEvent(ctx, *req.ItemID); err != nil {
+			log.Printf("Bookmark event %s error: %v", *req.ItemID, err)
+			return nil, fmt.Errorf("event not found")
+		}
+		bookmark.EventID = req.ItemID
+
+	case models.BookmarkTypeArticle:
+		if req.ArticleURL == "" || strings.TrimSpace(req.ArticleTitle) == "" {
+			return nil, fmt.Errorf("article details required")
+		}
+		bookmark.Article = &models.SavedArticle{
+			UserID:        userID,
+			ArticleURL:    req.ArticleURL,
+			ArticleTitle:  strings.TrimSpace(req.ArticleTitle),
+			ArticleImage:  req.ArticleImage,
+			ArticleSource: req.ArticleSource,
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid bookmark type")
+	}
+
+	if err := s.checkCollection(ctx, req.CollectionID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateBookmark(ctx, bookmark); err != nil {
+		return nil, err
+	}
+
+	if bookmark.Article != nil {
+		bookmark.Article.ID = bookmark.ID
+		bookmark.Article.SavedAt = bookmark.SavedAt
+	}
+
+	if bookmark.EventID != nil {
+		events, err := s.events.GetEventsByIDs(ctx, []uuid.UUID{*bookmark.EventID})
+		if err != nil {
+			return nil, err
+		}
+		bookmark.Event = events[*bookmark.EventID]
+	}
+
+	return bookmark, nil
+}
+
+// GetBookmarks returns the user's bookmarks of every type mixed together, most
+// recently saved first, optionally narrowed to one type or collection
+func (s *Service) GetBookmarks(ctx context.Context, userID uuid.UUID, itemType string, collectionID *uuid.UUID, page, limit int) ([]models.Bookmark, error) {
+	switch itemType {
+	case "", models.BookmarkTypePost, models.BookmarkTypeEvent, models.BookmarkTypeArticle:
+	default:
+		return nil, fmt.Errorf("invalid bookmark type")
+	}
+
+	if err := s.checkCollection(ctx, collectionID, userID); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+	bookmarks, err := s.repo.GetBookmarks(ctx, userID, itemType, collectionID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachItems(ctx, bookmarks, userID); err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+// DeleteBookmark removes one of the user's bookmarks
+func (s *Service) DeleteBookmark(ctx context.Context, bookmarkID, userID uuid.UUID) error {
+	return s.repo.DeleteBookmark(ctx, bookmarkID, userID)
+}
+
+// MoveBookmark moves a bookmark into one of the user's collections, or out of
+// any collection when collectionID is nil
+func (s *Service) MoveBookmark(ctx context.Context, bookmarkID, userID uuid.UUID, collectionID *uuid.UUID) error {
+	if err := s.checkCollection(ctx, collectionID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.MoveBookmark(ctx, bookmarkID, userID, collectionID)
+}
+
+// CreateCollection creates a bookmark collection for the user
+func (s *Service) CreateCollection(ctx context.Context, userID uuid.UUID, req *models.BookmarkCollectionRequest) (*models.BookmarkCollection, error) {
+	collection := &models.BookmarkCollection{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+	}
+
+	if collection.Name == "" {
+		return nil, fmt.Errorf("collection name required")
+	}
+
+	if err := s.repo.CreateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// GetCollections returns the user's collections
+func (s *Service) GetCollections(ctx context.Context, userID uuid.UUID) ([]models.BookmarkCollection, error) {
+	return s.repo.GetCollections(ctx, userID)
+}
+
+// RenameCollection renames one of the user's collections
+func (s *Service) RenameCollection(ctx context.Context, collectionID, userID uuid.UUID, req *models.BookmarkCollectionRequest) (*models.BookmarkCollection, error) {
+	collection := &models.BookmarkCollection{
+		ID:     collectionID,
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+	}
+
+	if collection.Name == "" {
+		return nil, fmt.Errorf("collection name required")
+	}
+
+	if err := s.repo.RenameCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// DeleteCollection deletes one of the user's collections, keeping its bookmarks
+func (s *Service) DeleteCollection(ctx context.Context, collectionID, userID uuid.UUID) error {
+	return s.repo.DeleteCollection(ctx, collectionID, userID)
+}
+
+// checkCollection makes sure a collection, when given, belongs to the user
+func (s *Service) checkCollection(ctx context.Context, collectionID *uuid.UUID, userID uuid.UUID) error {
+	if collectionID == nil {
+		return nil
+	}
+
+	exists, err := s.repo.CollectionExists(ctx, *collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check collection: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("collection not found")
+	}
+
+	return nil
+}
+
+// attachItems loads the saved posts and events, leaving out those the user can no longer see
+func (s *Service) attachItems(ctx context.Context, bookmarks []models.Bookmark, userID uuid.UUID) error {
+	var postIDs, eventIDs []uuid.UUID
+	for _, bookmark := range bookmarks {
+		if bookmark.PostID != nil {
+			postIDs = append(postIDs, *bookmark.PostID)
+		}
+		if bookmark.EventID != nil {
+			eventIDs = append(eventIDs, *bookmark.EventID)
+		}
+	}
+
+	if len(postIDs) > 0 {
+		posts, err := s.posts.GetVisiblePosts(ctx, postIDs, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmarked posts: %w", err)
+		}
+		for i := range bookmarks {
+			if bookmarks[i].PostID != nil {
+				bookmarks[i].Post = posts[*bookmarks[i].PostID]
+			}
+		}
+	}
+
+	if len(eventIDs) > 0 {
+		events, err := s.events.GetEventsByIDs(ctx, eventIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmarked events: %w", err)
+		}
+		for i := range bookmarks {
+			if bookmarks[i].EventID != nil {
+				bookmarks[i].Event = events[*bookmarks[i].EventID]
+			}
+		}
+	}
+
+	return nil
+}
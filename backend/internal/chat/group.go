@@ -0,0 +1,358 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/google/uuid"
+)
+
+// maxGroupMembers caps the size of the groups users put together themselves.
+// Event groups follow the event's attendance instead.
+const maxGroupMembers = 100
+
+// CreateGroup creates a group with the user as admin and the users with the
+// given usernames as members, each of whom must accept the user's messages
+func (s *Service) CreateGroup(ctx context.Context, userID uuid.UUID, req *models.CreateGroupRequest) (*models.Conversation, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("group name is empty")
+	}
+
+	memberIDs, err := s.resolveMembers(ctx, userID, req.Usernames)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberIDs)+1 > maxGroupMembers {
+		return nil, fmt.Errorf("group is full")
+	}
+
+	avatarURL, err := s.resolveAvatar(ctx, userID, req.AvatarID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.Conversation{Name: &name, AvatarURL: avatarURL}
+	if err := s.repo.CreateGroup(ctx, group, userID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	s.publishLastMessage(ctx, group.ID)
+
+	return s.GetConversation(ctx, group.ID, userID)
+}
+
+// UpdateGroup renames a group or changes its avatar; only its admins can
+func (s *Service) UpdateGroup(ctx context.Context, conversationID, userID uuid.UUID, req *models.UpdateGroupRequest) (*models.Conversation, error) {
+	if _, err := s.requireAdmin(ctx, conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	var name *string
+	if req.Name != nil {
+		trimmed := strings.TrimSpace(*req.Name)
+		if trimmed == "" {
+			return nil, fmt.Errorf("group name is empty")
+		}
+		name = &trimmed
+	}
+
+	avatarURL, err := s.resolveAvatar(ctx, userID, req.AvatarID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil || avatarURL != nil {
+		messageID, err := s.repo.UpdateGroup(ctx, conversationID, userID, name, avatarURL)
+		if err != nil {
+			return nil, err
+		}
+		s.publishMessages(ctx, conversationID, []uuid.UUID{messageID})
+	}
+
+	return s.GetConversation(ctx, conversationID, userID)
+}
+
+// AddMembers adds the users with the given usernames to a group; only its
+// admins can, and each new member must accept the admin's messages
+func (s *Service) AddMembers(ctx context.Context, conversationID, userID uuid.UUID, req *models.AddMembersRequest) (*models.Conversation, error) {
+	group, err := s.requireAdmin(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.resolveMembers(ctx, userID, req.Usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.EventID == nil {
+		count, err := s.repo.CountMembers(ctx, conversationID)
+		if err != nil {
+			return nil, err
+		}
+		if count+len(memberIDs) > maxGroupMembers {
+			return nil, fmt.Errorf("group is full")
+		}
+	}
+
+	messageIDs, err := s.repo.AddMembers(ctx, conversationID, userID, memberIDs, models.MessageKindMemberAdded)
+	if err != nil {
+		return nil, err
+	}
+	s.publishMessages(ctx, conversationID, messageIDs)
+
+	return s.GetConversation(ctx, conversationID, userID)
+}
+
+// RemoveMember removes the member with the given username from a group; only
+// its admins can
+func (s *Service) RemoveMember(ctx context.Context, conversationID, userID uuid.UUID, username string) error {
+	if _, err := s.requireAdmin(ctx, conversationID, userID); err != nil {
+		return err
+	}
+
+	memberID, err := s.users.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if memberID == userID {
+		return fmt.Errorf("leave the group to remove yourself")
+	}
+
+	messageID, err := s.repo.RemoveMember(ctx, conversationID, userID, memberID, models.MessageKindMemberRemoved)
+	if err != nil {
+		return err
+	}
+
+	// The removed member sees the message that took them out
+	s.publishMessages(ctx, conversationID, []uuid.UUID{messageID}, memberID)
+
+	return nil
+}
+
+// UpdateMemberRole makes the member with the given username an admin of a
+// group, or a plain member again; only its admins can. A group always keeps
+// an admin.
+func (s *Service) UpdateMemberRole(ctx context.Context, conversationID, userID uuid.UUID, username, role string) (*models.Conversation, error) {
+	if _, err := s.requireAdmin(ctx, conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	memberID, err := s.users.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.IsMember(ctx, conversationID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, fmt.Errorf("member not found")
+	}
+
+	messageID, err := s.repo.SetRole(ctx, conversationID, userID, memberID, role)
+	if err != nil {
+		return nil, err
+	}
+	if messageID != uuid.Nil {
+		s.publishMessages(ctx, conversationID, []uuid.UUID{messageID})
+	}
+
+	return s.GetConversation(ctx, conversationID, userID)
+}
+
+// LeaveGroup takes the user out of a group. If they were its last admin, the
+// longest-standing member takes over; if they were its last member, the group
+// is deleted.
+func (s *Service) LeaveGroup(ctx context.Context, conversationID, userID uuid.UUID) error {
+	conversation, err := s.repo.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if conversation.Type != models.ConversationGroup {
+		return fmt.Errorf("not a group conversation")
+	}
+
+	messageID, err := s.repo.RemoveMember(ctx, conversationID, userID, userID, models.MessageKindMemberLeft)
+	if err != nil {
+		return err
+	}
+	if messageID != uuid.Nil {
+		s.publishMessages(ctx, conversationID, []uuid.UUID{messageID})
+	}
+
+	return nil
+}
+
+// CreateEventGroup creates the group of an event, named after it, with its
+// organizer as admin and the given attendees as members. If the event already
+// has a group, the organizer joins it again if needed and it is returned.
+func (s *Service) CreateEventGroup(ctx context.Context, eventID, organizerID uuid.UUID, name string, avatarURL *string, attendeeIDs []uuid.UUID) (*models.Conversation, bool, error) {
+	conversationID, err := s.repo.GetEventGroupID(ctx, eventID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if conversationID == uuid.Nil {
+		group := &models.Conversation{Name: &name, AvatarURL: avatarURL, EventID: &eventID}
+		err := s.repo.CreateGroup(ctx, group, organizerID, attendeeIDs)
+		switch {
+		case err == nil:
+			s.publishLastMessage(ctx, group.ID)
+			conversation, err := s.GetConversation(ctx, group.ID, organizerID)
+			return conversation, true, err
+		case err.Error() != "event already has a group":
+			return nil, false, err
+		}
+
+		// Created concurrently
+		if conversationID, err = s.repo.GetEventGroupID(ctx, eventID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := s.JoinEventGroup(ctx, eventID, organizerID); err != nil {
+		return nil, false, err
+	}
+
+	conversation, err := s.GetConversation(ctx, conversationID, organizerID)
+	return conversation, false, err
+}
+
+// JoinEventGroup adds a user going to an event to its group, if it has one
+func (s *Service) JoinEventGroup(ctx context.Context, eventID, userID uuid.UUID) error {
+	conversationID, err := s.repo.GetEventGroupID(ctx, eventID)
+	if err != nil || conversationID == uuid.Nil {
+		return err
+	}
+
+	messageIDs, err := s.repo.AddMembers(ctx, conversationID, userID, []uuid.UUID{userID}, models.MessageKindMemberJoined)
+	if err != nil {
+		return err
+	}
+	s.publishMessages(ctx, conversationID, messageIDs)
+
+	return nil
+}
+
+// LeaveEventGroup takes a user no longer going to an event out of its group,
+// if it has one and they are in it
+func (s *Service) LeaveEventGroup(ctx context.Context, eventID, userID uuid.UUID) error {
+	conversationID, err := s.repo.GetEventGroupID(ctx, eventID)
+	if err != nil || conversationID == uuid.Nil {
+		return err
+	}
+
+	messageID, err := s.repo.RemoveMember(ctx, conversationID, userID, userID, models.MessageKindMemberLeft)
+	if err != nil {
+		if err.Error() == "member not found" {
+			return nil
+		}
+		return err
+	}
+	if messageID != uuid.Nil {
+		s.publishMessages(ctx, conversationID, []uuid.UUID{messageID})
+	}
+
+	return nil
+}
+
+// requireAdmin retrieves a group the user administers
+func (s *Service) requireAdmin(ctx context.Context, conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.repo.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Type != models.ConversationGroup {
+		return nil, fmt.Errorf("not a group conversation")
+	}
+	if conversation.Role != models.ConversationRoleAdmin {
+		return nil, fmt.Errorf("only group admins can do this")
+	}
+
+	return conversation, nil
+}
+
+// resolveMembers looks up the users with the given usernames, who must all
+// accept messages from the user adding them. The user themselves is skipped.
+func (s *Service) resolveMembers(ctx context.Context, userID uuid.UUID, usernames []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool)
+	memberIDs := make([]uuid.UUID, 0, len(usernames))
+
+	for _, username := range usernames {
+		memberID, err := s.users.GetUserIDByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		if memberID == userID || seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+
+		if err := s.users.CanMessage(ctx, userID, memberID); err != nil {
+			return nil, err
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	return memberIDs, nil
+}
+
+// resolveAvatar returns the URL of an image the user uploaded to use as a group
+// avatar, or nil when none is given
+func (s *Service) resolveAvatar(ctx context.Context, userID uuid.UUID, uploadID *uuid.UUID) (*string, error) {
+	if uploadID == nil {
+		return nil, nil
+	}
+
+	upload, err := s.repo.GetUpload(ctx, *uploadID)
+	if err != nil || upload.UserID != userID {
+		return nil, fmt.Errorf("avatar not found")
+	}
+
+	return &upload.URL, nil
+}
+
+// publishLastMessage streams the latest message of a conversation to its members
+func (s *Service) publishLastMessage(ctx context.Context, conversationID uuid.UUID) {
+	messages, err := s.repo.GetLastMessages(ctx, []uuid.UUID{conversationID})
+	if err != nil {
+		log.Printf("Last message of conversation %s error: %v", conversationID, err)
+		return
+	}
+	if message, ok := messages[conversationID]; ok {
+		s.publishMessages(ctx, conversationID, []uuid.UUID{message.ID})
+	}
+}
+
+// publishMessages streams system messages to the members of a conversation and
+// to the given former members
+func (s *Service) publishMessages(ctx context.Context, conversationID uuid.UUID, messageIDs []uuid.UUID, formerMemberIDs ...uuid.UUID) {
+	if len(messageIDs) == 0 {
+		return
+	}
+
+	memberIDs, err := s.repo.GetMemberIDs(ctx, conversationID)
+	if err != nil {
+		log.Printf("Members of conversation %s error: %v", conversationID, err)
+		return
+	}
+	recipients := append(memberIDs, formerMemberIDs...)
+
+	for _, messageID := range messageIDs {
+		message, err := s.repo.GetMessage(ctx, messageID)
+		if err != nil {
+			log.Printf("Message %s error: %v", messageID, err)
+			continue
+		}
+		for _, recipientID := range recipients {
+			s.hub.Publish(ctx, realtime.UserTopic(recipientID), realtime.EventMessage, message)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package chat
+
+import (
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+	config  *config.Config
+}
+
+func NewHandler(service *Service, cfg *config.Config) *Handler {
+	return &Handler{service: service, config: cfg}
+}
+
+// GetConversations handles retrieval of the user's conversations, most recent message first
+// GET /api/v1/conversations?cursor=...&limit=20
+func (h *Handler) GetConversations(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	conversations, nextCursor, err := h.service.GetConversations(c.Context(), userID, c.Query("cursor"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid cursor")
+		}
+		log.Printf("Get conversations error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get conversations")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"conversations": conversations,
+		"next_cursor":   nextCursor,
+		"limit":         limit,
+	})
+}
+
+// StartConversation handles opening the direct conversation with a user
+// POST /api/v1/conversations
+func (h *Handler) StartConversation(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.StartConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversation, created, err := h.service.StartConversation(c.Context(), userID, req.Username)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		case "you cannot message yourself":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot message yourself")
+		case "you cannot message this user":
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot message this user")
+		}
+		log.Printf("Start conversation error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to start conversation")
+	}
+
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+
+	return utils.SuccessResponse(c, status, "", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// GetConversation handles retrieval of one of the user's conversations
+// GET /api/v1/conversations/:id
+func (h *Handler) GetConversation(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	conversation, err := h.service.GetConversation(c.Context(), conversationID, userID)
+	if err != nil {
+		if err.Error() == "conversation not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+		}
+		log.Printf("Get conversation error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get conversation")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// GetMessages handles retrieval of a conversation's history, most recent message first
+// GET /api/v1/conversations/:id/messages?cursor=...&limit=30
+func (h *Handler) GetMessages(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	limit := c.QueryInt("limit", 30)
+	if limit < 1 || limit > 100 {
+		limit = 30
+	}
+
+	messages, nextCursor, err := h.service.GetMessages(c.Context(), conversationID, userID, c.Query("cursor"), limit)
+	if err != nil {
+		switch err.Error() {
+		case "invalid cursor":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid cursor")
+		case "conversation not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+		}
+		log.Printf("Get messages error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get messages")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"messages":    messages,
+		"next_cursor": nextCursor,
+		"limit":       limit,
+	})
+}
+
+// SendMessage handles sending a message with text, an uploaded attachment or both
+// POST /api/v1/conversations/:id/messages
+func (h *Handler) SendMessage(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	var req models.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	message, err := h.service.SendMessage(c.Context(), conversationID, userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "message is empty":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Message must have content or an attachment")
+		case "conversation not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+		case "attachment not found":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Attachment not found")
+		case "you cannot message this user", "user not found":
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot message this user")
+		}
+		log.Printf("Send message error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to send message")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Message sent", fiber.Map{
+		"message": message,
+	})
+}
+
+// MarkRead handles marking a conversation as read, up to message_id when given
+// POST /api/v1/conversations/:id/read
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	var req models.MarkReadRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	if err := h.service.MarkRead(c.Context(), conversationID, userID, req.MessageID); err != nil {
+		switch err.Error() {
+		case "conversation not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+		case "message not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Message not found")
+		}
+		log.Printf("Mark conversation read error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to mark conversation as read")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Conversation marked as read", nil)
+}
+
+// GetUnreadCount handles retrieval of the number of unread messages
+// GET /api/v1/conversations/unread-count
+func (h *Handler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	count, err := h.service.GetUnreadCount(c.Context(), userID)
+	if err != nil {
+		log.Printf("Count unread messages error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to count messages")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"unread_count": count,
+	})
+}
+
+// CreateGroup handles creation of a group conversation
+// POST /api/v1/conversations/groups
+func (h *Handler) CreateGroup(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.CreateGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversation, err := h.service.CreateGroup(c.Context(), userID, &req)
+	if err != nil {
+		return groupErrorResponse(c, err, "Create group", "Failed to create group")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Group created successfully", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// UpdateGroup handles renaming a group or changing its avatar
+// PUT /api/v1/conversations/:id
+func (h *Handler) UpdateGroup(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	var req models.UpdateGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversation, err := h.service.UpdateGroup(c.Context(), conversationID, userID, &req)
+	if err != nil {
+		return groupErrorResponse(c, err, "Update group", "Failed to update group")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Group updated successfully", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// AddMembers handles adding users to a group
+// POST /api/v1/conversations/:id/members
+func (h *Handler) AddMembers(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	var req models.AddMembersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversation, err := h.service.AddMembers(c.Context(), conversationID, userID, &req)
+	if err != nil {
+		return groupErrorResponse(c, err, "Add group members", "Failed to add members")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Members added successfully", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// RemoveMember handles removing a member from a group
+// DELETE /api/v1/conversations/:id/members/:username
+func (h *Handler) RemoveMember(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	if err := h.service.RemoveMember(c.Context(), conversationID, userID, c.Params("username")); err != nil {
+		return groupErrorResponse(c, err, "Remove group member", "Failed to remove member")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Member removed successfully", nil)
+}
+
+// UpdateMemberRole handles making a member an admin of a group, or a plain member again
+// PUT /api/v1/conversations/:id/members/:username/role
+func (h *Handler) UpdateMemberRole(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversation, err := h.service.UpdateMemberRole(c.Context(), conversationID, userID, c.Params("username"), req.Role)
+	if err != nil {
+		return groupErrorResponse(c, err, "Update member role", "Failed to update member role")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Member role updated successfully", fiber.Map{
+		"conversation": conversation,
+	})
+}
+
+// LeaveGroup handles the user leaving a group
+// POST /api/v1/conversations/:id/leave
+func (h *Handler) LeaveGroup(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	conversationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid conversation ID")
+	}
+
+	if err := h.service.LeaveGroup(c.Context(), conversationID, userID); err != nil {
+		return groupErrorResponse(c, err, "Leave group", "Failed to leave group")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "You left the group", nil)
+}
+
+// groupErrorResponse maps the errors of group management to responses, logging
+// unexpected ones under the given action
+func groupErrorResponse(c *fiber.Ctx, err error, action, failure string) error {
+	switch err.Error() {
+	case "conversation not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+	case "user not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+	case "member not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Member not found")
+	case "avatar not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Avatar not found")
+	case "not a group conversation":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Not a group conversation")
+	case "group name is empty":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Group name is empty")
+	case "group is full":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Group is full")
+	case "leave the group to remove yourself":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Leave the group to remove yourself")
+	case "group needs an admin":
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Group needs at least one admin")
+	case "only group admins can do this":
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Only group admins can do this")
+	case "you cannot message this user":
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot add this user")
+	}
+	log.Printf("%s error: %v", action, err)
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, failure)
+}
@@ -0,0 +1,234 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/Aolakije/City-Buzz/pkg/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Live connection timing: pings keep idle connections open through proxies,
+// and a connection silent for liveIdleTimeout, pongs included, is dropped
+const (
+	livePingInterval = 25 * time.Second
+	liveIdleTimeout  = 60 * time.Second
+	liveReadLimit    = 4 * 1024
+	liveCommandTime  = 10 * time.Second
+)
+
+// typingThrottle is how often a repeated typing state is passed on
+const typingThrottle = 3 * time.Second
+
+// liveEventTypes are the events of the user's topic relayed to live connections
+var liveEventTypes = map[string]bool{
+	realtime.EventMessage:  true,
+	realtime.EventTyping:   true,
+	realtime.EventReceipt:  true,
+	realtime.EventPresence: true,
+}
+
+// Commands sent by clients over a live connection
+const (
+	commandTyping    = "typing"
+	commandDelivered = "delivered"
+	commandRead      = "read"
+)
+
+// liveCommand is a frame sent by a client. Delivered and read acknowledge the
+// messages of a conversation up to MessageID.
+type liveCommand struct {
+	Type           string     `json:"type"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	MessageID      *uuid.UUID `json:"message_id"`
+	Typing         bool       `json:"typing"`
+}
+
+// typingState is the last typing state passed on for a conversation
+type typingState struct {
+	typing bool
+	at     time.Time
+}
+
+// Live handles the WebSocket connection streaming the user's messages, typing
+// indicators, receipts and the presence of their contacts, and receiving their
+// typing state and receipts
+// GET /api/v1/conversations/live
+func (h *Handler) Live(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	// The session cookie is sent along by any page, so only the frontend may connect
+	if origin := c.Get(fiber.HeaderOrigin); origin != "" && !h.allowedOrigin(origin) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Origin not allowed")
+	}
+
+	upgradeErr := websocket.Upgrade(c, func(conn *websocket.Conn) {
+		h.serveLive(userID, conn)
+	})
+	if upgradeErr != nil {
+		return utils.ErrorResponse(c, upgradeErr.Code, upgradeErr.Message)
+	}
+
+	return nil
+}
+
+// allowedOrigin reports whether a browser origin may open live connections
+func (h *Handler) allowedOrigin(origin string) bool {
+	for _, allowed := range h.config.CORS.AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// serveLive relays the user's chat events to a live connection and handles
+// its commands until either side closes it
+func (h *Handler) serveLive(userID uuid.UUID, conn *websocket.Conn) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn.SetReadLimit(liveReadLimit)
+	conn.SetIdleTimeout(liveIdleTimeout)
+
+	connectionID := uuid.NewString()
+	sub := h.service.Connect(ctx, userID, connectionID)
+	defer h.service.Disconnect(context.Background(), userID, connectionID, sub)
+
+	go func() {
+		defer cancel()
+		h.readCommands(ctx, userID, conn)
+	}()
+
+	ping := time.NewTicker(livePingInterval)
+	defer ping.Stop()
+	refresh := time.NewTicker(realtime.PresenceRefreshInterval)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// The server is shutting down
+				conn.Close(websocket.CloseGoingAway, "server shutting down")
+				return
+			}
+			if event.Type == realtime.EventSuspended {
+				conn.Close(websocket.ClosePolicyViolation, "account suspended")
+				return
+			}
+			if !liveEventTypes[event.Type] {
+				continue
+			}
+			if err := writeLiveEvent(conn, event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.Ping(); err != nil {
+				return
+			}
+		case <-refresh.C:
+			h.service.RefreshPresence(ctx, userID, connectionID)
+		}
+	}
+}
+
+// readCommands handles the commands of a live connection until it closes.
+// Typing indicators still on are turned off when it does.
+func (h *Handler) readCommands(ctx context.Context, userID uuid.UUID, conn *websocket.Conn) {
+	typing := make(map[uuid.UUID]typingState)
+	defer func() {
+		for conversationID, state := range typing {
+			if state.typing {
+				h.service.SetTyping(context.Background(), conversationID, userID, false)
+			}
+		}
+	}()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			conn.Close(websocket.CloseUnsupportedData, "text messages only")
+			return
+		}
+
+		var command liveCommand
+		if err := json.Unmarshal(data, &command); err != nil || command.ConversationID == uuid.Nil {
+			writeLiveError(conn, command.Type, "invalid command")
+			continue
+		}
+
+		if command.Type == commandTyping {
+			state, ok := typing[command.ConversationID]
+			if ok && state.typing == command.Typing && time.Since(state.at) < typingThrottle {
+				continue
+			}
+			typing[command.ConversationID] = typingState{typing: command.Typing, at: time.Now()}
+		}
+
+		if err := h.runCommand(ctx, userID, &command); err != nil {
+			switch err.Error() {
+			case "invalid command", "conversation not found", "message not found", "you cannot message this user", "user not found":
+				writeLiveError(conn, command.Type, err.Error())
+			default:
+				log.Printf("Live %s command error: %v", command.Type, err)
+				writeLiveError(conn, command.Type, "failed to process command")
+			}
+		}
+	}
+}
+
+// runCommand carries out a command of a live connection
+func (h *Handler) runCommand(ctx context.Context, userID uuid.UUID, command *liveCommand) error {
+	ctx, cancel := context.WithTimeout(ctx, liveCommandTime)
+	defer cancel()
+
+	switch command.Type {
+	case commandTyping:
+		return h.service.SetTyping(ctx, command.ConversationID, userID, command.Typing)
+	case commandDelivered:
+		if command.MessageID == nil {
+			return fmt.Errorf("invalid command")
+		}
+		return h.service.MarkDelivered(ctx, command.ConversationID, userID, *command.MessageID)
+	case commandRead:
+		return h.service.MarkRead(ctx, command.ConversationID, userID, command.MessageID)
+	}
+	return fmt.Errorf("invalid command")
+}
+
+// writeLiveEvent sends an event of the user's topic to a live connection
+func writeLiveEvent(conn *websocket.Conn, event realtime.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Realtime %s event on %s encoding error: %v", event.Type, event.Topic, err)
+		return nil
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// writeLiveError tells a live connection that one of its commands failed
+func writeLiveError(conn *websocket.Conn, command, message string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type": "error",
+		"data": map[string]string{
+			"command": command,
+			"error":   message,
+		},
+	})
+	conn.WriteMessage(websocket.TextMessage, data)
+}
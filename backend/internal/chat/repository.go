@@ -0,0 +1,934 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// messageColumns selects a message with its sender, subject and attachment from messageTables
+const messageColumns = `
+	m.id, m.conversation_id, m.kind, m.sender_id, m.subject_id, m.content, m.created_at,
+	u.username, u.first_name, u.last_name, u.avatar_url,
+	s.username, s.first_name, s.last_name, s.avatar_url,
+	a.id, a.url, a.content_type, a.size
+`
+
+const messageTables = `
+	messages m
+	LEFT JOIN users u ON u.id = m.sender_id
+	LEFT JOIN users s ON s.id = m.subject_id
+	LEFT JOIN uploads a ON a.id = m.attachment_id
+`
+
+// conversationColumns selects a conversation with the role and unread count of
+// the member cm
+const conversationColumns = `
+	c.id, c.type, c.name, c.avatar_url, c.event_id, c.last_message_at, c.created_at, cm.role,
+	(SELECT COUNT(*) FROM messages m
+	 WHERE m.conversation_id = c.id AND m.kind = 'text'
+	   AND m.created_at > COALESCE(cm.last_read_at, '-infinity')
+	   AND m.sender_id IS DISTINCT FROM cm.user_id)
+`
+
+// directKey identifies the direct conversation of two users, whatever their order
+func directKey(userID, otherID uuid.UUID) string {
+	a, b := userID.String(), otherID.String()
+	if a > b {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+// GetOrCreateDirect returns the direct conversation between two users, creating
+// it when they never talked. It reports whether the conversation is new.
+func (r *Repository) GetOrCreateDirect(ctx context.Context, userID, otherID uuid.UUID) (uuid.UUID, bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	key := directKey(userID, otherID)
+
+	var conversationID uuid.UUID
+	query := `
+		INSERT INTO conversations (type, direct_key)
+		VALUES ($1, $2)
+		ON CONFLICT (direct_key) DO NOTHING
+		RETURNING id
+	`
+	err = tx.QueryRow(ctx, query, models.ConversationDirect, key).Scan(&conversationID)
+	if err == pgx.ErrNoRows {
+		err = tx.QueryRow(ctx, `SELECT id FROM conversations WHERE direct_key = $1`, key).Scan(&conversationID)
+		if err != nil {
+			return uuid.Nil, false, fmt.Errorf("failed to get conversation: %w", err)
+		}
+		return conversationID, false, nil
+	}
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	memberQuery := `
+		INSERT INTO conversation_members (conversation_id, user_id)
+		VALUES ($1, $2), ($1, $3)
+	`
+	if _, err := tx.Exec(ctx, memberQuery, conversationID, userID, otherID); err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to add conversation members: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to commit conversation: %w", err)
+	}
+
+	return conversationID, true, nil
+}
+
+// GetConversation retrieves one of the user's conversations with their role and unread count
+func (r *Repository) GetConversation(ctx context.Context, conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN conversation_members cm ON cm.conversation_id = c.id AND cm.user_id = $2
+		WHERE c.id = $1
+	`
+
+	conversations, err := r.queryConversations(ctx, query, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(conversations) == 0 {
+		return nil, fmt.Errorf("conversation not found")
+	}
+
+	return &conversations[0], nil
+}
+
+// GetConversations retrieves the user's conversations that have messages, most
+// recent message first, starting after the (lastMessageAt, id) position of
+// before when it is set
+func (r *Repository) GetConversations(ctx context.Context, userID uuid.UUID, before *utils.Cursor, limit int) ([]models.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN conversation_members cm ON cm.conversation_id = c.id AND cm.user_id = $1
+		WHERE c.last_message_at IS NOT NULL
+		  AND ($2::timestamp IS NULL OR (c.last_message_at, c.id) < ($2::timestamp, $3::uuid))
+		ORDER BY c.last_message_at DESC, c.id DESC
+		LIMIT $4
+	`
+
+	var beforeAt interface{}
+	beforeID := uuid.Nil
+	if before != nil {
+		beforeAt, beforeID = before.At, before.ID
+	}
+
+	return r.queryConversations(ctx, query, userID, beforeAt, beforeID, limit)
+}
+
+// queryConversations runs a query selecting conversationColumns and scans the results
+func (r *Repository) queryConversations(ctx context.Context, query string, args ...interface{}) ([]models.Conversation, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		var c models.Conversation
+		err := rows.Scan(&c.ID, &c.Type, &c.Name, &c.AvatarURL, &c.EventID, &c.LastMessageAt, &c.CreatedAt,
+			&c.Role, &c.UnreadCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		conversations = append(conversations, c)
+	}
+
+	return conversations, rows.Err()
+}
+
+// GetMembers retrieves the active members of the given conversations, oldest first
+func (r *Repository) GetMembers(ctx context.Context, conversationIDs []uuid.UUID) (map[uuid.UUID][]models.ConversationMember, error) {
+	query := `
+		SELECT cm.conversation_id, u.id, u.username, u.first_name, u.last_name, u.avatar_url, cm.role, cm.joined_at
+		FROM conversation_members cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.conversation_id = ANY($1) AND u.is_active = true
+		ORDER BY cm.joined_at, u.username
+	`
+
+	rows, err := r.db.Query(ctx, query, conversationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation members: %w", err)
+	}
+	defer rows.Close()
+
+	members := make(map[uuid.UUID][]models.ConversationMember)
+	for rows.Next() {
+		var conversationID uuid.UUID
+		var m models.ConversationMember
+		err := rows.Scan(&conversationID, &m.ID, &m.Username, &m.FirstName, &m.LastName, &m.AvatarURL, &m.Role, &m.JoinedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conversation member: %w", err)
+		}
+		members[conversationID] = append(members[conversationID], m)
+	}
+
+	return members, rows.Err()
+}
+
+// GetMemberIDs retrieves the IDs of the members of a conversation
+func (r *Repository) GetMemberIDs(ctx context.Context, conversationID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `SELECT user_id FROM conversation_members WHERE conversation_id = $1`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation members: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation member: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetLastMessages retrieves the latest message of each of the given conversations
+func (r *Repository) GetLastMessages(ctx context.Context, conversationIDs []uuid.UUID) (map[uuid.UUID]*models.Message, error) {
+	query := `
+		SELECT DISTINCT ON (m.conversation_id) ` + messageColumns + `
+		FROM ` + messageTables + `
+		WHERE m.conversation_id = ANY($1)
+		ORDER BY m.conversation_id, m.created_at DESC, m.id DESC
+	`
+
+	messages, err := r.queryMessages(ctx, query, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	last := make(map[uuid.UUID]*models.Message, len(messages))
+	for i := range messages {
+		last[messages[i].ConversationID] = &messages[i]
+	}
+	return last, nil
+}
+
+// GetMessages retrieves the messages of a conversation, most recent first,
+// starting after the (createdAt, id) position of before when it is set
+func (r *Repository) GetMessages(ctx context.Context, conversationID uuid.UUID, before *utils.Cursor, limit int) ([]models.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM ` + messageTables + `
+		WHERE m.conversation_id = $1
+		  AND ($2::timestamp IS NULL OR (m.created_at, m.id) < ($2::timestamp, $3::uuid))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $4
+	`
+
+	var beforeAt interface{}
+	beforeID := uuid.Nil
+	if before != nil {
+		beforeAt, beforeID = before.At, before.ID
+	}
+
+	return r.queryMessages(ctx, query, conversationID, beforeAt, beforeID, limit)
+}
+
+// GetMessage retrieves a message with its sender and attachment
+func (r *Repository) GetMessage(ctx context.Context, messageID uuid.UUID) (*models.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM ` + messageTables + `
+		WHERE m.id = $1
+	`
+
+	messages, err := r.queryMessages(ctx, query, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	return &messages[0], nil
+}
+
+// queryMessages runs a query selecting messageColumns and scans the results
+func (r *Repository) queryMessages(ctx context.Context, query string, args ...interface{}) ([]models.Message, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.Message{}
+	for rows.Next() {
+		var m models.Message
+		var sender, subject scannedUser
+		var attachmentID *uuid.UUID
+		var attachmentURL, contentType *string
+		var size *int64
+
+		err := rows.Scan(&m.ID, &m.ConversationID, &m.Kind, &m.SenderID, &m.SubjectID, &m.Content, &m.CreatedAt,
+			&sender.Username, &sender.FirstName, &sender.LastName, &sender.AvatarURL,
+			&subject.Username, &subject.FirstName, &subject.LastName, &subject.AvatarURL,
+			&attachmentID, &attachmentURL, &contentType, &size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+
+		m.Sender = sender.toResponse(m.SenderID)
+		m.Subject = subject.toResponse(m.SubjectID)
+		if attachmentID != nil {
+			m.Attachment = &models.MessageAttachment{
+				ID:          *attachmentID,
+				URL:         *attachmentURL,
+				ContentType: *contentType,
+				Size:        *size,
+			}
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+// scannedUser holds the columns of a user LEFT JOINed to a message
+type scannedUser struct {
+	Username, FirstName, LastName, AvatarURL *string
+}
+
+// toResponse returns the user, or nil when the join found no one
+func (u *scannedUser) toResponse(id *uuid.UUID) *models.UserResponse {
+	if id == nil || u.Username == nil {
+		return nil
+	}
+	return &models.UserResponse{
+		ID:        *id,
+		Username:  *u.Username,
+		FirstName: *u.FirstName,
+		LastName:  *u.LastName,
+		AvatarURL: u.AvatarURL,
+	}
+}
+
+// CreateMessage saves a message and moves its conversation to the top of the
+// members' lists. Sending a message also marks the conversation as read for
+// its sender.
+func (r *Repository) CreateMessage(ctx context.Context, message *models.Message, attachmentID *uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO messages (conversation_id, sender_id, content, attachment_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, message.ConversationID, message.SenderID, message.Content, attachmentID).
+		Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+
+	conversationQuery := `UPDATE conversations SET last_message_at = $2 WHERE id = $1`
+	if _, err := tx.Exec(ctx, conversationQuery, message.ConversationID, message.CreatedAt); err != nil {
+		return fmt.Errorf("failed to update conversation: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+
+	return nil
+}
+
+// receipt is a receipt just recorded for a message of SenderID, nil once the
+// sender's account is deleted
+type receipt struct {
+	MessageID uuid.UUID
+	SenderID  *uuid.UUID
+	At        time.Time
+}
+
+// receiptTargets selects the messages of conversation $1 that user $2 received
+// after time $3 and up to time $4, other than their own
+const receiptTargets = `
+	SELECT m.id, m.sender_id
+	FROM messages m
+	WHERE m.conversation_id = $1 AND m.kind = 'text'
+	  AND m.sender_id IS DISTINCT FROM $2
+	  AND m.created_at > $3 AND m.created_at <= $4
+`
+
+// MarkDelivered records that the user received the messages of a conversation
+// sent up to the given time. Messages already read were received, so only the
+// unread ones are considered. It returns the receipts recorded.
+func (r *Repository) MarkDelivered(ctx context.Context, conversationID, userID uuid.UUID, upTo time.Time) ([]receipt, error) {
+	var since time.Time
+	query := `SELECT COALESCE(last_read_at, joined_at) FROM conversation_members WHERE conversation_id = $1 AND user_id = $2`
+	if err := r.db.QueryRow(ctx, query, conversationID, userID).Scan(&since); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("conversation not found")
+		}
+		return nil, fmt.Errorf("failed to get read position: %w", err)
+	}
+
+	query = `
+		WITH target AS (` + receiptTargets + `),
+		inserted AS (
+			INSERT INTO message_receipts (message_id, user_id)
+			SELECT id, $2 FROM target
+			ON CONFLICT (message_id, user_id) DO NOTHING
+			RETURNING message_id, delivered_at
+		)
+		SELECT i.message_id, t.sender_id, i.delivered_at
+		FROM inserted i
+		JOIN target t ON t.id = i.message_id
+	`
+
+	return queryReceipts(ctx, r.db, query, conversationID, userID, since, upTo)
+}
+
+// MarkRead records that the user read the messages of a conversation sent up
+// to the given time, or all of them when upTo is nil, and moves their read
+// position there. It returns the receipts recorded.
+func (r *Repository) MarkRead(ctx context.Context, conversationID, userID uuid.UUID, upTo *time.Time) ([]receipt, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the member row keeps concurrent reads from moving the position back
+	var since time.Time
+	var lastMessageAt *time.Time
+	query := `
+		SELECT COALESCE(cm.last_read_at, cm.joined_at), c.last_message_at
+		FROM conversation_members cm
+		JOIN conversations c ON c.id = cm.conversation_id
+		WHERE cm.conversation_id = $1 AND cm.user_id = $2
+		FOR UPDATE OF cm
+	`
+	if err := tx.QueryRow(ctx, query, conversationID, userID).Scan(&since, &lastMessageAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("conversation not found")
+		}
+		return nil, fmt.Errorf("failed to get read position: %w", err)
+	}
+
+	if upTo == nil {
+		upTo = lastMessageAt
+	}
+	if upTo == nil || !upTo.After(since) {
+		return nil, nil
+	}
+
+	query = `
+		WITH target AS (` + receiptTargets + `),
+		upserted AS (
+			INSERT INTO message_receipts (message_id, user_id, read_at)
+			SELECT id, $2, NOW() FROM target
+			ON CONFLICT (message_id, user_id) DO UPDATE SET read_at = EXCLUDED.read_at
+			WHERE message_receipts.read_at IS NULL
+			RETURNING message_id, read_at
+		)
+		SELECT u.message_id, t.sender_id, u.read_at
+		FROM upserted u
+		JOIN target t ON t.id = u.message_id
+	`
+	receipts, err := queryReceipts(ctx, tx, query, conversationID, userID, since, *upTo)
+	if err != nil {
+		return nil, err
+	}
+
+	positionQuery := `UPDATE conversation_members SET last_read_at = $3 WHERE conversation_id = $1 AND user_id = $2`
+	if _, err := tx.Exec(ctx, positionQuery, conversationID, userID, *upTo); err != nil {
+		return nil, fmt.Errorf("failed to mark conversation as read: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit read position: %w", err)
+	}
+
+	return receipts, nil
+}
+
+// querier runs queries on the pool or within a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// queryReceipts runs a query selecting message IDs, sender IDs and times
+func queryReceipts(ctx context.Context, db querier, query string, args ...interface{}) ([]receipt, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var receipts []receipt
+	for rows.Next() {
+		var rc receipt
+		if err := rows.Scan(&rc.MessageID, &rc.SenderID, &rc.At); err != nil {
+			return nil, fmt.Errorf("failed to scan receipt: %w", err)
+		}
+		receipts = append(receipts, rc)
+	}
+
+	return receipts, rows.Err()
+}
+
+// GetReceipts retrieves the receipts of the given messages
+func (r *Repository) GetReceipts(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]models.MessageReceipt, error) {
+	query := `
+		SELECT message_id, user_id, delivered_at, read_at
+		FROM message_receipts
+		WHERE message_id = ANY($1)
+		ORDER BY delivered_at
+	`
+
+	rows, err := r.db.Query(ctx, query, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+	defer rows.Close()
+
+	receipts := make(map[uuid.UUID][]models.MessageReceipt)
+	for rows.Next() {
+		var messageID uuid.UUID
+		var rc models.MessageReceipt
+		if err := rows.Scan(&messageID, &rc.UserID, &rc.DeliveredAt, &rc.ReadAt); err != nil {
+			return nil, fmt.Errorf("failed to scan receipt: %w", err)
+		}
+		receipts[messageID] = append(receipts[messageID], rc)
+	}
+
+	return receipts, rows.Err()
+}
+
+// GetContactIDs retrieves the users sharing a conversation with the user
+func (r *Repository) GetContactIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT other.user_id
+		FROM conversation_members cm
+		JOIN conversation_members other ON other.conversation_id = cm.conversation_id
+		WHERE cm.user_id = $1 AND other.user_id <> $1
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan contact: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetBlockedIDs retrieves the users who blocked the user or were blocked by them
+func (r *Repository) GetBlockedIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocked := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocked[id] = true
+	}
+
+	return blocked, rows.Err()
+}
+
+// CountUnread counts the messages the user has not read across their conversations
+func (r *Repository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM conversation_members cm
+		JOIN messages m ON m.conversation_id = cm.conversation_id
+		WHERE cm.user_id = $1 AND m.kind = 'text'
+		  AND m.created_at > COALESCE(cm.last_read_at, '-infinity')
+		  AND m.sender_id IS DISTINCT FROM cm.user_id
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetUpload retrieves an uploaded file
+func (r *Repository) GetUpload(ctx context.Context, uploadID uuid.UUID) (*models.Upload, error) {
+	query := `
+		SELECT id, user_id, url, filename, content_type, size, created_at
+		FROM uploads
+		WHERE id = $1
+	`
+
+	var u models.Upload
+	err := r.db.QueryRow(ctx, query, uploadID).Scan(&u.ID, &u.UserID, &u.URL, &u.Filename, &u.ContentType, &u.Size, &u.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("upload not found")
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	return &u, nil
+}
+
+// addSystemMessage records a change made by sender to a conversation in its
+// history and returns the ID of the message
+func addSystemMessage(ctx context.Context, tx pgx.Tx, conversationID uuid.UUID, kind string, senderID, subjectID *uuid.UUID, content *string) (uuid.UUID, error) {
+	var messageID uuid.UUID
+	query := `
+		WITH message AS (
+			INSERT INTO messages (conversation_id, kind, sender_id, subject_id, content)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		)
+		UPDATE conversations c SET last_message_at = message.created_at
+		FROM message
+		WHERE c.id = $1
+		RETURNING message.id
+	`
+	if err := tx.QueryRow(ctx, query, conversationID, kind, senderID, subjectID, content).Scan(&messageID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save system message: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// CreateGroup creates a group conversation with its creator as admin and the
+// given members, and records its creation. A group with an EventID belongs to
+// that event.
+func (r *Repository) CreateGroup(ctx context.Context, group *models.Conversation, creatorID uuid.UUID, memberIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO conversations (type, name, avatar_url, created_by, event_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, models.ConversationGroup, group.Name, group.AvatarURL, creatorID, group.EventID).
+		Scan(&group.ID, &group.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("event already has a group")
+		}
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	memberQuery := `
+		INSERT INTO conversation_members (conversation_id, user_id, role, last_read_at)
+		SELECT $1, unnest($2::uuid[]), $3, NOW()
+		ON CONFLICT (conversation_id, user_id) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, memberQuery, group.ID, []uuid.UUID{creatorID}, models.ConversationRoleAdmin); err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+	if _, err := tx.Exec(ctx, memberQuery, group.ID, memberIDs, models.ConversationRoleMember); err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+
+	if _, err := addSystemMessage(ctx, tx, group.ID, models.MessageKindGroupCreated, &creatorID, nil, group.Name); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit group: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateGroup renames a group and changes its avatar, keeping the current
+// values for nil arguments, and records the change
+func (r *Repository) UpdateGroup(ctx context.Context, conversationID, actorID uuid.UUID, name, avatarURL *string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE conversations
+		SET name = COALESCE($2, name), avatar_url = COALESCE($3, avatar_url)
+		WHERE id = $1 AND type = 'group'
+		RETURNING name
+	`
+	var newName *string
+	if err := tx.QueryRow(ctx, query, conversationID, name, avatarURL).Scan(&newName); err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, fmt.Errorf("conversation not found")
+		}
+		return uuid.Nil, fmt.Errorf("failed to update group: %w", err)
+	}
+
+	messageID, err := addSystemMessage(ctx, tx, conversationID, models.MessageKindGroupUpdated, &actorID, nil, newName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit group: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// AddMembers adds users to a group and records each addition under the given
+// kind. Users already in the group are skipped. It returns the IDs of the
+// system messages.
+func (r *Repository) AddMembers(ctx context.Context, conversationID, actorID uuid.UUID, userIDs []uuid.UUID, kind string) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// New members start with the history read
+	query := `
+		INSERT INTO conversation_members (conversation_id, user_id, last_read_at)
+		SELECT $1, unnest($2::uuid[]), NOW()
+		ON CONFLICT (conversation_id, user_id) DO NOTHING
+		RETURNING user_id
+	`
+	rows, err := tx.Query(ctx, query, conversationID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add members: %w", err)
+	}
+	var added []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		added = append(added, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to add members: %w", err)
+	}
+
+	messageIDs := make([]uuid.UUID, 0, len(added))
+	for _, id := range added {
+		subjectID := id
+		messageID, err := addSystemMessage(ctx, tx, conversationID, kind, &actorID, &subjectID, nil)
+		if err != nil {
+			return nil, err
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit members: %w", err)
+	}
+
+	return messageIDs, nil
+}
+
+// RemoveMember takes a user out of a group and records it under the given
+// kind. When the last admin goes, the longest-standing member becomes admin;
+// when the last member goes, the group is deleted. It returns the ID of the
+// system message, or uuid.Nil once the group is deleted.
+func (r *Repository) RemoveMember(ctx context.Context, conversationID, actorID, userID uuid.UUID, kind string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the group serializes membership changes, so that two admins
+	// leaving at once cannot leave it without one
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM conversations WHERE id = $1 FOR UPDATE`, conversationID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to lock group: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM conversation_members WHERE conversation_id = $1 AND user_id = $2`, conversationID, userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to remove member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return uuid.Nil, fmt.Errorf("member not found")
+	}
+
+	var remaining int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM conversation_members WHERE conversation_id = $1`, conversationID).Scan(&remaining)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	messageID := uuid.Nil
+	if remaining == 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM conversations WHERE id = $1`, conversationID); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to delete group: %w", err)
+		}
+	} else {
+		promoteQuery := `
+			UPDATE conversation_members SET role = 'admin'
+			WHERE conversation_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM conversation_members WHERE conversation_id = $1 AND role = 'admin')
+			  AND user_id = (SELECT user_id FROM conversation_members WHERE conversation_id = $1
+			                 ORDER BY joined_at, user_id LIMIT 1)
+		`
+		if _, err := tx.Exec(ctx, promoteQuery, conversationID); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to promote admin: %w", err)
+		}
+
+		messageID, err = addSystemMessage(ctx, tx, conversationID, kind, &actorID, &userID, nil)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit member removal: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// SetRole changes the role of a member of a group and records the change
+func (r *Repository) SetRole(ctx context.Context, conversationID, actorID, userID uuid.UUID, role string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM conversations WHERE id = $1 FOR UPDATE`, conversationID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to lock group: %w", err)
+	}
+
+	query := `
+		UPDATE conversation_members SET role = $3
+		WHERE conversation_id = $1 AND user_id = $2 AND role <> $3
+	`
+	result, err := tx.Exec(ctx, query, conversationID, userID, role)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to change role: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		// Not a member, or already in that role
+		return uuid.Nil, nil
+	}
+
+	// A group always keeps an admin
+	var admins int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM conversation_members WHERE conversation_id = $1 AND role = 'admin'`, conversationID).Scan(&admins)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+	if admins == 0 {
+		return uuid.Nil, fmt.Errorf("group needs an admin")
+	}
+
+	messageID, err := addSystemMessage(ctx, tx, conversationID, models.MessageKindRoleChanged, &actorID, &userID, &role)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit role: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// IsMember reports whether a user is a member of a conversation
+func (r *Repository) IsMember(ctx context.Context, conversationID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM conversation_members WHERE conversation_id = $1 AND user_id = $2)`
+
+	var member bool
+	if err := r.db.QueryRow(ctx, query, conversationID, userID).Scan(&member); err != nil {
+		return false, fmt.Errorf("failed to check membership: %w", err)
+	}
+
+	return member, nil
+}
+
+// CountMembers counts the members of a conversation
+func (r *Repository) CountMembers(ctx context.Context, conversationID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM conversation_members WHERE conversation_id = $1`, conversationID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetEventGroupID retrieves the ID of an event's group, uuid.Nil when it has none
+func (r *Repository) GetEventGroupID(ctx context.Context, eventID uuid.UUID) (uuid.UUID, error) {
+	var conversationID uuid.UUID
+	err := r.db.QueryRow(ctx, `SELECT id FROM conversations WHERE event_id = $1`, eventID).Scan(&conversationID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, fmt.Errorf("failed to get event group: %w", err)
+	}
+
+	return conversationID, nil
+}
+
+// isUniqueViolation reports whether err comes from a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
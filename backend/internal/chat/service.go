@@ -0,0 +1,473 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/internal/user"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo     *Repository
+	users    *user.Service
+	hub      *realtime.Hub
+	presence *realtime.Presence
+}
+
+func NewService(repo *Repository, users *user.Service, hub *realtime.Hub, presence *realtime.Presence) *Service {
+	return &Service{repo: repo, users: users, hub: hub, presence: presence}
+}
+
+// StartConversation returns the user's direct conversation with the account
+// with the given username, creating it if needed, provided that account
+// accepts their messages. It reports whether the conversation is new.
+func (s *Service) StartConversation(ctx context.Context, userID uuid.UUID, username string) (*models.Conversation, bool, error) {
+	otherID, err := s.users.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if otherID == userID {
+		return nil, false, fmt.Errorf("you cannot message yourself")
+	}
+
+	if err := s.users.CanMessage(ctx, userID, otherID); err != nil {
+		return nil, false, err
+	}
+
+	conversationID, created, err := s.repo.GetOrCreateDirect(ctx, userID, otherID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	conversation, err := s.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return conversation, created, nil
+}
+
+// GetConversations retrieves a page of the user's conversations, most recent
+// message first, and the cursor of the next page, empty on the last page
+func (s *Service) GetConversations(ctx context.Context, userID uuid.UUID, rawCursor string, limit int) ([]models.Conversation, string, error) {
+	before, err := utils.ParseCursor(rawCursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// One more row than asked tells whether there is a next page
+	conversations, err := s.repo.GetConversations(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(conversations) > limit {
+		conversations = conversations[:limit]
+		last := conversations[limit-1]
+		nextCursor = utils.EncodeCursor(&utils.Cursor{At: *last.LastMessageAt, ID: last.ID})
+	}
+
+	if err := s.attachDetails(ctx, userID, conversations); err != nil {
+		return nil, "", err
+	}
+
+	return conversations, nextCursor, nil
+}
+
+// GetConversation retrieves one of the user's conversations
+func (s *Service) GetConversation(ctx context.Context, conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	conversation, err := s.repo.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := []models.Conversation{*conversation}
+	if err := s.attachDetails(ctx, userID, conversations); err != nil {
+		return nil, err
+	}
+
+	return &conversations[0], nil
+}
+
+// GetMessages retrieves a page of a conversation's messages, most recent
+// first, and the cursor of the next (older) page, empty on the last page
+func (s *Service) GetMessages(ctx context.Context, conversationID, userID uuid.UUID, rawCursor string, limit int) ([]models.Message, string, error) {
+	before, err := utils.ParseCursor(rawCursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := s.repo.GetConversation(ctx, conversationID, userID); err != nil {
+		return nil, "", err
+	}
+
+	messages, err := s.repo.GetMessages(ctx, conversationID, before, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(messages) > limit {
+		messages = messages[:limit]
+		last := messages[limit-1]
+		nextCursor = utils.EncodeCursor(&utils.Cursor{At: last.CreatedAt, ID: last.ID})
+	}
+
+	if err := s.attachReceipts(ctx, userID, messages); err != nil {
+		return nil, "", err
+	}
+
+	return messages, nextCursor, nil
+}
+
+// attachReceipts loads the receipts of the messages the user sent
+func (s *Service) attachReceipts(ctx context.Context, userID uuid.UUID, messages []models.Message) error {
+	var ids []uuid.UUID
+	for _, m := range messages {
+		if m.SenderID != nil && *m.SenderID == userID && m.Kind == models.MessageKindText {
+			ids = append(ids, m.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	receipts, err := s.repo.GetReceipts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		messages[i].Receipts = receipts[messages[i].ID]
+	}
+
+	return nil
+}
+
+// SendMessage sends a message to one of the user's conversations and streams
+// it to the members. In a direct conversation, the other user must still
+// accept the sender's messages.
+func (s *Service) SendMessage(ctx context.Context, conversationID, userID uuid.UUID, req *models.SendMessageRequest) (*models.Message, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" && req.AttachmentID == nil {
+		return nil, fmt.Errorf("message is empty")
+	}
+
+	conversation, err := s.repo.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.repo.GetMemberIDs(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if conversation.Type == models.ConversationDirect {
+		if err := s.checkDirectRecipient(ctx, userID, memberIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.AttachmentID != nil {
+		upload, err := s.repo.GetUpload(ctx, *req.AttachmentID)
+		if err != nil || upload.UserID != userID {
+			return nil, fmt.Errorf("attachment not found")
+		}
+	}
+
+	message := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       &userID,
+	}
+	if content != "" {
+		message.Content = &content
+	}
+
+	if err := s.repo.CreateMessage(ctx, message, req.AttachmentID); err != nil {
+		return nil, err
+	}
+
+	message, err = s.repo.GetMessage(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, memberID := range memberIDs {
+		s.hub.Publish(ctx, realtime.UserTopic(memberID), realtime.EventMessage, message)
+	}
+
+	// Replying reads what came before
+	if err := s.markRead(ctx, conversationID, userID, &message.CreatedAt); err != nil {
+		log.Printf("Mark conversation %s read error: %v", conversationID, err)
+	}
+
+	return message, nil
+}
+
+// checkDirectRecipient checks that the other member of a direct conversation
+// still accepts the sender's messages
+func (s *Service) checkDirectRecipient(ctx context.Context, senderID uuid.UUID, memberIDs []uuid.UUID) error {
+	for _, memberID := range memberIDs {
+		if memberID != senderID {
+			return s.users.CanMessage(ctx, senderID, memberID)
+		}
+	}
+
+	// The other user deleted their account
+	return fmt.Errorf("you cannot message this user")
+}
+
+// MarkRead marks one of the user's conversations as read up to the given
+// message, or entirely when messageID is nil, and tells the senders
+func (s *Service) MarkRead(ctx context.Context, conversationID, userID uuid.UUID, messageID *uuid.UUID) error {
+	var upTo *time.Time
+	if messageID != nil {
+		message, err := s.conversationMessage(ctx, conversationID, *messageID)
+		if err != nil {
+			return err
+		}
+		upTo = &message.CreatedAt
+	}
+
+	return s.markRead(ctx, conversationID, userID, upTo)
+}
+
+func (s *Service) markRead(ctx context.Context, conversationID, userID uuid.UUID, upTo *time.Time) error {
+	receipts, err := s.repo.MarkRead(ctx, conversationID, userID, upTo)
+	if err != nil {
+		return err
+	}
+
+	s.publishReceipts(ctx, conversationID, userID, models.ReceiptRead, receipts)
+	return nil
+}
+
+// MarkDelivered records that the user received the messages of a conversation
+// up to the given one, and tells the senders
+func (s *Service) MarkDelivered(ctx context.Context, conversationID, userID, messageID uuid.UUID) error {
+	message, err := s.conversationMessage(ctx, conversationID, messageID)
+	if err != nil {
+		return err
+	}
+
+	receipts, err := s.repo.MarkDelivered(ctx, conversationID, userID, message.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	s.publishReceipts(ctx, conversationID, userID, models.ReceiptDelivered, receipts)
+	return nil
+}
+
+// conversationMessage retrieves a message, which must belong to the conversation
+func (s *Service) conversationMessage(ctx context.Context, conversationID, messageID uuid.UUID) (*models.Message, error) {
+	message, err := s.repo.GetMessage(ctx, messageID)
+	if err != nil || message.ConversationID != conversationID {
+		return nil, fmt.Errorf("message not found")
+	}
+	return message, nil
+}
+
+// publishReceipts streams new receipts of a member to the senders of the
+// messages, except those they blocked or were blocked by
+func (s *Service) publishReceipts(ctx context.Context, conversationID, userID uuid.UUID, status string, receipts []receipt) {
+	if len(receipts) == 0 {
+		return
+	}
+
+	blocked, err := s.repo.GetBlockedIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Blocks of user %s error: %v", userID, err)
+		return
+	}
+
+	updates := make(map[uuid.UUID]*models.ReceiptUpdate)
+	for _, rc := range receipts {
+		if rc.SenderID == nil || blocked[*rc.SenderID] {
+			continue
+		}
+		update, ok := updates[*rc.SenderID]
+		if !ok {
+			update = &models.ReceiptUpdate{
+				ConversationID: conversationID,
+				UserID:         userID,
+				Status:         status,
+				At:             rc.At,
+			}
+			updates[*rc.SenderID] = update
+		}
+		update.MessageIDs = append(update.MessageIDs, rc.MessageID)
+	}
+
+	for senderID, update := range updates {
+		s.hub.Publish(ctx, realtime.UserTopic(senderID), realtime.EventReceipt, update)
+	}
+}
+
+// SetTyping tells the other members of a conversation that the user started
+// or stopped typing. In a direct conversation, the other user must still
+// accept the user's messages.
+func (s *Service) SetTyping(ctx context.Context, conversationID, userID uuid.UUID, typing bool) error {
+	conversation, err := s.repo.GetConversation(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	memberIDs, err := s.repo.GetMemberIDs(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	if conversation.Type == models.ConversationDirect {
+		if err := s.checkDirectRecipient(ctx, userID, memberIDs); err != nil {
+			return err
+		}
+	}
+
+	for _, memberID := range memberIDs {
+		if memberID == userID {
+			continue
+		}
+		s.hub.Publish(ctx, realtime.UserTopic(memberID), realtime.EventTyping, map[string]interface{}{
+			"conversation_id": conversationID,
+			"user_id":         userID,
+			"typing":          typing,
+		})
+	}
+
+	return nil
+}
+
+// Connect starts streaming the user's chat events to a live connection and
+// records them as online, telling their contacts if they just came online
+func (s *Service) Connect(ctx context.Context, userID uuid.UUID, connectionID string) *realtime.Subscription {
+	sub := s.hub.Subscribe(realtime.UserTopic(userID))
+
+	online, err := s.presence.Connect(ctx, userID, connectionID)
+	if err != nil {
+		log.Printf("Presence of user %s error: %v", userID, err)
+	} else if online {
+		s.publishPresence(ctx, userID, realtime.PresenceStatus{Online: true})
+	}
+
+	return sub
+}
+
+// RefreshPresence keeps a live connection of the user counted as online
+func (s *Service) RefreshPresence(ctx context.Context, userID uuid.UUID, connectionID string) {
+	if err := s.presence.Refresh(ctx, userID, connectionID); err != nil {
+		log.Printf("Presence of user %s error: %v", userID, err)
+	}
+}
+
+// Disconnect ends a live connection, telling the user's contacts when it was
+// their last one
+func (s *Service) Disconnect(ctx context.Context, userID uuid.UUID, connectionID string, sub *realtime.Subscription) {
+	s.hub.Unsubscribe(sub)
+
+	offline, at, err := s.presence.Disconnect(ctx, userID, connectionID)
+	if err != nil {
+		log.Printf("Presence of user %s error: %v", userID, err)
+	} else if offline {
+		s.publishPresence(ctx, userID, realtime.PresenceStatus{LastSeenAt: &at})
+	}
+}
+
+// publishPresence streams a user's presence to the users sharing a
+// conversation with them, except those they blocked or were blocked by
+func (s *Service) publishPresence(ctx context.Context, userID uuid.UUID, status realtime.PresenceStatus) {
+	contactIDs, err := s.repo.GetContactIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Contacts of user %s error: %v", userID, err)
+		return
+	}
+
+	blocked, err := s.repo.GetBlockedIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Blocks of user %s error: %v", userID, err)
+		return
+	}
+
+	for _, contactID := range contactIDs {
+		if blocked[contactID] {
+			continue
+		}
+		s.hub.Publish(ctx, realtime.UserTopic(contactID), realtime.EventPresence, map[string]interface{}{
+			"user_id":      userID,
+			"online":       status.Online,
+			"last_seen_at": status.LastSeenAt,
+		})
+	}
+}
+
+// GetUnreadCount counts the messages the user has not read
+func (s *Service) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// attachDetails loads the other members and the last message of the conversations
+func (s *Service) attachDetails(ctx context.Context, userID uuid.UUID, conversations []models.Conversation) error {
+	if len(conversations) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(conversations))
+	for i, c := range conversations {
+		ids[i] = c.ID
+	}
+
+	members, err := s.repo.GetMembers(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	lastMessages, err := s.repo.GetLastMessages(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	var memberIDs []uuid.UUID
+	for _, list := range members {
+		for _, member := range list {
+			memberIDs = append(memberIDs, member.ID)
+		}
+	}
+
+	// Conversations still load when presence is unavailable
+	presence, err := s.presence.Get(ctx, memberIDs)
+	if err != nil {
+		log.Printf("Presence of conversation members error: %v", err)
+	}
+
+	blocked, err := s.repo.GetBlockedIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range conversations {
+		conversations[i].Members = []models.ConversationMember{}
+		for _, member := range members[conversations[i].ID] {
+			if member.ID != userID {
+				// Users who blocked one another don't see each other's presence
+				if !blocked[member.ID] {
+					status := presence[member.ID]
+					member.Online, member.LastSeenAt = status.Online, status.LastSeenAt
+				}
+				conversations[i].Members = append(conversations[i].Members, member)
+			}
+		}
+		conversations[i].LastMessage = lastMessages[conversations[i].ID]
+	}
+
+	return nil
+}
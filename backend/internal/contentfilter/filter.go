@@ -0,0 +1,84 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Kinds of content run through the filters
+const (
+	KindPost    = "post"
+	KindComment = "comment"
+	KindEvent   = "event"
+)
+
+// Content is a piece of user content about to be published
+type Content struct {
+	AuthorID uuid.UUID
+	ItemID   uuid.UUID // Set when an existing item is re-screened after an edit
+	Kind     string
+	Text     string
+}
+
+// Result is the outcome of running content through the pipeline
+type Result struct {
+	Held    bool
+	Reasons []string
+}
+
+// Check inspects content and returns a reason when it should be held for
+// review, or an empty string when it looks fine
+type Check interface {
+	Check(ctx context.Context, content *Content) (string, error)
+}
+
+// Pipeline runs content through a list of checks
+type Pipeline struct {
+	checks []Check
+}
+
+// NewPipeline creates a pipeline running the given checks in order
+func NewPipeline(checks ...Check) *Pipeline {
+	return &Pipeline{checks: checks}
+}
+
+// NewDefaultPipeline creates the pipeline used by the API: word lists, link
+// spam, repeated content and new-account rate checks. It runs no checks when
+// filtering is disabled.
+func NewDefaultPipeline(db *pgxpool.Pool, cfg *config.Config) *Pipeline {
+	if !cfg.Filter.Enabled {
+		return NewPipeline()
+	}
+
+	history := NewHistory(db)
+
+	return NewPipeline(
+		NewWordListCheck(cfg.Filter.BlockedWords),
+		NewLinkSpamCheck(cfg.Filter.MaxLinks),
+		NewRepeatedContentCheck(history, cfg.Filter.RepeatWindow, cfg.Filter.MaxRepeats),
+		NewAccountRateCheck(history, cfg.Filter.NewAccountAge, cfg.Filter.NewAccountMaxPerHour),
+	)
+}
+
+// Run runs every check and collects the reasons content was flagged
+func (p *Pipeline) Run(ctx context.Context, content *Content) (*Result, error) {
+	result := &Result{}
+
+	for _, check := range p.checks {
+		reason, err := check.Check(ctx, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run content filter: %w", err)
+		}
+
+		if reason != "" {
+			result.Held = true
+			result.Reasons = append(result.Reasons, reason)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// History looks up what an author has published recently
+type History struct {
+	db *pgxpool.Pool
+}
+
+func NewHistory(db *pgxpool.Pool) *History {
+	return &History{db: db}
+}
+
+// duplicateQueries count an author's items of each kind with the same text
+// created since a given time, leaving out the item being re-screened
+var duplicateQueries = map[string]string{
+	KindPost: `
+		SELECT COUNT(*) FROM posts
+		WHERE user_id = $1 AND created_at >= $2 AND is_deleted = false
+		  AND lower(btrim(content)) = $3 AND id <> $4
+	`,
+	KindComment: `
+		SELECT COUNT(*) FROM comments
+		WHERE user_id = $1 AND created_at >= $2 AND is_deleted = false
+		  AND lower(btrim(content)) = $3 AND id <> $4
+	`,
+	KindEvent: `
+		SELECT COUNT(*) FROM events
+		WHERE created_by = $1 AND created_at >= $2 AND is_deleted = false
+		  AND lower(btrim(description)) = $3 AND id <> $4
+	`,
+}
+
+// CountDuplicates counts the author's recent items of the same kind with the
+// same text, other than excludeID
+func (h *History) CountDuplicates(ctx context.Context, authorID, excludeID uuid.UUID, kind, text string, since time.Time) (int, error) {
+	query, ok := duplicateQueries[kind]
+	if !ok {
+		return 0, fmt.Errorf("unknown content kind: %s", kind)
+	}
+
+	var count int
+	normalized := strings.ToLower(strings.TrimSpace(text))
+	if err := h.db.QueryRow(ctx, query, authorID, since, normalized, excludeID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count duplicate content: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountRecent counts the posts, comments and events the author created since a given time
+func (h *History) CountRecent(ctx context.Context, authorID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT (SELECT COUNT(*) FROM posts WHERE user_id = $1 AND created_at >= $2)
+		     + (SELECT COUNT(*) FROM comments WHERE user_id = $1 AND created_at >= $2)
+		     + (SELECT COUNT(*) FROM events WHERE created_by = $1 AND created_at >= $2)
+	`
+
+	var count int
+	if err := h.db.QueryRow(ctx, query, authorID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recent content: %w", err)
+	}
+
+	return count, nil
+}
+
+// AccountCreatedAt returns when the author signed up
+func (h *History) AccountCreatedAt(ctx context.Context, authorID uuid.UUID) (time.Time, error) {
+	var createdAt time.Time
+	err := h.db.QueryRow(ctx, `SELECT created_at FROM users WHERE id = $1`, authorID).Scan(&createdAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, fmt.Errorf("user not found")
+		}
+		return time.Time{}, fmt.Errorf("failed to get account age: %w", err)
+	}
+
+	return createdAt, nil
+}
+
+// RepeatedContentCheck holds content the author already published several
+// times within a time window
+type RepeatedContentCheck struct {
+	history    *History
+	window     time.Duration
+	maxRepeats int
+}
+
+// NewRepeatedContentCheck creates a check allowing the same text maxRepeats
+// times within window
+func NewRepeatedContentCheck(history *History, window time.Duration, maxRepeats int) *RepeatedContentCheck {
+	return &RepeatedContentCheck{history: history, window: window, maxRepeats: maxRepeats}
+}
+
+// Check implements Check
+func (c *RepeatedContentCheck) Check(ctx context.Context, content *Content) (string, error) {
+	if strings.TrimSpace(content.Text) == "" {
+		return "", nil
+	}
+
+	count, err := c.history.CountDuplicates(ctx, content.AuthorID, content.ItemID, content.Kind, content.Text, time.Now().UTC().Add(-c.window))
+	if err != nil {
+		return "", err
+	}
+
+	if count >= c.maxRepeats {
+		return "repeated_content", nil
+	}
+
+	return "", nil
+}
+
+// AccountRateCheck holds content from new accounts publishing faster than allowed
+type AccountRateCheck struct {
+	history    *History
+	accountAge time.Duration
+	maxPerHour int
+}
+
+// NewAccountRateCheck creates a check limiting accounts younger than
+// accountAge to maxPerHour items
+func NewAccountRateCheck(history *History, accountAge time.Duration, maxPerHour int) *AccountRateCheck {
+	return &AccountRateCheck{history: history, accountAge: accountAge, maxPerHour: maxPerHour}
+}
+
+// Check implements Check
+func (c *AccountRateCheck) Check(ctx context.Context, content *Content) (string, error) {
+	createdAt, err := c.history.AccountCreatedAt(ctx, content.AuthorID)
+	if err != nil {
+		return "", err
+	}
+
+	if time.Since(createdAt) >= c.accountAge {
+		return "", nil
+	}
+
+	count, err := c.history.CountRecent(ctx, content.AuthorID, time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+
+	if count >= c.maxPerHour {
+		return "new_account_rate", nil
+	}
+
+	return "", nil
+}
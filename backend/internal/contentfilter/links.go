@@ -0,0 +1,68 @@
+package contentfilter
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+
+// URL shorteners hide the real destination and are a common spam signal
+var shortenerDomains = []string{
+	"bit.ly", "tinyurl.com", "t.co", "goo.gl", "ow.ly", "is.gd", "buff.ly",
+	"cutt.ly", "rebrand.ly", "shorturl.at", "tiny.cc",
+}
+
+// LinkSpamCheck holds content with too many links, links hidden behind URL
+// shorteners, or content that is nothing but links
+type LinkSpamCheck struct {
+	maxLinks int
+}
+
+// NewLinkSpamCheck creates a link spam check allowing up to maxLinks links
+func NewLinkSpamCheck(maxLinks int) *LinkSpamCheck {
+	return &LinkSpamCheck{maxLinks: maxLinks}
+}
+
+// Check implements Check
+func (c *LinkSpamCheck) Check(ctx context.Context, content *Content) (string, error) {
+	links := linkPattern.FindAllString(content.Text, -1)
+	if len(links) == 0 {
+		return "", nil
+	}
+
+	if len(links) > c.maxLinks {
+		return "too_many_links", nil
+	}
+
+	for _, link := range links {
+		if isShortenedLink(link) {
+			return "shortened_link", nil
+		}
+	}
+
+	if strings.TrimSpace(linkPattern.ReplaceAllString(content.Text, "")) == "" && len(links) > 1 {
+		return "links_only", nil
+	}
+
+	return "", nil
+}
+
+// isShortenedLink reports whether the link points at a known URL shortener
+func isShortenedLink(link string) bool {
+	host := strings.ToLower(link)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimPrefix(host, "www.")
+	if i := strings.IndexAny(host, "/?#:"); i >= 0 {
+		host = host[:i]
+	}
+
+	for _, domain := range shortenerDomains {
+		if host == domain {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Built-in lists of words that hold content for review. They are kept short on
+// purpose, and words that are harmless in the other language (such as "retard",
+// French for "delay") are left out. Deployments can extend them with
+// FILTER_BLOCKED_WORDS.
+var (
+	frenchWords = []string{
+		"connard", "connasse", "salope", "enculé", "pute", "putain", "nique",
+		"niquer", "batard", "bâtard", "pédé", "tapette", "bougnoule", "négro",
+		"youpin", "fdp", "ntm",
+	}
+
+	englishWords = []string{
+		"fuck", "fucking", "motherfucker", "shit", "bitch", "cunt", "asshole",
+		"faggot", "nigger", "whore", "slut", "kys",
+	}
+)
+
+// accentReplacer folds accented letters so "enculé" and "encule" match alike
+var accentReplacer = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i",
+	"ô", "o", "ö", "o",
+	"ù", "u", "û", "u", "ü", "u",
+	"ÿ", "y", "œ", "oe", "æ", "ae",
+)
+
+// leetReplacer undoes common character substitutions used to dodge filters
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// WordListCheck holds content containing words from the French and English lists
+type WordListCheck struct {
+	words map[string]bool
+}
+
+// NewWordListCheck creates a word list check using the built-in lists plus extra words
+func NewWordListCheck(extra []string) *WordListCheck {
+	words := make(map[string]bool)
+	for _, list := range [][]string{frenchWords, englishWords, extra} {
+		for _, word := range list {
+			words[normalizeWord(word)] = true
+		}
+	}
+	return &WordListCheck{words: words}
+}
+
+// Check implements Check
+func (c *WordListCheck) Check(ctx context.Context, content *Content) (string, error) {
+	for _, word := range tokenize(content.Text) {
+		if c.words[word] || c.words[leetReplacer.Replace(word)] {
+			return "blocked_word", nil
+		}
+	}
+	return "", nil
+}
+
+// normalizeWord lowercases a word and folds its accents
+func normalizeWord(word string) string {
+	return accentReplacer.Replace(strings.ToLower(strings.TrimSpace(word)))
+}
+
+// tokenize splits text into normalized words. Digits and a few symbols are kept
+// inside words so leetspeak can be undone afterwards.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(normalizeWord(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '@' && r != '$'
+	})
+	return fields
+}
@@ -232,6 +232,13 @@ func (a *openAgendaAdapter) convertToEvent(oaEvent models.OpenAgendaEvent) (*mod
 		ExternalID:  &externalID,
 	}
 
+	// OpenAgenda reports missing coordinates as zero
+	if oaEvent.Location.Latitude != 0 || oaEvent.Location.Longitude != 0 {
+		latitude, longitude := oaEvent.Location.Latitude, oaEvent.Location.Longitude
+		event.Latitude = &latitude
+		event.Longitude = &longitude
+	}
+
 	return event, nil
 }
 
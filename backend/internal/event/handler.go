@@ -1,10 +1,12 @@
 package event
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
 
+	"github.com/Aolakije/City-Buzz/internal/geo"
 	"github.com/Aolakije/City-Buzz/internal/models"
 	"github.com/Aolakije/City-Buzz/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -57,6 +59,44 @@ func (h *Handler) GetTrendingEvents(c *fiber.Ctx) error {
 	})
 }
 
+// GetNearbyEvents handles GET /api/v1/events/nearby?lat=49.44&lon=1.09&radius_km=5
+func (h *Handler) GetNearbyEvents(c *fiber.Ctx) error {
+	latitude, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	longitude, errLon := strconv.ParseFloat(c.Query("lon"), 64)
+	if errLat != nil || errLon != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "lat and lon are required")
+	}
+
+	radiusKm := c.QueryFloat("radius_km", 5)
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("pageSize", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	events, err := h.service.GetNearbyEvents(c.Context(), latitude, longitude, radiusKm, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "invalid coordinates":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid coordinates")
+		case "invalid radius":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("radius_km must be between 0 and %g", geo.MaxRadiusKm))
+		}
+		log.Printf("Get nearby events error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch nearby events")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Nearby events fetched successfully", fiber.Map{
+		"events":    events,
+		"total":     len(events),
+		"radius_km": radiusKm,
+	})
+}
+
 // GetEventByID handles GET /api/v1/events/:id
 func (h *Handler) GetEventByID(c *fiber.Ctx) error {
 	eventID, err := utils.ParseUUID(c.Params("id"))
@@ -99,10 +139,18 @@ func (h *Handler) CreateEvent(c *fiber.Ctx) error {
 
 	event, err := h.service.CreateEvent(c.Context(), &req, userID)
 	if err != nil {
+		if err.Error() == "latitude and longitude must be set together" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Latitude and longitude must be set together")
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create event")
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusCreated, "Event created successfully", event)
+	message := "Event created successfully"
+	if event.IsHeld {
+		message = "Event submitted for review"
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, message, event)
 }
 
 // UpdateEvent handles PUT /api/v1/events/:id (protected)
@@ -133,6 +181,9 @@ func (h *Handler) UpdateEvent(c *fiber.Ctx) error {
 
 	event, err := h.service.UpdateEvent(c.Context(), eventID, &req, userID)
 	if err != nil {
+		if err.Error() == "latitude and longitude must be set together" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Latitude and longitude must be set together")
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -339,3 +390,43 @@ func (h *Handler) GetEventAttendees(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, fiber.StatusOK, "Attendees fetched successfully", attendees)
 }
+
+// CreateEventChat handles POST /api/v1/events/:id/chat (protected)
+// Creates the group chat of the attendees going to an event the user organizes
+func (h *Handler) CreateEventChat(c *fiber.Ctx) error {
+	userIDValue := c.Locals("userID")
+	if userIDValue == nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	userID, err := utils.ParseUUID(userIDValue.(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	eventID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid event ID")
+	}
+
+	conversation, created, err := h.service.CreateEventChat(c.Context(), eventID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "event not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Event not found")
+		case "unauthorized: only the organizer can create the event chat":
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Only the organizer can create the event chat")
+		}
+		log.Printf("Create event chat error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create event chat")
+	}
+
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+
+	return utils.SuccessResponse(c, status, "", fiber.Map{
+		"conversation": conversation,
+	})
+}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+)
+
+// Reminder reminds the users going to an event that it starts soon, at each of
+// the configured offsets before its start. Sent reminders are recorded in the
+// database, so restarts neither repeat nor lose them.
+type Reminder struct {
+	repo          Repository
+	notifications *notification.Service
+	offsets       []time.Duration
+	interval      time.Duration
+	batchSize     int
+}
+
+func NewReminder(repo Repository, cfg *config.Config, notifications *notification.Service) *Reminder {
+	return &Reminder{
+		repo:          repo,
+		notifications: notifications,
+		offsets:       cfg.Reminders.Offsets,
+		interval:      cfg.Reminders.Interval,
+		batchSize:     cfg.Reminders.BatchSize,
+	}
+}
+
+// Run sends due reminders right away and then on every interval until ctx is cancelled
+func (r *Reminder) Run(ctx context.Context) {
+	if len(r.offsets) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.SendDue(ctx); err != nil {
+			log.Printf("Event reminders error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends every reminder whose time has come, a batch at a time
+func (r *Reminder) SendDue(ctx context.Context) error {
+	for {
+		reminders, err := r.repo.ClaimDueReminders(ctx, r.offsets, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		sent := 0
+		for eventID, userIDs := range reminders {
+			for _, userID := range userIDs {
+				r.notifications.Notify(ctx, userID, uuid.Nil, models.NotificationEventReminder, notification.EventTarget(eventID))
+			}
+			sent += len(userIDs)
+		}
+
+		if sent > 0 {
+			log.Printf("Sent %d event reminders", sent)
+		}
+		if sent < r.batchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
@@ -3,8 +3,10 @@ package event
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/Aolakije/City-Buzz/internal/geo"
 	"github.com/Aolakije/City-Buzz/internal/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -14,8 +16,10 @@ type Repository interface {
 	// Event CRUD
 	Create(ctx context.Context, event *models.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Event, error)
 	GetByCity(ctx context.Context, city, category string, limit, offset int) ([]*models.Event, error)
 	GetUpcoming(ctx context.Context, city, category string, limit, offset int) ([]*models.Event, error)
+	GetNearby(ctx context.Context, near *geo.Query, limit, offset int) ([]*models.Event, error)
 	Update(ctx context.Context, id uuid.UUID, event *models.Event) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetUserEvents(ctx context.Context, userID uuid.UUID) ([]*models.Event, error)
@@ -28,6 +32,8 @@ type Repository interface {
 	GetUserRSVPs(ctx context.Context, userID uuid.UUID, status string) ([]*models.EventRSVP, error)
 	GetEventAttendees(ctx context.Context, eventID uuid.UUID) (*models.EventAttendeesResponse, error) // ADD THIS LINE
 
+	// Reminders
+	ClaimDueReminders(ctx context.Context, offsets []time.Duration, limit int) (map[uuid.UUID][]uuid.UUID, error)
 }
 
 type repository struct {
@@ -43,8 +49,8 @@ func (r *repository) Create(ctx context.Context, event *models.Event) error {
         INSERT INTO events (
             id, title, description, start_date, end_date, location, address, city, category,
             event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
-            max_capacity, source, external_id, created_by
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+            max_capacity, source, external_id, created_by, is_held, held_reasons, latitude, longitude
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
         ON CONFLICT (id) DO UPDATE SET
             title = EXCLUDED.title,
             description = EXCLUDED.description,
@@ -52,6 +58,8 @@ func (r *repository) Create(ctx context.Context, event *models.Event) error {
             end_date = EXCLUDED.end_date,
             location = EXCLUDED.location,
             address = EXCLUDED.address,
+            latitude = EXCLUDED.latitude,
+            longitude = EXCLUDED.longitude,
             city = EXCLUDED.city,
             category = EXCLUDED.category,
             event_type = EXCLUDED.event_type,
@@ -64,24 +72,24 @@ func (r *repository) Create(ctx context.Context, event *models.Event) error {
 		event.ID, event.Title, event.Description, event.StartDate, event.EndDate, event.Location,
 		event.Address, event.City, event.Category, event.EventType, event.ImageURL, event.Price, event.IsFree,
 		event.OrganizerName, event.OrganizerContact, event.TicketURL, event.MaxCapacity,
-		event.Source, event.ExternalID, event.CreatedBy,
+		event.Source, event.ExternalID, event.CreatedBy, event.IsHeld, event.HeldReasons, event.Latitude, event.Longitude,
 	).Scan(&event.CreatedAt, &event.UpdatedAt, &event.GoingCount, &event.InterestedCount, &event.IsDeleted)
 }
 
 func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
 	query := `
-		SELECT id, title, description, start_date, end_date, location, address, city, category,
+		SELECT id, title, description, start_date, end_date, location, address, latitude, longitude, city, category,
 			   event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
 			   max_capacity, going_count, interested_count, source, external_id, created_by,
 			   created_at, updated_at, is_deleted
 		FROM events
-		WHERE id = $1 AND is_deleted = false
+		WHERE id = $1 AND is_deleted = false AND is_hidden = false AND is_held = false
 	`
 
 	event := &models.Event{}
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
-		&event.Location, &event.Address, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+		&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
 		&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
 		&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
 		&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
@@ -95,14 +103,51 @@ func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*models.Event,
 	return event, nil
 }
 
+// GetByIDs retrieves the visible events among the given IDs, keyed by ID
+func (r *repository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Event, error) {
+	query := `
+		SELECT id, title, description, start_date, end_date, location, address, latitude, longitude, city, category,
+			   event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
+			   max_capacity, going_count, interested_count, source, external_id, created_by,
+			   created_at, updated_at, is_deleted
+		FROM events
+		WHERE id = ANY($1) AND is_deleted = false AND is_hidden = false AND is_held = false
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make(map[uuid.UUID]*models.Event, len(ids))
+	for rows.Next() {
+		event := &models.Event{}
+		err := rows.Scan(
+			&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
+			&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+			&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
+			&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
+			&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
+			&event.UpdatedAt, &event.IsDeleted,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events[event.ID] = event
+	}
+
+	return events, rows.Err()
+}
+
 func (r *repository) GetByCity(ctx context.Context, city, category string, limit, offset int) ([]*models.Event, error) {
 	query := `
-		SELECT id, title, description, start_date, end_date, location, address, city, category,
+		SELECT id, title, description, start_date, end_date, location, address, latitude, longitude, city, category,
 			   event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
 			   max_capacity, going_count, interested_count, source, external_id, created_by,
 			   created_at, updated_at, is_deleted
 		FROM events
-		WHERE LOWER(city) = LOWER($1) AND is_deleted = false
+		WHERE LOWER(city) = LOWER($1) AND is_deleted = false AND is_hidden = false AND is_held = false
 	`
 
 	args := []interface{}{city}
@@ -139,7 +184,7 @@ func (r *repository) GetByCity(ctx context.Context, city, category string, limit
 		event := &models.Event{}
 		err := rows.Scan(
 			&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
-			&event.Location, &event.Address, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+			&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
 			&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
 			&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
 			&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
@@ -156,12 +201,12 @@ func (r *repository) GetByCity(ctx context.Context, city, category string, limit
 
 func (r *repository) GetUpcoming(ctx context.Context, city, category string, limit, offset int) ([]*models.Event, error) {
 	query := `
-		SELECT id, title, description, start_date, end_date, location, address, city, category,
+		SELECT id, title, description, start_date, end_date, location, address, latitude, longitude, city, category,
 			   event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
 			   max_capacity, going_count, interested_count, source, external_id, created_by,
 			   created_at, updated_at, is_deleted
 		FROM events
-		WHERE start_date >= $1 AND is_deleted = false
+		WHERE start_date >= $1 AND is_deleted = false AND is_hidden = false AND is_held = false
 	`
 
 	args := []interface{}{time.Now()}
@@ -204,7 +249,7 @@ func (r *repository) GetUpcoming(ctx context.Context, city, category string, lim
 		event := &models.Event{}
 		err := rows.Scan(
 			&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
-			&event.Location, &event.Address, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+			&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
 			&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
 			&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
 			&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
@@ -219,21 +264,71 @@ func (r *repository) GetUpcoming(ctx context.Context, city, category string, lim
 	return events, rows.Err()
 }
 
+// GetNearby retrieves upcoming or ongoing events located within a radius of a
+// point, closest first
+func (r *repository) GetNearby(ctx context.Context, near *geo.Query, limit, offset int) ([]*models.Event, error) {
+	query := `
+		SELECT e.id, e.title, e.description, e.start_date, e.end_date, e.location, e.address, e.latitude, e.longitude,
+			   e.city, e.category, e.event_type, e.image_url, e.price, e.is_free, e.organizer_name,
+			   e.organizer_contact, e.ticket_url, e.max_capacity, e.going_count, e.interested_count,
+			   e.source, e.external_id, e.created_by, e.created_at, e.updated_at, e.is_deleted,
+			   ` + near.Distance("e", 3) + ` AS distance
+		FROM events e
+		WHERE ` + near.Where("e", 3) + `
+		  AND COALESCE(e.end_date, e.start_date) >= NOW()
+		  AND e.is_deleted = false AND e.is_hidden = false AND e.is_held = false
+		ORDER BY distance ASC, e.start_date ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	args := append([]interface{}{limit, offset}, near.Args()...)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.Event{}
+	for rows.Next() {
+		event := &models.Event{}
+		var distance float64
+		err := rows.Scan(
+			&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
+			&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+			&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
+			&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
+			&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
+			&event.UpdatedAt, &event.IsDeleted, &distance,
+		)
+		if err != nil {
+			return nil, err
+		}
+		distance = math.Round(distance*100) / 100
+		event.DistanceKm = &distance
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
 func (r *repository) Update(ctx context.Context, id uuid.UUID, event *models.Event) error {
 	query := `
 		UPDATE events
 		SET title = $1, description = $2, start_date = $3, end_date = $4, location = $5,
 			address = $6, city = $7, category = $8, event_type = $9, image_url = $10, price = $11, is_free = $12,
 			organizer_name = $13, organizer_contact = $14, ticket_url = $15, max_capacity = $16,
-			updated_at = NOW()
-		WHERE id = $17 AND is_deleted = false
+			latitude = $17, longitude = $18, updated_at = NOW(),
+			is_held = is_held OR $20,
+			held_reasons = CASE WHEN $20 THEN $21 ELSE held_reasons END
+		WHERE id = $19 AND is_deleted = false
 		RETURNING updated_at
 	`
 
 	return r.db.QueryRow(ctx, query,
 		event.Title, event.Description, event.StartDate, event.EndDate, event.Location,
 		event.Address, event.City, event.Category, event.EventType, event.ImageURL, event.Price, event.IsFree,
-		event.OrganizerName, event.OrganizerContact, event.TicketURL, event.MaxCapacity, id,
+		event.OrganizerName, event.OrganizerContact, event.TicketURL, event.MaxCapacity,
+		event.Latitude, event.Longitude, id, event.IsHeld, event.HeldReasons,
 	).Scan(&event.UpdatedAt)
 }
 
@@ -245,12 +340,12 @@ func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *repository) GetUserEvents(ctx context.Context, userID uuid.UUID) ([]*models.Event, error) {
 	query := `
-		SELECT id, title, description, start_date, end_date, location, address, city, category,
+		SELECT id, title, description, start_date, end_date, location, address, latitude, longitude, city, category,
 			   event_type, image_url, price, is_free, organizer_name, organizer_contact, ticket_url,
 			   max_capacity, going_count, interested_count, source, external_id, created_by,
-			   created_at, updated_at, is_deleted
+			   created_at, updated_at, is_deleted, is_held
 		FROM events
-		WHERE created_by = $1 AND is_deleted = false
+		WHERE created_by = $1 AND is_deleted = false AND is_hidden = false
 		ORDER BY created_at DESC
 	`
 
@@ -265,11 +360,11 @@ func (r *repository) GetUserEvents(ctx context.Context, userID uuid.UUID) ([]*mo
 		event := &models.Event{}
 		err := rows.Scan(
 			&event.ID, &event.Title, &event.Description, &event.StartDate, &event.EndDate,
-			&event.Location, &event.Address, &event.City, &event.Category, &event.EventType, &event.ImageURL,
+			&event.Location, &event.Address, &event.Latitude, &event.Longitude, &event.City, &event.Category, &event.EventType, &event.ImageURL,
 			&event.Price, &event.IsFree, &event.OrganizerName, &event.OrganizerContact,
 			&event.TicketURL, &event.MaxCapacity, &event.GoingCount, &event.InterestedCount,
 			&event.Source, &event.ExternalID, &event.CreatedBy, &event.CreatedAt,
-			&event.UpdatedAt, &event.IsDeleted,
+			&event.UpdatedAt, &event.IsDeleted, &event.IsHeld,
 		)
 		if err != nil {
 			return nil, err
@@ -439,3 +534,62 @@ func (r *repository) GetEventAttendees(ctx context.Context, eventID uuid.UUID) (
 		InterestedCount: len(interested),
 	}, nil
 }
+
+// ClaimDueReminders records the reminders due now, at one of the offsets before
+// the start of an event, to the users going to it, and returns the users to
+// remind by event. Recording them first means a reminder is never sent twice,
+// even by concurrent instances. Users who RSVP'd after a reminder was due are
+// not sent that one, and several reminders due at once for the same event are
+// returned once.
+func (r *repository) ClaimDueReminders(ctx context.Context, offsets []time.Duration, limit int) (map[uuid.UUID][]uuid.UUID, error) {
+	seconds := make([]int32, len(offsets))
+	for i, offset := range offsets {
+		seconds[i] = int32(offset.Seconds())
+	}
+
+	query := `
+		WITH due AS (
+			SELECT r.event_id, r.user_id, o.offset_seconds, e.start_date
+			FROM event_rsvps r
+			JOIN events e ON e.id = r.event_id
+			CROSS JOIN unnest($1::int[]) AS o(offset_seconds)
+			WHERE r.status = 'going'
+			  AND e.is_deleted = false AND e.is_hidden = false AND e.is_held = false
+			  AND e.start_date > NOW()
+			  AND e.start_date - make_interval(secs => o.offset_seconds) <= NOW()
+			  AND r.updated_at < e.start_date - make_interval(secs => o.offset_seconds)
+			  AND NOT EXISTS (
+			      SELECT 1 FROM event_reminders er
+			      WHERE er.event_id = r.event_id AND er.user_id = r.user_id
+			        AND er.offset_seconds = o.offset_seconds AND er.start_date = e.start_date
+			  )
+			LIMIT $2
+		)
+		INSERT INTO event_reminders (event_id, user_id, offset_seconds, start_date)
+		SELECT event_id, user_id, offset_seconds, start_date FROM due
+		ON CONFLICT DO NOTHING
+		RETURNING event_id, user_id
+	`
+
+	rows, err := r.db.Query(ctx, query, seconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := make(map[uuid.UUID][]uuid.UUID)
+	seen := make(map[[2]uuid.UUID]bool)
+	for rows.Next() {
+		var eventID, userID uuid.UUID
+		if err := rows.Scan(&eventID, &userID); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		if seen[[2]uuid.UUID{eventID, userID}] {
+			continue
+		}
+		seen[[2]uuid.UUID{eventID, userID}] = true
+		reminders[eventID] = append(reminders[eventID], userID)
+	}
+
+	return reminders, rows.Err()
+}
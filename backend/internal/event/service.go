@@ -7,8 +7,13 @@ import (
 	"sort"
 	"time"
 
+	"github.com/Aolakije/City-Buzz/internal/chat"
+	"github.com/Aolakije/City-Buzz/internal/contentfilter"
 	"github.com/Aolakije/City-Buzz/internal/event/adapters"
+	"github.com/Aolakije/City-Buzz/internal/geo"
 	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
 	"github.com/Aolakije/City-Buzz/pkg/config"
 	"github.com/google/uuid"
 )
@@ -17,9 +22,12 @@ type Service interface {
 	// Event operations
 	CreateEvent(ctx context.Context, req *models.CreateEventRequest, userID uuid.UUID) (*models.Event, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (*models.Event, error)
+	GetEventsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Event, error)
+	EnsureEvent(ctx context.Context, id uuid.UUID) error
 	GetEventsByCity(ctx context.Context, city, category, language string, page, pageSize int) ([]*models.Event, error)
 	GetUpcomingEvents(ctx context.Context, city, category, language string, page, pageSize int) ([]*models.Event, error)
 	GetTrendingEvents(ctx context.Context, city string, limit int) ([]*models.Event, error)
+	GetNearbyEvents(ctx context.Context, latitude, longitude, radiusKm float64, page, pageSize int) ([]*models.Event, error)
 	UpdateEvent(ctx context.Context, id uuid.UUID, req *models.UpdateEventRequest, userID uuid.UUID) (*models.Event, error)
 	DeleteEvent(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	GetUserEvents(ctx context.Context, userID uuid.UUID) ([]*models.Event, error)
@@ -31,23 +39,38 @@ type Service interface {
 	GetUserRSVP(ctx context.Context, eventID, userID uuid.UUID) (*models.EventRSVP, error)
 	GetUserRSVPs(ctx context.Context, userID uuid.UUID, status string) ([]*models.EventRSVP, error)
 	GetEventAttendees(ctx context.Context, eventID uuid.UUID) (*models.EventAttendeesResponse, error) // ADD THIS LINE
+
+	// Group chat of the attendees going
+	CreateEventChat(ctx context.Context, eventID, userID uuid.UUID) (*models.Conversation, bool, error)
 }
 
 type service struct {
 	repo              Repository
 	openAgendaAdapter adapters.OpenAgendaAdapter
+	filter            *contentfilter.Pipeline
+	notifications     *notification.Service
+	hub               *realtime.Hub
+	chats             *chat.Service
 	config            *config.Config
 }
 
-func NewService(repo Repository, cfg *config.Config) Service {
+func NewService(repo Repository, cfg *config.Config, filter *contentfilter.Pipeline, notifications *notification.Service, hub *realtime.Hub, chats *chat.Service) Service {
 	return &service{
 		repo:              repo,
 		openAgendaAdapter: adapters.NewOpenAgendaAdapter(cfg),
+		filter:            filter,
+		notifications:     notifications,
+		hub:               hub,
+		chats:             chats,
 		config:            cfg,
 	}
 }
 
 func (s *service) CreateEvent(ctx context.Context, req *models.CreateEventRequest, userID uuid.UUID) (*models.Event, error) {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, fmt.Errorf("latitude and longitude must be set together")
+	}
+
 	event := &models.Event{
 		ID:               uuid.New(),
 		Title:            req.Title,
@@ -56,6 +79,8 @@ func (s *service) CreateEvent(ctx context.Context, req *models.CreateEventReques
 		EndDate:          req.EndDate,
 		Location:         req.Location,
 		Address:          req.Address,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
 		City:             req.City,
 		Category:         req.Category,
 		EventType:        req.EventType,
@@ -70,6 +95,11 @@ func (s *service) CreateEvent(ctx context.Context, req *models.CreateEventReques
 		CreatedBy:        &userID,
 	}
 
+	// Flagged events are held for review instead of being listed
+	if err := s.screenEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Create(ctx, event); err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
@@ -85,6 +115,33 @@ func (s *service) GetEvent(ctx context.Context, id uuid.UUID) (*models.Event, er
 	return event, nil
 }
 
+// GetEventsByIDs retrieves the visible events among the given IDs, keyed by ID
+func (s *service) GetEventsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.Event, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*models.Event{}, nil
+	}
+
+	events, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
+}
+
+// EnsureEvent makes sure an event is stored in the database, importing it from
+// OpenAgenda when it has only been seen through the API so far
+func (s *service) EnsureEvent(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		log.Printf("Event %s not found in DB, fetching from OpenAgenda", id)
+
+		if err := s.fetchAndSaveEventFromOpenAgenda(ctx, id); err != nil {
+			return fmt.Errorf("failed to fetch and save event %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
 func (s *service) GetEventsByCity(ctx context.Context, city, category, language string, page, pageSize int) ([]*models.Event, error) {
 	offset := (page - 1) * pageSize
 
@@ -349,6 +406,22 @@ func (s *service) GetTrendingEvents(ctx context.Context, city string, limit int)
 	return todayEvents, nil
 }
 
+// GetNearbyEvents retrieves upcoming events located within a radius of a point, closest first
+func (s *service) GetNearbyEvents(ctx context.Context, latitude, longitude, radiusKm float64, page, pageSize int) ([]*models.Event, error) {
+	near, err := geo.NewQuery(latitude, longitude, radiusKm)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * pageSize
+	events, err := s.repo.GetNearby(ctx, near, pageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nearby events: %w", err)
+	}
+
+	return events, nil
+}
+
 func (s *service) UpdateEvent(ctx context.Context, id uuid.UUID, req *models.UpdateEventRequest, userID uuid.UUID) (*models.Event, error) {
 	// Get existing event
 	event, err := s.repo.GetByID(ctx, id)
@@ -361,6 +434,10 @@ func (s *service) UpdateEvent(ctx context.Context, id uuid.UUID, req *models.Upd
 		return nil, fmt.Errorf("unauthorized: you can only update your own events")
 	}
 
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, fmt.Errorf("latitude and longitude must be set together")
+	}
+
 	// Update fields
 	if req.Title != nil {
 		event.Title = *req.Title
@@ -380,6 +457,10 @@ func (s *service) UpdateEvent(ctx context.Context, id uuid.UUID, req *models.Upd
 	if req.Address != nil {
 		event.Address = req.Address
 	}
+	if req.Latitude != nil {
+		event.Latitude = req.Latitude
+		event.Longitude = req.Longitude
+	}
 	if req.City != nil {
 		event.City = *req.City
 	}
@@ -411,13 +492,40 @@ func (s *service) UpdateEvent(ctx context.Context, id uuid.UUID, req *models.Upd
 		event.MaxCapacity = req.MaxCapacity
 	}
 
+	// Edits are screened like new events; a flagged edit hides the event until
+	// a moderator approves it
+	if err := s.screenEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Update(ctx, id, event); err != nil {
 		return nil, fmt.Errorf("failed to update event: %w", err)
 	}
+	if !event.IsHeld {
+		s.notifications.NotifyAll(ctx, s.attendeeIDs(ctx, id), userID, models.NotificationEventUpdated, notification.EventTarget(id))
+	}
 
 	return event, nil
 }
 
+// screenEvent runs the event's title and description through the content
+// filters and marks it as held for review when flagged
+func (s *service) screenEvent(ctx context.Context, event *models.Event) error {
+	result, err := s.filter.Run(ctx, &contentfilter.Content{
+		AuthorID: *event.CreatedBy,
+		ItemID:   event.ID,
+		Kind:     contentfilter.KindEvent,
+		Text:     event.Title + "\n" + event.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	event.IsHeld = result.Held
+	event.HeldReasons = result.Reasons
+	return nil
+}
+
 func (s *service) DeleteEvent(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	// Get existing event
 	event, err := s.repo.GetByID(ctx, id)
@@ -430,9 +538,13 @@ func (s *service) DeleteEvent(ctx context.Context, id uuid.UUID, userID uuid.UUI
 		return fmt.Errorf("unauthorized: you can only delete your own events")
 	}
 
+	// RSVPs are deleted with the event, so attendees are looked up first
+	attendees := s.attendeeIDs(ctx, id)
+
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
+	s.notifications.NotifyAll(ctx, attendees, userID, models.NotificationEventCancelled, notification.EventTarget(id))
 
 	return nil
 }
@@ -451,15 +563,9 @@ func (s *service) CreateOrUpdateRSVP(ctx context.Context, eventID, userID uuid.U
 		return fmt.Errorf("invalid RSVP status: must be 'going' or 'interested'")
 	}
 
-	// Check if event exists in database
-	_, err := s.repo.GetByID(ctx, eventID)
-	if err != nil {
-		// Event doesn't exist in DB - fetch and save from OpenAgenda
-		log.Printf("Event %s not found in DB, fetching from OpenAgenda", eventID)
-
-		if err := s.fetchAndSaveEventFromOpenAgenda(ctx, eventID); err != nil {
-			return fmt.Errorf("failed to fetch and save event %s: %w", eventID, err)
-		}
+	// Event doesn't exist in DB yet when it only came from OpenAgenda
+	if err := s.EnsureEvent(ctx, eventID); err != nil {
+		return err
 	}
 
 	// Event exists (or was just created), create/update RSVP
@@ -473,9 +579,55 @@ func (s *service) CreateOrUpdateRSVP(ctx context.Context, eventID, userID uuid.U
 		return fmt.Errorf("failed to create/update RSVP: %w", err)
 	}
 
+	// Only attendees going are in the event's group chat
+	if status == "going" {
+		if err := s.chats.JoinEventGroup(ctx, eventID, userID); err != nil {
+			log.Printf("Join group of event %s error: %v", eventID, err)
+		}
+	} else if err := s.chats.LeaveEventGroup(ctx, eventID, userID); err != nil {
+		log.Printf("Leave group of event %s error: %v", eventID, err)
+	}
+
+	event, err := s.repo.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("RSVP counts of event %s error: %v", eventID, err)
+		return nil
+	}
+	s.publishRSVPCounts(ctx, event)
+
+	// Organizers of user-created events hear about new RSVPs
+	if event.CreatedBy != nil {
+		s.notifications.Notify(ctx, *event.CreatedBy, userID, models.NotificationRSVP, notification.EventTarget(eventID))
+	}
+
 	return nil
 }
 
+// publishRSVPCounts streams an event's RSVP counts to the clients viewing it
+func (s *service) publishRSVPCounts(ctx context.Context, event *models.Event) {
+	s.hub.Publish(ctx, realtime.EventTopic(event.ID), realtime.EventRSVPCounts, map[string]interface{}{
+		"event_id":         event.ID,
+		"going_count":      event.GoingCount,
+		"interested_count": event.InterestedCount,
+	})
+}
+
+// attendeeIDs returns the users who RSVP'd to an event, for notifications
+func (s *service) attendeeIDs(ctx context.Context, eventID uuid.UUID) []uuid.UUID {
+	rsvps, err := s.repo.GetEventRSVPs(ctx, eventID, "")
+	if err != nil {
+		log.Printf("Attendees of event %s lookup error: %v", eventID, err)
+		return nil
+	}
+
+	attendees := make([]uuid.UUID, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		attendees = append(attendees, rsvp.UserID)
+	}
+
+	return attendees
+}
+
 // Updated helper function - much simpler now!
 func (s *service) fetchAndSaveEventFromOpenAgenda(ctx context.Context, eventID uuid.UUID) error {
 	// Use the new FetchEventByID method
@@ -497,6 +649,18 @@ func (s *service) DeleteRSVP(ctx context.Context, eventID, userID uuid.UUID) err
 	if err := s.repo.DeleteRSVP(ctx, eventID, userID); err != nil {
 		return fmt.Errorf("failed to delete RSVP: %w", err)
 	}
+
+	if err := s.chats.LeaveEventGroup(ctx, eventID, userID); err != nil {
+		log.Printf("Leave group of event %s error: %v", eventID, err)
+	}
+
+	event, err := s.repo.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("RSVP counts of event %s error: %v", eventID, err)
+		return nil
+	}
+	s.publishRSVPCounts(ctx, event)
+
 	return nil
 }
 
@@ -529,3 +693,39 @@ func (s *service) GetEventAttendees(ctx context.Context, eventID uuid.UUID) (*mo
 
 	return attendees, nil
 }
+
+// maxEventChatName is the longest group name; longer event titles are shortened
+const maxEventChatName = 100
+
+// CreateEventChat creates the group chat of an event the user organizes, with
+// the attendees going to it. It returns the existing group if there is one and
+// reports whether it was created.
+func (s *service) CreateEventChat(ctx context.Context, eventID, userID uuid.UUID) (*models.Conversation, bool, error) {
+	event, err := s.repo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, false, fmt.Errorf("event not found")
+	}
+
+	if event.CreatedBy == nil || *event.CreatedBy != userID {
+		return nil, false, fmt.Errorf("unauthorized: only the organizer can create the event chat")
+	}
+
+	rsvps, err := s.repo.GetEventRSVPs(ctx, eventID, "going")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get attendees: %w", err)
+	}
+
+	attendeeIDs := make([]uuid.UUID, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		if rsvp.UserID != userID {
+			attendeeIDs = append(attendeeIDs, rsvp.UserID)
+		}
+	}
+
+	name := []rune(event.Title)
+	if len(name) > maxEventChatName {
+		name = name[:maxEventChatName]
+	}
+
+	return s.chats.CreateEventGroup(ctx, eventID, userID, string(name), event.ImageURL, attendeeIDs)
+}
@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// MaxRadiusKm is the largest search radius accepted
+const MaxRadiusKm = 100.0
+
+// Point is a position in decimal degrees
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Valid reports whether the point has in-range coordinates
+func (p Point) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180 &&
+		!math.IsNaN(p.Latitude) && !math.IsNaN(p.Longitude)
+}
+
+// Query selects rows whose latitude and longitude columns lie within a radius of
+// a point. Rows are first narrowed with a bounding box, which can use the
+// coordinates indexes, then filtered on the exact distance computed by the
+// geo_distance_km SQL function.
+type Query struct {
+	Center   Point
+	RadiusKm float64
+}
+
+// NewQuery validates a center point and radius
+func NewQuery(latitude, longitude, radiusKm float64) (*Query, error) {
+	center := Point{Latitude: latitude, Longitude: longitude}
+	if !center.Valid() {
+		return nil, fmt.Errorf("invalid coordinates")
+	}
+
+	if radiusKm <= 0 || radiusKm > MaxRadiusKm || math.IsNaN(radiusKm) {
+		return nil, fmt.Errorf("invalid radius")
+	}
+
+	return &Query{Center: center, RadiusKm: radiusKm}, nil
+}
+
+// Args returns the query parameters used by Where and Distance, in order
+func (q *Query) Args() []interface{} {
+	minLat, maxLat, minLon, maxLon := q.boundingBox()
+	return []interface{}{q.Center.Latitude, q.Center.Longitude, q.RadiusKm, minLat, maxLat, minLon, maxLon}
+}
+
+// Where returns the SQL condition matching rows of the aliased table within the
+// radius. firstArg is the placeholder number of the first value from Args.
+func (q *Query) Where(alias string, firstArg int) string {
+	return fmt.Sprintf(
+		"%[1]s.latitude BETWEEN $%[2]d AND $%[3]d AND %[1]s.longitude BETWEEN $%[4]d AND $%[5]d AND %[6]s <= $%[7]d",
+		alias, firstArg+3, firstArg+4, firstArg+5, firstArg+6, q.Distance(alias, firstArg), firstArg+2,
+	)
+}
+
+// Distance returns the SQL expression of a row's distance to the center, in kilometres
+func (q *Query) Distance(alias string, firstArg int) string {
+	return fmt.Sprintf("geo_distance_km(%[1]s.latitude, %[1]s.longitude, $%[2]d, $%[3]d)", alias, firstArg, firstArg+1)
+}
+
+// boundingBox returns the latitude and longitude ranges enclosing the search circle
+func (q *Query) boundingBox() (minLat, maxLat, minLon, maxLon float64) {
+	latDelta := q.RadiusKm / earthRadiusKm * 180 / math.Pi
+	minLat = math.Max(q.Center.Latitude-latDelta, -90)
+	maxLat = math.Min(q.Center.Latitude+latDelta, 90)
+
+	// Near the poles, or when the box crosses the antimeridian, every longitude may match
+	cosLat := math.Cos(q.Center.Latitude * math.Pi / 180)
+	if minLat <= -90 || maxLat >= 90 || cosLat < 1e-6 {
+		return minLat, maxLat, -180, 180
+	}
+
+	lonDelta := latDelta / cosLat
+	minLon = q.Center.Longitude - lonDelta
+	maxLon = q.Center.Longitude + lonDelta
+	if minLon < -180 || maxLon > 180 {
+		return minLat, maxLat, -180, 180
+	}
+
+	return minLat, maxLat, minLon, maxLon
+}
@@ -0,0 +1,143 @@
+package linkpreview
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/netguard"
+)
+
+// maxRedirects bounds how many redirects are followed when fetching a page
+const maxRedirects = 3
+
+// Fetcher downloads pages and extracts their preview metadata
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewFetcher creates a fetcher with a time limit per page and a cap on how much
+// of each page is read. Unless allowPrivate is set, connections to loopback,
+// private, link-local and other non-public addresses are refused. The check runs
+// on the address actually dialed, so it also covers redirects and DNS rebinding.
+func NewFetcher(timeout time.Duration, maxBytes int64, allowPrivate bool) *Fetcher {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = netguard.GuardPublicAddress
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return NewFetcherWithClient(&http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}, maxBytes)
+}
+
+// NewFetcherWithClient creates a fetcher using the given HTTP client, for
+// instance one pointed at a local test server
+func NewFetcherWithClient(client *http.Client, maxBytes int64) *Fetcher {
+	return &Fetcher{client: client, maxBytes: maxBytes}
+}
+
+// Fetch downloads a page and returns its preview metadata
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	pageURL, err := validateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "City-Buzz-LinkPreview/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("unsupported content type: %s", mediaType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	preview := parseMetadata(string(body), resp.Request.URL)
+	preview.URL = rawURL
+
+	if preview.Title == nil && preview.Description == nil && preview.ImageURL == nil {
+		return nil, fmt.Errorf("no preview metadata found")
+	}
+
+	return preview, nil
+}
+
+// validateURL only accepts absolute http(s) URLs without credentials. Any port
+// is allowed; internal services are kept out by the dial guard instead.
+func validateURL(rawURL string) (*url.URL, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+
+	if pageURL.Scheme != "http" && pageURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme: %s", pageURL.Scheme)
+	}
+
+	if pageURL.Hostname() == "" || pageURL.User != nil {
+		return nil, fmt.Errorf("invalid url host")
+	}
+
+	return pageURL, nil
+}
+
+// checkRedirect limits redirects and keeps them on http(s)
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxRedirects {
+		return fmt.Errorf("too many redirects")
+	}
+	if _, err := validateURL(req.URL.String()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// normalizeText trims and collapses whitespace, and caps the length
+func normalizeText(value string, maxRunes int) *string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return nil
+	}
+	if runes := []rune(value); len(runes) > maxRunes {
+		value = string(runes[:maxRunes-1]) + "…"
+	}
+	return &value
+}
@@ -0,0 +1,121 @@
+package linkpreview
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+)
+
+var (
+	headEndPattern   = regexp.MustCompile(`(?i)</head\s*>`)
+	metaTagPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titleTagPattern  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title\s*>`)
+)
+
+// Lengths kept from each field, matching the link_previews columns
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+	maxSiteNameLength    = 200
+	maxImageURLLength    = 2048
+)
+
+// parseMetadata extracts OpenGraph and Twitter Card metadata from a page,
+// falling back to the <title> tag and standard description meta tag
+func parseMetadata(page string, pageURL *url.URL) *models.LinkPreview {
+	// Metadata lives in the head; ignore the body when it can be found
+	if loc := headEndPattern.FindStringIndex(page); loc != nil {
+		page = page[:loc[0]]
+	}
+
+	meta := make(map[string]string)
+	for _, tag := range metaTagPattern.FindAllString(page, -1) {
+		attrs := parseAttributes(tag)
+
+		key := attrs["property"]
+		if key == "" {
+			key = attrs["name"]
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		// The first occurrence of a property wins, as with og:image arrays
+		if key != "" && meta[key] == "" {
+			meta[key] = html.UnescapeString(attrs["content"])
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := strings.TrimSpace(meta[key]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	title := first("og:title", "twitter:title")
+	if title == "" {
+		if match := titleTagPattern.FindStringSubmatch(page); match != nil {
+			title = html.UnescapeString(match[1])
+		}
+	}
+
+	preview := &models.LinkPreview{
+		Title:       normalizeText(title, maxTitleLength),
+		Description: normalizeText(first("og:description", "twitter:description", "description"), maxDescriptionLength),
+		SiteName:    normalizeText(first("og:site_name", "application-name"), maxSiteNameLength),
+	}
+
+	image := first("og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src")
+	if image != "" {
+		preview.ImageURL = resolveImageURL(image, pageURL)
+	}
+
+	if preview.SiteName == nil && pageURL != nil {
+		preview.SiteName = normalizeText(strings.TrimPrefix(pageURL.Hostname(), "www."), maxSiteNameLength)
+	}
+
+	return preview
+}
+
+// parseAttributes returns the lower-cased attribute names and raw values of a tag
+func parseAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range attributePattern.FindAllStringSubmatch(tag, -1) {
+		value := match[2]
+		if value == "" {
+			value = match[3]
+		}
+		if value == "" {
+			value = match[4]
+		}
+		attrs[strings.ToLower(match[1])] = value
+	}
+	return attrs
+}
+
+// resolveImageURL makes an image URL absolute and keeps only http(s) images
+func resolveImageURL(image string, pageURL *url.URL) *string {
+	imageURL, err := url.Parse(image)
+	if err != nil {
+		return nil
+	}
+
+	if pageURL != nil {
+		imageURL = pageURL.ResolveReference(imageURL)
+	}
+
+	if imageURL.Scheme != "http" && imageURL.Scheme != "https" {
+		return nil
+	}
+
+	resolved := imageURL.String()
+	if len(resolved) > maxImageURLLength {
+		return nil
+	}
+	return &resolved
+}
@@ -0,0 +1,94 @@
+package linkpreview
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseMetadata(t *testing.T) {
+	pageURL, _ := url.Parse("https://www.example.com/articles/1")
+
+	tests := []struct {
+		name        string
+		page        string
+		title       string
+		description string
+		image       string
+		siteName    string
+	}{
+		{
+			name: "open graph",
+			page: `<head><meta property="og:title" content="OG title"><meta property="og:description" content="OG description">
+				<meta property="og:image" content="https://cdn.example.com/a.jpg"><meta property="og:site_name" content="Example"></head>`,
+			title: "OG title", description: "OG description", image: "https://cdn.example.com/a.jpg", siteName: "Example",
+		},
+		{
+			name: "twitter card",
+			page: `<head><meta name="twitter:title" content="Card title"><meta name="twitter:description" content="Card description">
+				<meta name="twitter:image" content="/card.png"></head>`,
+			title: "Card title", description: "Card description", image: "https://www.example.com/card.png", siteName: "example.com",
+		},
+		{
+			name:  "title tag and description",
+			page:  `<head><title>  Plain   title </title><meta name="description" content="Plain description"></head>`,
+			title: "Plain title", description: "Plain description", siteName: "example.com",
+		},
+		{
+			name:  "attributes in any order and quoting",
+			page:  `<head><META content='Single &amp; quoted' PROPERTY=og:title></head>`,
+			title: "Single & quoted", siteName: "example.com",
+		},
+		{
+			name:  "first occurrence wins",
+			page:  `<head><meta property="og:title" content="First"><meta property="og:title" content="Second"></head>`,
+			title: "First", siteName: "example.com",
+		},
+		{
+			name:  "body ignored",
+			page:  `<head><title>Head</title></head><body><meta property="og:title" content="Body"></body>`,
+			title: "Head", siteName: "example.com",
+		},
+		{
+			name:     "non-http image dropped",
+			page:     `<head><meta property="og:title" content="T"><meta property="og:image" content="javascript:alert(1)"></head>`,
+			title:    "T",
+			siteName: "example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview := parseMetadata(tt.page, pageURL)
+
+			check := func(field string, got *string, want string) {
+				t.Helper()
+				if want == "" {
+					if got != nil {
+						t.Errorf("%s = %q, want none", field, *got)
+					}
+					return
+				}
+				if got == nil || *got != want {
+					t.Errorf("%s = %v, want %q", field, got, want)
+				}
+			}
+			check("title", preview.Title, tt.title)
+			check("description", preview.Description, tt.description)
+			check("image", preview.ImageURL, tt.image)
+			check("site name", preview.SiteName, tt.siteName)
+		})
+	}
+}
+
+func TestParseMetadataTruncates(t *testing.T) {
+	page := `<head><meta property="og:title" content="` + strings.Repeat("a", maxTitleLength+50) + `"></head>`
+
+	preview := parseMetadata(page, nil)
+	if preview.Title == nil || len([]rune(*preview.Title)) != maxTitleLength {
+		t.Fatalf("got title of %d runes, want %d", len([]rune(*preview.Title)), maxTitleLength)
+	}
+	if !strings.HasSuffix(*preview.Title, "…") {
+		t.Fatal("truncated title has no ellipsis")
+	}
+}
@@ -0,0 +1,128 @@
+package linkpreview
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Fetch outcomes stored in link_previews.status
+const (
+	statusOK     = "ok"
+	statusFailed = "failed"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// fetchState is what is known about the last fetches of a URL. A zero
+// lastAttemptAt means the URL has never been fetched.
+type fetchState struct {
+	status        string
+	fetchedAt     time.Time
+	lastAttemptAt time.Time
+}
+
+// GetPreviews retrieves the successfully fetched previews of the given URLs,
+// and the fetch state of every URL that has been fetched, both keyed by URL
+func (r *Repository) GetPreviews(ctx context.Context, urls []string) (map[string]*models.LinkPreview, map[string]fetchState, error) {
+	query := `
+		SELECT url, status, title, description, image_url, site_name, fetched_at, last_attempt_at
+		FROM link_previews
+		WHERE url = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, urls)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get link previews: %w", err)
+	}
+	defer rows.Close()
+
+	previews := make(map[string]*models.LinkPreview)
+	states := make(map[string]fetchState)
+	for rows.Next() {
+		var preview models.LinkPreview
+		var state fetchState
+		err := rows.Scan(
+			&preview.URL,
+			&state.status,
+			&preview.Title,
+			&preview.Description,
+			&preview.ImageURL,
+			&preview.SiteName,
+			&preview.FetchedAt,
+			&state.lastAttemptAt,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan link preview: %w", err)
+		}
+
+		state.fetchedAt = preview.FetchedAt
+		states[preview.URL] = state
+		if state.status == statusOK {
+			previews[preview.URL] = &preview
+		}
+	}
+
+	return previews, states, rows.Err()
+}
+
+// GetFetchState returns what is known about the last fetches of a URL
+func (r *Repository) GetFetchState(ctx context.Context, url string) (fetchState, error) {
+	var state fetchState
+
+	query := `SELECT status, fetched_at, last_attempt_at FROM link_previews WHERE url = $1`
+	err := r.db.QueryRow(ctx, query, url).Scan(&state.status, &state.fetchedAt, &state.lastAttemptAt)
+	if err == pgx.ErrNoRows {
+		return fetchState{}, nil
+	}
+	if err != nil {
+		return fetchState{}, fmt.Errorf("failed to get link preview state: %w", err)
+	}
+
+	return state, nil
+}
+
+// SavePreview stores a fetched preview, replacing any previous one for the URL
+func (r *Repository) SavePreview(ctx context.Context, preview *models.LinkPreview) error {
+	query := `
+		INSERT INTO link_previews (url, status, title, description, image_url, site_name, fetched_at, last_attempt_at)
+		VALUES ($1, 'ok', $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (url) DO UPDATE SET
+			status = 'ok', title = EXCLUDED.title, description = EXCLUDED.description,
+			image_url = EXCLUDED.image_url, site_name = EXCLUDED.site_name,
+			fetched_at = NOW(), last_attempt_at = NOW()
+	`
+
+	_, err := r.db.Exec(ctx, query, preview.URL, preview.Title, preview.Description, preview.ImageURL, preview.SiteName)
+	if err != nil {
+		return fmt.Errorf("failed to save link preview: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed fetch so the URL isn't retried right away.
+// A previously successful preview is kept, along with when it was fetched.
+func (r *Repository) MarkFailed(ctx context.Context, url string) error {
+	query := `
+		INSERT INTO link_previews (url, status, fetched_at, last_attempt_at)
+		VALUES ($1, 'failed', NOW(), NOW())
+		ON CONFLICT (url) DO UPDATE SET last_attempt_at = NOW()
+	`
+
+	if _, err := r.db.Exec(ctx, query, url); err != nil {
+		return fmt.Errorf("failed to mark link preview as failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package linkpreview
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+)
+
+// maxURLLength is the longest URL that gets a preview
+const maxURLLength = 2048
+
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+type Service struct {
+	repo     *Repository
+	fetcher  *Fetcher
+	config   config.LinkPreviewConfig
+	queue    chan string
+	inFlight sync.Map // URLs queued or being fetched
+}
+
+func NewService(repo *Repository, cfg *config.Config) *Service {
+	previews := cfg.Previews
+	return NewServiceWithFetcher(repo, NewFetcher(previews.Timeout, previews.MaxBytes, previews.AllowPrivate), cfg)
+}
+
+// NewServiceWithFetcher creates a service using the given fetcher, for instance
+// one whose client targets a local test server. Its workers run for the
+// lifetime of the process.
+func NewServiceWithFetcher(repo *Repository, fetcher *Fetcher, cfg *config.Config) *Service {
+	workers := cfg.Previews.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	queueSize := cfg.Previews.QueueSize
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	s := &Service{
+		repo:    repo,
+		fetcher: fetcher,
+		config:  cfg.Previews,
+		queue:   make(chan string, queueSize),
+	}
+
+	if s.config.Enabled {
+		for i := 0; i < workers; i++ {
+			go s.work()
+		}
+	}
+
+	return s
+}
+
+// ExtractURL returns the first http(s) URL in a text, or nil when there is none
+func ExtractURL(text string) *string {
+	match := urlPattern.FindString(text)
+
+	// Punctuation right after a link usually belongs to the sentence
+	match = strings.TrimRight(match, ".,;:!?)]}'")
+	if match == "" || len(match) > maxURLLength {
+		return nil
+	}
+
+	if _, err := validateURL(match); err != nil {
+		return nil
+	}
+	return &match
+}
+
+// Enqueue fetches the preview of a URL in the background unless a recent one
+// is cached. When the queue is full the URL is dropped; it is enqueued again
+// the next time it is shared or its preview is read.
+func (s *Service) Enqueue(url string) {
+	if !s.config.Enabled {
+		return
+	}
+
+	if _, busy := s.inFlight.LoadOrStore(url, struct{}{}); busy {
+		return
+	}
+
+	select {
+	case s.queue <- url:
+	default:
+		s.inFlight.Delete(url)
+		log.Printf("Link preview queue full, dropping %s", url)
+	}
+}
+
+// work fetches the previews of queued URLs one at a time
+func (s *Service) work() {
+	for url := range s.queue {
+		// Leave room for the database calls on top of the fetch itself
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout+10*time.Second)
+		if err := s.refresh(ctx, url); err != nil {
+			log.Printf("Link preview error for %s: %v", url, err)
+		}
+		cancel()
+
+		s.inFlight.Delete(url)
+	}
+}
+
+// due reports whether a URL should be fetched: it never was, its preview is
+// stale, or its last fetch failed, each retried no sooner than RetryAfter
+func (s *Service) due(state fetchState) bool {
+	if state.lastAttemptAt.IsZero() {
+		return true
+	}
+	if state.status == statusOK && time.Since(state.fetchedAt) < s.config.CacheTTL {
+		return false
+	}
+	return time.Since(state.lastAttemptAt) >= s.config.RetryAfter
+}
+
+// refresh fetches and stores a URL's preview when it is due
+func (s *Service) refresh(ctx context.Context, url string) error {
+	state, err := s.repo.GetFetchState(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if !s.due(state) {
+		return nil
+	}
+
+	preview, err := s.fetcher.Fetch(ctx, url)
+	if err != nil {
+		log.Printf("Link preview fetch failed for %s: %v", url, err)
+		return s.repo.MarkFailed(ctx, url)
+	}
+
+	return s.repo.SavePreview(ctx, preview)
+}
+
+// GetPreviews returns the cached previews of the given URLs, keyed by URL.
+// Stale previews are still returned, and URLs due for a fetch, including
+// those dropped from a full queue, are fetched in the background.
+func (s *Service) GetPreviews(ctx context.Context, urls []string) (map[string]*models.LinkPreview, error) {
+	if len(urls) == 0 {
+		return map[string]*models.LinkPreview{}, nil
+	}
+
+	previews, states, err := s.repo.GetPreviews(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, url := range urls {
+		if s.due(states[url]) {
+			s.Enqueue(url)
+		}
+	}
+
+	return previews, nil
+}
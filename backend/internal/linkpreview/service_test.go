@@ -0,0 +1,54 @@
+package linkpreview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+)
+
+func TestServiceDue(t *testing.T) {
+	s := &Service{config: config.LinkPreviewConfig{CacheTTL: 24 * time.Hour, RetryAfter: time.Hour}}
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		state fetchState
+		want  bool
+	}{
+		{name: "never fetched", state: fetchState{}, want: true},
+		{
+			name:  "fresh preview",
+			state: fetchState{status: statusOK, fetchedAt: now.Add(-time.Hour), lastAttemptAt: now.Add(-time.Hour)},
+			want:  false,
+		},
+		{
+			name:  "stale preview",
+			state: fetchState{status: statusOK, fetchedAt: now.Add(-48 * time.Hour), lastAttemptAt: now.Add(-48 * time.Hour)},
+			want:  true,
+		},
+		{
+			name:  "stale preview whose refresh just failed",
+			state: fetchState{status: statusOK, fetchedAt: now.Add(-48 * time.Hour), lastAttemptAt: now.Add(-time.Minute)},
+			want:  false,
+		},
+		{
+			name:  "recent failure",
+			state: fetchState{status: statusFailed, fetchedAt: now.Add(-time.Minute), lastAttemptAt: now.Add(-time.Minute)},
+			want:  false,
+		},
+		{
+			name:  "old failure",
+			state: fetchState{status: statusFailed, fetchedAt: now.Add(-2 * time.Hour), lastAttemptAt: now.Add(-2 * time.Hour)},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.due(tt.state); got != tt.want {
+				t.Errorf("due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package middleware
 
 import (
+	"context"
+	"log"
+
 	"github.com/Aolakije/City-Buzz/pkg/config"
 	"github.com/Aolakije/City-Buzz/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
-// AuthMiddleware validates JWT token from httpOnly cookie
-func AuthMiddleware(cfg *config.Config) fiber.Handler {
+// SuspensionChecker tells whether a user was suspended after their token was issued
+type SuspensionChecker interface {
+	IsSuspended(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// AuthMiddleware validates JWT token from httpOnly cookie. Tokens outlive
+// suspensions, so the user must also not have been suspended since.
+func AuthMiddleware(cfg *config.Config, suspensions SuspensionChecker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		//  Allow CORS preflight requests
@@ -33,6 +43,21 @@ func AuthMiddleware(cfg *config.Config) fiber.Handler {
 			})
 		}
 
+		suspended, err := suspensions.IsSuspended(c.Context(), claims.UserID)
+		if err != nil {
+			log.Printf("Suspension status of user %s error: %v", claims.UserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"success": false,
+				"error":   "Failed to check account",
+			})
+		}
+		if suspended {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"success": false,
+				"error":   "Unauthorized - account suspended",
+			})
+		}
+
 		// Set user info in context
 		c.Locals("userID", claims.UserID.String())
 		c.Locals("username", claims.Username)
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bookmark item types
+const (
+	BookmarkTypePost    = "post"
+	BookmarkTypeEvent   = "event"
+	BookmarkTypeArticle = "article"
+)
+
+// Bookmark is an item a user saved for later. Exactly one of Post, Event or
+// Article is set, depending on Type; Post and Event are left empty when the
+// item is no longer visible to the user.
+type Bookmark struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	Type         string     `json:"type" db:"item_type"`
+	PostID       *uuid.UUID `json:"post_id,omitempty" db:"post_id"`
+	EventID      *uuid.UUID `json:"event_id,omitempty" db:"event_id"`
+	CollectionID *uuid.UUID `json:"collection_id" db:"collection_id"`
+	SavedAt      time.Time  `json:"saved_at" db:"saved_at"`
+
+	// Joined fields (not in DB)
+	Post    *Post         `json:"post,omitempty" db:"-"`
+	Event   *Event        `json:"event,omitempty" db:"-"`
+	Article *SavedArticle `json:"article,omitempty" db:"-"`
+}
+
+// BookmarkCollection is a user's folder of bookmarks
+type BookmarkCollection struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Name           string    `json:"name" db:"name"`
+	BookmarksCount int       `json:"bookmarks_count" db:"-"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBookmarkRequest represents the request to bookmark a post, event or news article.
+// ItemID is required for posts and events, the article fields for articles.
+type CreateBookmarkRequest struct {
+	Type          string     `json:"type" validate:"required,oneof=post event article"`
+	ItemID        *uuid.UUID `json:"item_id,omitempty"`
+	ArticleURL    string     `json:"article_url" validate:"omitempty,url"`
+	ArticleTitle  string     `json:"article_title" validate:"omitempty,max=500"`
+	ArticleImage  string     `json:"article_image" validate:"omitempty,url"`
+	ArticleSource string     `json:"article_source" validate:"omitempty,max=200"`
+	CollectionID  *uuid.UUID `json:"collection_id,omitempty"`
+}
+
+// MoveBookmarkRequest represents the request to move a bookmark into a
+// collection, or out of any collection when CollectionID is null
+type MoveBookmarkRequest struct {
+	CollectionID *uuid.UUID `json:"collection_id"`
+}
+
+// BookmarkCollectionRequest represents the request to create or rename a collection
+type BookmarkCollectionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Conversation types
+const (
+	ConversationDirect = "direct"
+	ConversationGroup  = "group"
+)
+
+// Roles of the members of a group conversation
+const (
+	ConversationRoleAdmin  = "admin" // Can rename the group and manage its members
+	ConversationRoleMember = "member"
+)
+
+// Message kinds: text sent by a member, or a system message recording a change
+// made by the sender to the group or to its subject member
+const (
+	MessageKindText          = "text"
+	MessageKindGroupCreated  = "group_created"
+	MessageKindGroupUpdated  = "group_updated"
+	MessageKindMemberAdded   = "member_added"
+	MessageKindMemberJoined  = "member_joined" // Joined an event's group by RSVPing
+	MessageKindMemberRemoved = "member_removed"
+	MessageKindMemberLeft    = "member_left"
+	MessageKindRoleChanged   = "role_changed"
+)
+
+// Conversation is a thread of messages between its members: a direct
+// conversation between two users, or a named group
+type Conversation struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	Type          string     `json:"type" db:"type"`
+	Name          *string    `json:"name,omitempty" db:"name"`
+	AvatarURL     *string    `json:"avatar_url,omitempty" db:"avatar_url"`
+	EventID       *uuid.UUID `json:"event_id,omitempty" db:"event_id"` // Event whose attendees make up the group
+	LastMessageAt *time.Time `json:"last_message_at,omitempty" db:"last_message_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields (not in DB)
+	Role        string               `json:"role" db:"-"`         // The user's role
+	Members     []ConversationMember `json:"members" db:"-"`      // Members other than the user
+	LastMessage *Message             `json:"last_message" db:"-"` // Nil until the first message
+	UnreadCount int                  `json:"unread_count" db:"-"`
+}
+
+// ConversationMember is a user taking part in a conversation
+type ConversationMember struct {
+	UserResponse
+	Role       string     `json:"role"`
+	JoinedAt   time.Time  `json:"joined_at"`
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// Message is sent by a member of a conversation, with text, an attachment or
+// both. System messages have another kind and record membership changes.
+type Message struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	ConversationID uuid.UUID  `json:"conversation_id" db:"conversation_id"`
+	Kind           string     `json:"kind" db:"kind"`
+	SenderID       *uuid.UUID `json:"sender_id" db:"sender_id"` // Nil once the sender's account is deleted
+	SubjectID      *uuid.UUID `json:"subject_id,omitempty" db:"subject_id"`
+	Content        *string    `json:"content,omitempty" db:"content"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields (not in DB)
+	Attachment *MessageAttachment `json:"attachment,omitempty" db:"-"`
+	Sender     *UserResponse      `json:"sender,omitempty" db:"-"`
+	Subject    *UserResponse      `json:"subject,omitempty" db:"-"`
+	Receipts   []MessageReceipt   `json:"receipts,omitempty" db:"-"` // On the user's own messages
+}
+
+// Receipt statuses
+const (
+	ReceiptDelivered = "delivered"
+	ReceiptRead      = "read"
+)
+
+// MessageReceipt tells when a member received and read a message
+type MessageReceipt struct {
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	DeliveredAt time.Time  `json:"delivered_at" db:"delivered_at"`
+	ReadAt      *time.Time `json:"read_at,omitempty" db:"read_at"`
+}
+
+// ReceiptUpdate tells a sender that a member received or read some of their messages
+type ReceiptUpdate struct {
+	ConversationID uuid.UUID   `json:"conversation_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+	Status         string      `json:"status"`
+	MessageIDs     []uuid.UUID `json:"message_ids"`
+	At             time.Time   `json:"at"`
+}
+
+// MessageAttachment is an uploaded file sent with a message
+type MessageAttachment struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	URL         string    `json:"url" db:"url"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+}
+
+// Upload is a file a user uploaded
+type Upload struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	URL         string    `json:"url" db:"url"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// StartConversationRequest opens the direct conversation with a user
+type StartConversationRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=20"`
+}
+
+// SendMessageRequest sends text, an uploaded attachment or both
+type SendMessageRequest struct {
+	Content      string     `json:"content" validate:"max=2000"`
+	AttachmentID *uuid.UUID `json:"attachment_id"`
+}
+
+// MarkReadRequest marks a conversation as read up to a message, or entirely without one
+type MarkReadRequest struct {
+	MessageID *uuid.UUID `json:"message_id"`
+}
+
+// CreateGroupRequest creates a group with its first members besides its creator
+type CreateGroupRequest struct {
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	AvatarID  *uuid.UUID `json:"avatar_id"` // Uploaded image
+	Usernames []string   `json:"usernames" validate:"max=50,dive,min=3,max=20"`
+}
+
+// UpdateGroupRequest renames a group or changes its avatar
+type UpdateGroupRequest struct {
+	Name     *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	AvatarID *uuid.UUID `json:"avatar_id"` // Uploaded image
+}
+
+// AddMembersRequest adds users to a group
+type AddMembersRequest struct {
+	Usernames []string `json:"usernames" validate:"required,min=1,max=50,dive,min=3,max=20"`
+}
+
+// UpdateMemberRoleRequest makes a member an admin of a group, or a plain member again
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin member"`
+}
@@ -15,6 +15,8 @@ type Event struct {
 	EndDate          *time.Time `json:"end_date,omitempty" db:"end_date"`
 	Location         string     `json:"location" db:"location"`
 	Address          *string    `json:"address,omitempty" db:"address"`
+	Latitude         *float64   `json:"latitude,omitempty" db:"latitude"`
+	Longitude        *float64   `json:"longitude,omitempty" db:"longitude"`
 	City             string     `json:"city" db:"city"`
 	Category         string     `json:"category" db:"category"`
 	EventType        *string    `json:"event_type,omitempty" db:"event_type"`
@@ -33,6 +35,11 @@ type Event struct {
 	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 	IsDeleted        bool       `json:"is_deleted" db:"is_deleted"`
+	IsHeld           bool       `json:"is_held" db:"is_held"` // Held for moderator review by the content filters
+	HeldReasons      []string   `json:"-" db:"held_reasons"`
+
+	// Joined fields (not in DB)
+	DistanceKm *float64 `json:"distance_km,omitempty" db:"-"` // Distance to the searched point, in nearby queries
 }
 
 // EventRSVP represents a user's RSVP to an event
@@ -53,6 +60,8 @@ type CreateEventRequest struct {
 	EndDate          *time.Time `json:"end_date,omitempty"`
 	Location         string     `json:"location" validate:"required"`
 	Address          *string    `json:"address,omitempty"`
+	Latitude         *float64   `json:"latitude,omitempty" validate:"omitempty,gte=-90,lte=90"`
+	Longitude        *float64   `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
 	City             string     `json:"city" validate:"required"`
 	Category         string     `json:"category" validate:"required,oneof=concerts festivals sports culture markets nightlife clubs"`
 	EventType        *string    `json:"event_type,omitempty" validate:"omitempty,oneof=party concert gaming hangout reading hiking travel show art sports dining coffee workshop networking movie outdoor"`
@@ -73,6 +82,8 @@ type UpdateEventRequest struct {
 	EndDate          *time.Time `json:"end_date,omitempty"`
 	Location         *string    `json:"location,omitempty"`
 	Address          *string    `json:"address,omitempty"`
+	Latitude         *float64   `json:"latitude,omitempty" validate:"omitempty,gte=-90,lte=90"`
+	Longitude        *float64   `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
 	City             *string    `json:"city,omitempty"`
 	Category         *string    `json:"category,omitempty" validate:"omitempty,oneof=concerts festivals sports culture markets nightlife clubs"`
 	EventType        *string    `json:"event_type,omitempty" validate:"omitempty,oneof=party concert gaming hangout reading hiking travel show art sports dining coffee workshop networking movie outdoor"`
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// LinkPreview holds the OpenGraph / Twitter Card metadata of a URL shared in a post
+type LinkPreview struct {
+	URL         string    `json:"url" db:"url"`
+	Title       *string   `json:"title,omitempty" db:"title"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	ImageURL    *string   `json:"image_url,omitempty" db:"image_url"`
+	SiteName    *string   `json:"site_name,omitempty" db:"site_name"`
+	FetchedAt   time.Time `json:"fetched_at" db:"fetched_at"`
+}
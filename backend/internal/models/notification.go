@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification types
+const (
+	NotificationLike           = "like"
+	NotificationComment        = "comment"
+	NotificationReply          = "reply"
+	NotificationMention        = "mention"
+	NotificationFollow         = "follow"
+	NotificationRSVP           = "rsvp"
+	NotificationEventUpdated   = "event_updated"
+	NotificationEventCancelled = "event_cancelled"
+	NotificationEventReminder  = "event_reminder"
+)
+
+// Notification target types
+const (
+	NotificationTargetPost    = "post"
+	NotificationTargetComment = "comment"
+	NotificationTargetEvent   = "event"
+	NotificationTargetUser    = "user"
+)
+
+// Notification tells a user that others acted on something of theirs, or, without
+// actors, reminds them of something. Actions of the same type on the same target
+// are grouped while the notification is unread.
+type Notification struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Type       string     `json:"type" db:"type"`
+	TargetType string     `json:"target_type" db:"target_type"`
+	TargetID   uuid.UUID  `json:"target_id" db:"target_id"`
+	PostID     *uuid.UUID `json:"post_id,omitempty" db:"post_id"` // Post of a comment target
+	ReadAt     *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"` // Time of the latest grouped action
+
+	// Joined fields (not in DB)
+	Actors      []UserResponse `json:"actors" db:"-"` // Most recent first, a few at most
+	ActorsCount int            `json:"actors_count" db:"-"`
+	IsRead      bool           `json:"is_read" db:"-"`
+	Message     string         `json:"message" db:"-"`
+}
+
+// NotificationTypes lists every notification type, in the order settings are shown
+var NotificationTypes = []string{
+	NotificationLike,
+	NotificationComment,
+	NotificationReply,
+	NotificationMention,
+	NotificationFollow,
+	NotificationRSVP,
+	NotificationEventUpdated,
+	NotificationEventCancelled,
+	NotificationEventReminder,
+}
+
+// Email digest frequencies
+const (
+	DigestNone   = "none"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// NotificationPreference selects the channels a type of notification is
+// delivered through; with every channel off the type is not delivered at all
+type NotificationPreference struct {
+	Type  string `json:"type" db:"type" validate:"required,oneof=like comment reply mention follow rsvp event_updated event_cancelled event_reminder"`
+	InApp bool   `json:"in_app" db:"in_app"`
+	Email bool   `json:"email" db:"email"`
+	Push  bool   `json:"push" db:"push"`
+}
+
+// NotificationSettings gathers a user's notification preferences
+type NotificationSettings struct {
+	Preferences []NotificationPreference `json:"preferences"`
+	Digest      string                   `json:"digest"`
+}
+
+// UpdateNotificationSettingsRequest changes the preferences of the given types
+// and, when set, the digest frequency
+type UpdateNotificationSettingsRequest struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"omitempty,max=20,dive"`
+	Digest      *string                  `json:"digest" validate:"omitempty,oneof=none daily weekly"`
+}
+
+// PushSubscription is a browser subscribed to Web Push notifications
+type PushSubscription struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Endpoint   string     `json:"endpoint" db:"endpoint"`
+	P256dh     string     `json:"-" db:"p256dh"`
+	Auth       string     `json:"-" db:"auth"`
+	UserAgent  *string    `json:"user_agent,omitempty" db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// SubscribePushRequest is a browser's PushSubscription, as serialized by toJSON()
+type SubscribePushRequest struct {
+	Endpoint string `json:"endpoint" validate:"required,url,max=2048"`
+	Keys     struct {
+		P256dh string `json:"p256dh" validate:"required,max=255"`
+		Auth   string `json:"auth" validate:"required,max=255"`
+	} `json:"keys"`
+}
+
+// UnsubscribePushRequest identifies the subscription of a browser by its endpoint
+type UnsubscribePushRequest struct {
+	Endpoint string `json:"endpoint" validate:"required,max=2048"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll represents a poll attached to a post
+type Poll struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	PostID         uuid.UUID    `json:"post_id" db:"post_id"`
+	AllowsMultiple bool         `json:"allows_multiple" db:"allows_multiple"`
+	ClosesAt       time.Time    `json:"closes_at" db:"closes_at"`
+	VotersCount    int          `json:"voters_count" db:"voters_count"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	Options        []PollOption `json:"options" db:"-"`
+
+	// Joined fields (not in DB)
+	IsClosed       bool        `json:"is_closed" db:"-"`
+	VotedOptionIDs []uuid.UUID `json:"voted_option_ids" db:"-"`
+}
+
+// PollOption represents one answer of a poll with its live tally
+type PollOption struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	PollID     uuid.UUID `json:"poll_id" db:"poll_id"`
+	Position   int       `json:"position" db:"position"`
+	Text       string    `json:"text" db:"text"`
+	VotesCount int       `json:"votes_count" db:"votes_count"`
+}
+
+// CreatePollRequest represents poll input when creating a post
+type CreatePollRequest struct {
+	Options        []string  `json:"options" validate:"required,min=2,max=6,dive,required,min=1,max=100"`
+	ClosesAt       time.Time `json:"closes_at" validate:"required"`
+	AllowsMultiple bool      `json:"allows_multiple"`
+}
+
+// PollVoteRequest represents a user's ballot
+type PollVoteRequest struct {
+	OptionIDs []uuid.UUID `json:"option_ids" validate:"required,min=1,max=6"`
+}
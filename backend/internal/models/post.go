@@ -8,40 +8,131 @@ import (
 
 // Post represents a user post
 type Post struct {
-	ID            uuid.UUID `json:"id" db:"id"`
-	UserID        uuid.UUID `json:"user_id" db:"user_id"`
-	Content       string    `json:"content" db:"content"`
-	LikesCount    int       `json:"likes_count" db:"likes_count"`
-	CommentsCount int       `json:"comments_count" db:"comments_count"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
-	IsDeleted     bool      `json:"is_deleted" db:"is_deleted"`
+	ID              uuid.UUID      `json:"id" db:"id"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id"`
+	Content         string         `json:"content" db:"content"`
+	LikesCount      int            `json:"likes_count" db:"likes_count"`
+	CommentsCount   int            `json:"comments_count" db:"comments_count"`
+	RepostCount     int            `json:"repost_count" db:"repost_count"`
+	ReactionCounts  map[string]int `json:"reaction_counts" db:"reaction_counts"`
+	Visibility      string         `json:"visibility" db:"visibility"`
+	Status          string         `json:"status" db:"status"`
+	ScheduledAt     *time.Time     `json:"scheduled_at,omitempty" db:"scheduled_at"`
+	City            *string        `json:"city,omitempty" db:"city"`
+	LinkURL         *string        `json:"link_url,omitempty" db:"link_url"` // First URL in the content, previewed when possible
+	RepostOfID      *uuid.UUID     `json:"repost_of_id,omitempty" db:"repost_of_id"`
+	EventID         *uuid.UUID     `json:"event_id,omitempty" db:"event_id"`
+	EventPinnedAt   *time.Time     `json:"event_pinned_at,omitempty" db:"event_pinned_at"`     // Pinned by the event's organizer
+	ProfilePinnedAt *time.Time     `json:"profile_pinned_at,omitempty" db:"profile_pinned_at"` // Pinned to the author's profile
+	Latitude        *float64       `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64       `json:"longitude,omitempty" db:"longitude"`
+	PlaceName       *string        `json:"place_name,omitempty" db:"place_name"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	EditedAt        *time.Time     `json:"edited_at,omitempty" db:"edited_at"`
+	IsDeleted       bool           `json:"is_deleted" db:"is_deleted"`
+	DeletedAt       *time.Time     `json:"deleted_at,omitempty" db:"deleted_at"`
+	IsHeld          bool           `json:"is_held" db:"is_held"` // Held for moderator review by the content filters
+	HeldReasons     []string       `json:"-" db:"held_reasons"`
 
 	// Joined fields (not in DB)
-	Author   *UserResponse `json:"author,omitempty" db:"-"`
-	IsLiked  bool          `json:"is_liked" db:"-"`
-	Comments []Comment     `json:"comments,omitempty" db:"-"`
+	Author      *UserResponse `json:"author,omitempty" db:"-"`
+	IsLiked     bool          `json:"is_liked" db:"-"`
+	MyReaction  *string       `json:"my_reaction" db:"-"`
+	IsEdited    bool          `json:"is_edited" db:"-"`
+	IsReposted  bool          `json:"is_reposted" db:"-"`
+	RepostOf    *Post         `json:"repost_of,omitempty" db:"-"`
+	Poll        *Poll         `json:"poll,omitempty" db:"-"`
+	LinkPreview *LinkPreview  `json:"link_preview,omitempty" db:"-"`
+	AuthorRSVP  *string       `json:"author_rsvp,omitempty" db:"-"` // Author's RSVP to the post's event, in event threads
+	DistanceKm  *float64      `json:"distance_km,omitempty" db:"-"` // Distance to the searched point, in nearby queries
+	Comments    []Comment     `json:"comments,omitempty" db:"-"`
 }
 
+// IsPublished reports whether the post is live rather than a draft or scheduled post
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished
+}
+
+// IsPlainRepost reports whether the post re-shares another post without commentary
+func (p *Post) IsPlainRepost() bool {
+	return p.RepostOfID != nil && p.Content == ""
+}
+
+// Post publication statuses
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+)
+
+// Post visibility levels
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+)
+
 // Comment represents a comment on a post
 type Comment struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	PostID     uuid.UUID `json:"post_id" db:"post_id"`
-	UserID     uuid.UUID `json:"user_id" db:"user_id"`
-	Content    string    `json:"content" db:"content"`
-	LikesCount int       `json:"likes_count" db:"likes_count"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
-	IsDeleted  bool      `json:"is_deleted" db:"is_deleted"`
+	ID             uuid.UUID      `json:"id" db:"id"`
+	PostID         uuid.UUID      `json:"post_id" db:"post_id"`
+	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
+	Content        string         `json:"content" db:"content"`
+	LikesCount     int            `json:"likes_count" db:"likes_count"`
+	ReactionCounts map[string]int `json:"reaction_counts" db:"reaction_counts"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	EditedAt       *time.Time     `json:"edited_at,omitempty" db:"edited_at"`
+	IsDeleted      bool           `json:"is_deleted" db:"is_deleted"`
+	IsHeld         bool           `json:"is_held" db:"is_held"` // Held for moderator review by the content filters
+	HeldReasons    []string       `json:"-" db:"held_reasons"`
 
 	// Joined fields
-	Author  *UserResponse `json:"author,omitempty" db:"-"`
-	IsLiked bool          `json:"is_liked" db:"-"`
+	Author     *UserResponse `json:"author,omitempty" db:"-"`
+	IsLiked    bool          `json:"is_liked" db:"-"`
+	MyReaction *string       `json:"my_reaction" db:"-"`
+	IsEdited   bool          `json:"is_edited" db:"-"`
+}
+
+// Revision represents a previous version of a post or comment's content
+type Revision struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Content   string     `json:"content" db:"content"`
+	EditedBy  *uuid.UUID `json:"edited_by,omitempty" db:"edited_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
 
 // CreatePostRequest represents post creation input
 type CreatePostRequest struct {
-	Content string `json:"content" validate:"required,min=1,max=5000"`
+	Content    string             `json:"content" validate:"required,min=1,max=5000"`
+	Visibility string             `json:"visibility" validate:"omitempty,oneof=public followers private"`
+	City       *string            `json:"city" validate:"omitempty,min=1,max=100"`
+	Poll       *CreatePollRequest `json:"poll,omitempty" validate:"omitempty"`
+	EventID    *uuid.UUID         `json:"event_id,omitempty"`
+	Location   *PostLocation      `json:"location,omitempty" validate:"omitempty"`
+
+	// Draft keeps the post unpublished; ScheduledAt publishes it automatically at that time
+	Draft       bool       `json:"draft"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+}
+
+// RepostRequest represents repost input; content turns the repost into a quote post
+type RepostRequest struct {
+	Content    string `json:"content" validate:"max=5000"`
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public followers private"`
+}
+
+// PostLocation is the place a post is tagged with
+type PostLocation struct {
+	Latitude  float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	PlaceName *string `json:"place_name,omitempty" validate:"omitempty,min=1,max=200"`
+}
+
+// SchedulePostRequest represents the time at which a draft should be published
+type SchedulePostRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" validate:"required"`
 }
 
 // UpdatePostRequest represents post update input
@@ -54,6 +145,19 @@ type CreateCommentRequest struct {
 	Content string `json:"content" validate:"required,min=1,max=2000"`
 }
 
+// UpdateCommentRequest represents comment update input
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=2000"`
+}
+
+// ReactionRequest represents a reaction on a post or comment
+type ReactionRequest struct {
+	Reaction string `json:"reaction" validate:"required,max=20"`
+}
+
+// ReactionLike is the reaction behind the like endpoints and likes_count
+const ReactionLike = "like"
+
 // FeedQuery represents feed query parameters
 type FeedQuery struct {
 	Page  int `query:"page" validate:"min=1"`
@@ -62,23 +166,37 @@ type FeedQuery struct {
 
 // PostResponse represents a post with author info
 type PostResponse struct {
-	ID            uuid.UUID     `json:"id"`
-	Content       string        `json:"content"`
-	LikesCount    int           `json:"likes_count"`
-	CommentsCount int           `json:"comments_count"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
-	Author        *UserResponse `json:"author"`
-	IsLiked       bool          `json:"is_liked"`
+	ID             uuid.UUID      `json:"id"`
+	Content        string         `json:"content"`
+	LikesCount     int            `json:"likes_count"`
+	CommentsCount  int            `json:"comments_count"`
+	RepostCount    int            `json:"repost_count"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+	Visibility     string         `json:"visibility"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Author         *UserResponse  `json:"author"`
+	IsLiked        bool           `json:"is_liked"`
+	MyReaction     *string        `json:"my_reaction"`
+	IsEdited       bool           `json:"is_edited"`
+	IsReposted     bool           `json:"is_reposted"`
+	IsHeld         bool           `json:"is_held"`
+	RepostOf       *PostResponse  `json:"repost_of,omitempty"`
+	Poll           *Poll          `json:"poll,omitempty"`
+	LinkPreview    *LinkPreview   `json:"link_preview,omitempty"`
 }
 
 // CommentResponse represents a comment with author info
 type CommentResponse struct {
-	ID         uuid.UUID     `json:"id"`
-	PostID     uuid.UUID     `json:"post_id"`
-	Content    string        `json:"content"`
-	LikesCount int           `json:"likes_count"`
-	CreatedAt  time.Time     `json:"created_at"`
-	Author     *UserResponse `json:"author"`
-	IsLiked    bool          `json:"is_liked"`
+	ID             uuid.UUID      `json:"id"`
+	PostID         uuid.UUID      `json:"post_id"`
+	Content        string         `json:"content"`
+	LikesCount     int            `json:"likes_count"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Author         *UserResponse  `json:"author"`
+	IsLiked        bool           `json:"is_liked"`
+	MyReaction     *string        `json:"my_reaction"`
+	IsEdited       bool           `json:"is_edited"`
+	IsHeld         bool           `json:"is_held"`
 }
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report represents a user's report against a post, comment, event or user
+type Report struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	ReporterID uuid.UUID  `json:"reporter_id" db:"reporter_id"`
+	TargetType string     `json:"target_type" db:"target_type"`
+	TargetID   uuid.UUID  `json:"target_id" db:"target_id"`
+	Reason     string     `json:"reason" db:"reason"`
+	Details    *string    `json:"details,omitempty" db:"details"`
+	Status     string     `json:"status" db:"status"`
+	ReviewedBy *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Reporter     *UserResponse `json:"reporter,omitempty" db:"-"`
+	ReportsCount int           `json:"reports_count" db:"-"` // Pending reports on the same target
+}
+
+// ModerationAction represents an entry in the moderation audit trail
+type ModerationAction struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	ModeratorID *uuid.UUID `json:"moderator_id,omitempty" db:"moderator_id"`
+	ReportID    *uuid.UUID `json:"report_id,omitempty" db:"report_id"`
+	TargetType  string     `json:"target_type" db:"target_type"`
+	TargetID    uuid.UUID  `json:"target_id" db:"target_id"`
+	Action      string     `json:"action" db:"action"`
+	Note        *string    `json:"note,omitempty" db:"note"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Moderator *UserResponse `json:"moderator,omitempty" db:"-"`
+}
+
+// HeldItem is a post, comment or event held for review by the content filters
+type HeldItem struct {
+	Type      string    `json:"type"`
+	ID        uuid.UUID `json:"id"`
+	AuthorID  uuid.UUID `json:"author_id"`
+	Content   string    `json:"content"`
+	Reasons   []string  `json:"reasons"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Joined fields
+	Author *UserResponse `json:"author,omitempty"`
+}
+
+// Report target types
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetEvent   = "event"
+	ReportTargetUser    = "user"
+)
+
+// Report statuses
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+// Moderation actions
+const (
+	ModerationDismiss = "dismiss"
+	ModerationHide    = "hide"
+	ModerationDelete  = "delete"
+	ModerationSuspend = "suspend"
+	ModerationApprove = "approve"
+	ModerationReject  = "reject"
+)
+
+// CreateReportRequest represents report creation input
+type CreateReportRequest struct {
+	TargetType string    `json:"target_type" validate:"required,oneof=post comment event user"`
+	TargetID   uuid.UUID `json:"target_id" validate:"required"`
+	Reason     string    `json:"reason" validate:"required,oneof=spam harassment hate_speech violence nudity misinformation other"`
+	Details    *string   `json:"details" validate:"omitempty,max=1000"`
+}
+
+// DismissReportRequest represents a moderator dismissing a report
+type DismissReportRequest struct {
+	Note *string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// ReviewHeldRequest represents a moderator approving or rejecting held content
+type ReviewHeldRequest struct {
+	Note *string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// ModerationActionRequest represents a moderator acting on a report
+type ModerationActionRequest struct {
+	Action string  `json:"action" validate:"required,oneof=hide delete suspend"`
+	Note   *string `json:"note" validate:"omitempty,max=1000"`
+}
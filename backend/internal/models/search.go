@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Search result types
+const (
+	SearchTypePost  = "post"
+	SearchTypeEvent = "event"
+	SearchTypeUser  = "user"
+)
+
+// SearchResult is a single full-text search hit. Exactly one of Post, Event or
+// User is set, depending on Type. Highlight is an HTML-escaped excerpt with
+// matching terms wrapped in <mark> tags.
+type SearchResult struct {
+	Type      string        `json:"type"`
+	ID        uuid.UUID     `json:"id"`
+	Rank      float32       `json:"rank"`
+	Highlight string        `json:"highlight"`
+	Post      *Post         `json:"post,omitempty"`
+	Event     *Event        `json:"event,omitempty"`
+	User      *UserResponse `json:"user,omitempty"`
+}
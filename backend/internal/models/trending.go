@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// Trending topic kinds
+const (
+	TopicKindHashtag = "hashtag"
+	TopicKindKeyword = "keyword"
+)
+
+// TrendingTopic is a hashtag or keyword trending in recent posts
+type TrendingTopic struct {
+	City        string    `json:"city" db:"city"`
+	Kind        string    `json:"kind" db:"kind"`
+	Topic       string    `json:"topic" db:"topic"`
+	Score       float64   `json:"score" db:"score"`
+	PostCount   int       `json:"post_count" db:"post_count"`
+	AuthorCount int       `json:"author_count" db:"author_count"`
+	ComputedAt  time.Time `json:"computed_at" db:"computed_at"`
+}
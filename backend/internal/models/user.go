@@ -21,6 +21,7 @@ type User struct {
 	AvatarURL     *string    `json:"avatar_url,omitempty" db:"avatar_url"`
 	Language      string     `json:"language" db:"language"`
 	ConfirmMethod *string    `json:"confirm_method,omitempty" db:"confirm_method"`
+	Role          string     `json:"role" db:"role"`
 	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
 	LastLogin     *time.Time `json:"last_login,omitempty" db:"last_login"`
@@ -42,6 +43,18 @@ type RegisterRequest struct {
 	ConfirmMethod *string `json:"confirm_method" validate:"omitempty,oneof=email phone"`
 }
 
+// User roles
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// IsModerator reports whether the user can moderate content
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
 // LoginRequest represents user login input
 type LoginRequest struct {
 	Identifier string `json:"identifier" validate:"required"` // Email, phone, or username
@@ -83,3 +96,30 @@ func (u *User) ToResponse() *UserResponse {
 		LastLogin:   u.LastLogin,
 	}
 }
+
+// SelfResponse is the user data sent to the signed-in user about themselves.
+// Only they see their role; UserResponse is shared with other users.
+type SelfResponse struct {
+	UserResponse
+	Role string `json:"role"`
+}
+
+// ToSelfResponse converts User to SelfResponse (removes sensitive data)
+func (u *User) ToSelfResponse() *SelfResponse {
+	return &SelfResponse{
+		UserResponse: *u.ToResponse(),
+		Role:         u.Role,
+	}
+}
+
+// Who can send direct messages to a user
+const (
+	MessagePrivacyEveryone  = "everyone"
+	MessagePrivacyFollowing = "following" // Only the users they follow
+	MessagePrivacyNobody    = "nobody"
+)
+
+// PrivacySettings are a user's privacy choices
+type PrivacySettings struct {
+	MessagePrivacy string `json:"message_privacy" validate:"required,oneof=everyone following nobody"`
+}
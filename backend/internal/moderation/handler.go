@@ -0,0 +1,307 @@
+package moderation
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateReport handles reporting a post, comment, event or user
+// POST /api/v1/reports
+func (h *Handler) CreateReport(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.service.CreateReport(c.Context(), userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "target not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Reported content not found")
+		case "you cannot report yourself":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot report yourself")
+		case "already reported":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "You already reported this")
+		}
+		log.Printf("Create report error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create report")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Report submitted", fiber.Map{
+		"report": report,
+	})
+}
+
+// GetReports handles retrieval of the moderation queue
+// GET /api/v1/moderation/reports?status=pending&page=1&limit=20
+func (h *Handler) GetReports(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	status := c.Query("status", models.ReportStatusPending)
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	reports, err := h.service.GetReports(c.Context(), userID, status, page, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Moderator role required")
+		}
+		if err.Error() == "invalid status" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid status")
+		}
+		log.Printf("Get reports error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get reports")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"reports": reports,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+// DismissReport handles dismissing a report
+// POST /api/v1/moderation/reports/:id/dismiss
+func (h *Handler) DismissReport(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	reportID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid report ID")
+	}
+
+	var req models.DismissReportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.DismissReport(c.Context(), userID, reportID, req.Note); err != nil {
+		return moderationError(c, err, "Failed to dismiss report")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Report dismissed", nil)
+}
+
+// ActOnReport handles hiding or deleting reported content, or suspending its author
+// POST /api/v1/moderation/reports/:id/action
+func (h *Handler) ActOnReport(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	reportID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid report ID")
+	}
+
+	var req models.ModerationActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.ActOnReport(c.Context(), userID, reportID, &req); err != nil {
+		return moderationError(c, err, "Failed to apply moderation action")
+	}
+
+	log.Printf("Moderation action %s on report %s by User=%s", req.Action, reportID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Moderation action applied", nil)
+}
+
+// GetActions handles retrieval of the moderation audit trail
+// GET /api/v1/moderation/actions?page=1&limit=20
+func (h *Handler) GetActions(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	actions, err := h.service.GetActions(c.Context(), userID, page, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Moderator role required")
+		}
+		log.Printf("Get moderation actions error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get moderation actions")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"actions": actions,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+// GetHeldItems handles retrieval of content held by the content filters
+// GET /api/v1/moderation/held?type=post&page=1&limit=20
+func (h *Handler) GetHeldItems(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	itemType := c.Query("type", models.ReportTargetPost)
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	items, err := h.service.GetHeldItems(c.Context(), userID, itemType, page, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Moderator role required")
+		}
+		if err.Error() == "invalid type" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Type must be post, comment or event")
+		}
+		log.Printf("Get held items error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get held items")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"items": items,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ApproveHeldItem handles publishing held content
+// POST /api/v1/moderation/held/:type/:id/approve
+func (h *Handler) ApproveHeldItem(c *fiber.Ctx) error {
+	return h.reviewHeldItem(c, models.ModerationApprove)
+}
+
+// RejectHeldItem handles deleting held content
+// POST /api/v1/moderation/held/:type/:id/reject
+func (h *Handler) RejectHeldItem(c *fiber.Ctx) error {
+	return h.reviewHeldItem(c, models.ModerationReject)
+}
+
+// reviewHeldItem approves or rejects the held item named in the route
+func (h *Handler) reviewHeldItem(c *fiber.Ctx, action string) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	itemID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	var req models.ReviewHeldRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if action == models.ModerationApprove {
+		err = h.service.ApproveHeldItem(c.Context(), userID, c.Params("type"), itemID, req.Note)
+	} else {
+		err = h.service.RejectHeldItem(c.Context(), userID, c.Params("type"), itemID, req.Note)
+	}
+
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Moderator role required")
+		case err.Error() == "invalid type":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Type must be post, comment or event")
+		case err.Error() == "held item not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Held item not found")
+		}
+		log.Printf("Review held item error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to review held item")
+	}
+
+	if action == models.ModerationApprove {
+		return utils.SuccessResponse(c, fiber.StatusOK, "Content approved", nil)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, "Content rejected", nil)
+}
+
+// moderationError maps errors from resolving a report to HTTP responses
+func moderationError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Moderator role required")
+	case err.Error() == "report not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Report not found")
+	case err.Error() == "target not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Reported content no longer exists")
+	case err.Error() == "report already resolved":
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Report already resolved")
+	case err.Error() == "action not supported for this target",
+		err.Error() == "target has no author",
+		err.Error() == "moderators cannot be suspended":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	log.Printf("Moderation error: %v", err)
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, fallback)
+}
@@ -0,0 +1,436 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// authorQueries look up the author of each reportable target. Only content that
+// is still visible can be reported or acted upon.
+var authorQueries = map[string]string{
+	models.ReportTargetPost:    `SELECT user_id FROM posts WHERE id = $1 AND is_deleted = false AND is_hidden = false AND status = 'published'`,
+	models.ReportTargetComment: `SELECT user_id FROM comments WHERE id = $1 AND is_deleted = false AND is_hidden = false`,
+	models.ReportTargetEvent:   `SELECT created_by FROM events WHERE id = $1 AND is_deleted = false AND is_hidden = false`,
+	models.ReportTargetUser:    `SELECT id FROM users WHERE id = $1 AND is_active = true`,
+}
+
+// GetTargetAuthor returns the author of a reported target. The author is nil
+// for events imported from external sources.
+func (r *Repository) GetTargetAuthor(ctx context.Context, targetType string, targetID uuid.UUID) (*uuid.UUID, error) {
+	query, ok := authorQueries[targetType]
+	if !ok {
+		return nil, fmt.Errorf("invalid target type")
+	}
+
+	var authorID *uuid.UUID
+	err := r.db.QueryRow(ctx, query, targetID).Scan(&authorID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("target not found")
+		}
+		return nil, fmt.Errorf("failed to get report target: %w", err)
+	}
+
+	return authorID, nil
+}
+
+// CreateReport stores a new report
+func (r *Repository) CreateReport(ctx context.Context, report *models.Report) error {
+	query := `
+		INSERT INTO reports (reporter_id, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (reporter_id, target_type, target_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id, status, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		report.ReporterID, report.TargetType, report.TargetID, report.Reason, report.Details,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("already reported")
+		}
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	return nil
+}
+
+// GetReports retrieves reports with the given status, oldest first so the queue
+// is worked through in order
+func (r *Repository) GetReports(ctx context.Context, status string, limit, offset int) ([]models.Report, error) {
+	query := `
+		SELECT rp.id, rp.reporter_id, rp.target_type, rp.target_id, rp.reason, rp.details,
+		       rp.status, rp.reviewed_by, rp.reviewed_at, rp.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url,
+		       (SELECT COUNT(*) FROM reports o
+		        WHERE o.target_type = rp.target_type AND o.target_id = rp.target_id AND o.status = 'pending') as reports_count
+		FROM reports rp
+		JOIN users u ON rp.reporter_id = u.id
+		WHERE rp.status = $1
+		ORDER BY rp.created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []models.Report
+	for rows.Next() {
+		var report models.Report
+		var reporter models.UserResponse
+
+		err := rows.Scan(
+			&report.ID,
+			&report.ReporterID,
+			&report.TargetType,
+			&report.TargetID,
+			&report.Reason,
+			&report.Details,
+			&report.Status,
+			&report.ReviewedBy,
+			&report.ReviewedAt,
+			&report.CreatedAt,
+			&reporter.ID,
+			&reporter.Username,
+			&reporter.FirstName,
+			&reporter.LastName,
+			&reporter.AvatarURL,
+			&report.ReportsCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+
+		report.Reporter = &reporter
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// GetReportByID retrieves a report by ID
+func (r *Repository) GetReportByID(ctx context.Context, reportID uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, details,
+		       status, reviewed_by, reviewed_at, created_at
+		FROM reports
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, reportID).Scan(
+		&report.ID,
+		&report.ReporterID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.Reason,
+		&report.Details,
+		&report.Status,
+		&report.ReviewedBy,
+		&report.ReviewedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("report not found")
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// ResolveReport applies a moderation action to the report's target, closes every
+// pending report on that target and records the decision in the audit trail.
+// authorID is the target's author and is only used by the suspend action.
+func (r *Repository) ResolveReport(ctx context.Context, report *models.Report, moderatorID uuid.UUID, action string, authorID *uuid.UUID, note *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyAction(ctx, tx, report.TargetType, report.TargetID, action, authorID); err != nil {
+		return err
+	}
+
+	status := models.ReportStatusActioned
+	if action == models.ModerationDismiss {
+		status = models.ReportStatusDismissed
+	}
+
+	resolveQuery := `
+		UPDATE reports SET status = $1, reviewed_by = $2, reviewed_at = NOW()
+		WHERE target_type = $3 AND target_id = $4 AND status = 'pending'
+	`
+	if _, err := tx.Exec(ctx, resolveQuery, status, moderatorID, report.TargetType, report.TargetID); err != nil {
+		return fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	auditQuery := `
+		INSERT INTO moderation_actions (moderator_id, report_id, target_type, target_id, action, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(ctx, auditQuery, moderatorID, report.ID, report.TargetType, report.TargetID, action, note); err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit moderation action: %w", err)
+	}
+
+	return nil
+}
+
+// applyAction hides, deletes or suspends the author of a target inside tx
+func applyAction(ctx context.Context, tx pgx.Tx, targetType string, targetID uuid.UUID, action string, authorID *uuid.UUID) error {
+	var queries []string
+	args := []interface{}{targetID}
+
+	switch action {
+	case models.ModerationDismiss:
+		return nil
+	case models.ModerationHide:
+		switch targetType {
+		case models.ReportTargetPost:
+			queries = []string{`UPDATE posts SET is_hidden = true WHERE id = $1`}
+		case models.ReportTargetComment:
+			queries = []string{`UPDATE comments SET is_hidden = true WHERE id = $1`}
+		case models.ReportTargetEvent:
+			queries = []string{`UPDATE events SET is_hidden = true WHERE id = $1`}
+		}
+	case models.ModerationDelete:
+		switch targetType {
+		case models.ReportTargetPost:
+			queries = []string{
+				`UPDATE posts SET is_deleted = true WHERE id = $1 AND is_deleted = false`,
+				`UPDATE posts SET is_deleted = true WHERE repost_of_id = $1 AND content = '' AND is_deleted = false`,
+			}
+		case models.ReportTargetComment:
+			queries = []string{`UPDATE comments SET is_deleted = true WHERE id = $1 AND is_deleted = false`}
+		case models.ReportTargetEvent:
+			queries = []string{`UPDATE events SET is_deleted = true, updated_at = NOW() WHERE id = $1`}
+		}
+	case models.ModerationSuspend:
+		queries = []string{`UPDATE users SET is_active = false WHERE id = $1`}
+		args = []interface{}{authorID}
+	}
+
+	if len(queries) == 0 {
+		return fmt.Errorf("action not supported for this target")
+	}
+
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to apply moderation action: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetActions retrieves the moderation audit trail, newest first
+func (r *Repository) GetActions(ctx context.Context, limit, offset int) ([]models.ModerationAction, error) {
+	query := `
+		SELECT ma.id, ma.moderator_id, ma.report_id, ma.target_type, ma.target_id,
+		       ma.action, ma.note, ma.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM moderation_actions ma
+		LEFT JOIN users u ON ma.moderator_id = u.id
+		ORDER BY ma.created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []models.ModerationAction
+	for rows.Next() {
+		var action models.ModerationAction
+		var moderatorID *uuid.UUID
+		var username, firstName, lastName, avatarURL *string
+
+		err := rows.Scan(
+			&action.ID,
+			&action.ModeratorID,
+			&action.ReportID,
+			&action.TargetType,
+			&action.TargetID,
+			&action.Action,
+			&action.Note,
+			&action.CreatedAt,
+			&moderatorID,
+			&username,
+			&firstName,
+			&lastName,
+			&avatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan moderation action: %w", err)
+		}
+
+		if moderatorID != nil {
+			action.Moderator = &models.UserResponse{
+				ID:        *moderatorID,
+				Username:  *username,
+				FirstName: *firstName,
+				LastName:  *lastName,
+				AvatarURL: avatarURL,
+			}
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// heldQueries list the held items of each type, oldest first
+var heldQueries = map[string]string{
+	models.ReportTargetPost: `
+		SELECT t.id, t.user_id, t.content, t.held_reasons, t.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM posts t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.is_held = true AND t.is_deleted = false
+		ORDER BY t.created_at ASC
+		LIMIT $1 OFFSET $2
+	`,
+	models.ReportTargetComment: `
+		SELECT t.id, t.user_id, t.content, t.held_reasons, t.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM comments t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.is_held = true AND t.is_deleted = false
+		ORDER BY t.created_at ASC
+		LIMIT $1 OFFSET $2
+	`,
+	models.ReportTargetEvent: `
+		SELECT t.id, t.created_by, t.title || E'\n' || t.description, t.held_reasons, t.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM events t
+		JOIN users u ON t.created_by = u.id
+		WHERE t.is_held = true AND t.is_deleted = false
+		ORDER BY t.created_at ASC
+		LIMIT $1 OFFSET $2
+	`,
+}
+
+// GetHeldItems retrieves content of the given type held for review by the content filters
+func (r *Repository) GetHeldItems(ctx context.Context, itemType string, limit, offset int) ([]models.HeldItem, error) {
+	query, ok := heldQueries[itemType]
+	if !ok {
+		return nil, fmt.Errorf("invalid type")
+	}
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.HeldItem{}
+	for rows.Next() {
+		item := models.HeldItem{Type: itemType}
+		var author models.UserResponse
+
+		err := rows.Scan(
+			&item.ID,
+			&item.AuthorID,
+			&item.Content,
+			&item.Reasons,
+			&item.CreatedAt,
+			&author.ID,
+			&author.Username,
+			&author.FirstName,
+			&author.LastName,
+			&author.AvatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan held item: %w", err)
+		}
+
+		item.Author = &author
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// heldTables maps held item types to their tables
+var heldTables = map[string]string{
+	models.ReportTargetPost:    "posts",
+	models.ReportTargetComment: "comments",
+	models.ReportTargetEvent:   "events",
+}
+
+// ReviewHeldItem publishes (approve) or deletes (reject) a held item and records
+// the decision in the audit trail
+func (r *Repository) ReviewHeldItem(ctx context.Context, moderatorID uuid.UUID, itemType string, itemID uuid.UUID, action string, note *string) error {
+	table, ok := heldTables[itemType]
+	if !ok {
+		return fmt.Errorf("invalid type")
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`UPDATE %s SET is_held = false, held_reasons = NULL WHERE id = $1 AND is_held = true AND is_deleted = false`, table)
+	if action == models.ModerationReject {
+		query = fmt.Sprintf(`UPDATE %s SET is_deleted = true WHERE id = $1 AND is_held = true AND is_deleted = false`, table)
+	}
+
+	result, err := tx.Exec(ctx, query, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to review held item: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("held item not found")
+	}
+
+	auditQuery := `
+		INSERT INTO moderation_actions (moderator_id, target_type, target_id, action, note)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(ctx, auditQuery, moderatorID, itemType, itemID, action, note); err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit review: %w", err)
+	}
+
+	return nil
+}
+
+// IsModerator reports whether the user can moderate content
+func (r *Repository) IsModerator(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var isModerator bool
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND role IN ('moderator', 'admin'))`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&isModerator)
+	return isModerator, err
+}
@@ -0,0 +1,205 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/auth"
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/post"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo        *Repository
+	posts       *post.Service
+	hub         *realtime.Hub
+	suspensions *auth.Suspensions
+}
+
+func NewService(repo *Repository, posts *post.Service, hub *realtime.Hub, suspensions *auth.Suspensions) *Service {
+	return &Service{repo: repo, posts: posts, hub: hub, suspensions: suspensions}
+}
+
+// CreateReport files a report against a post, comment, event or user
+func (s *Service) CreateReport(ctx context.Context, reporterID uuid.UUID, req *models.CreateReportRequest) (*models.Report, error) {
+	if req.TargetType == models.ReportTargetUser && req.TargetID == reporterID {
+		return nil, fmt.Errorf("you cannot report yourself")
+	}
+
+	if _, err := s.repo.GetTargetAuthor(ctx, req.TargetType, req.TargetID); err != nil {
+		return nil, err
+	}
+
+	report := &models.Report{
+		ReporterID: reporterID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	}
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetReports returns the moderation queue for the given status
+func (s *Service) GetReports(ctx context.Context, moderatorID uuid.UUID, status string, page, limit int) ([]models.Report, error) {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case models.ReportStatusPending, models.ReportStatusDismissed, models.ReportStatusActioned:
+	default:
+		return nil, fmt.Errorf("invalid status")
+	}
+
+	offset := (page - 1) * limit
+	return s.repo.GetReports(ctx, status, limit, offset)
+}
+
+// DismissReport closes a report, and the other pending reports on the same
+// target, without touching the reported content
+func (s *Service) DismissReport(ctx context.Context, moderatorID, reportID uuid.UUID, note *string) error {
+	report, err := s.getPendingReport(ctx, moderatorID, reportID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.ResolveReport(ctx, report, moderatorID, models.ModerationDismiss, nil, note)
+}
+
+// ActOnReport hides or deletes the reported content, or suspends its author
+func (s *Service) ActOnReport(ctx context.Context, moderatorID, reportID uuid.UUID, req *models.ModerationActionRequest) error {
+	report, err := s.getPendingReport(ctx, moderatorID, reportID)
+	if err != nil {
+		return err
+	}
+
+	if report.TargetType == models.ReportTargetUser && req.Action != models.ModerationSuspend {
+		return fmt.Errorf("action not supported for this target")
+	}
+
+	authorID, err := s.repo.GetTargetAuthor(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if req.Action == models.ModerationSuspend {
+		if authorID == nil {
+			return fmt.Errorf("target has no author")
+		}
+
+		isModerator, err := s.repo.IsModerator(ctx, *authorID)
+		if err != nil {
+			return fmt.Errorf("failed to check author role: %w", err)
+		}
+
+		if isModerator {
+			return fmt.Errorf("moderators cannot be suspended")
+		}
+
+		// Revoked before the report is resolved so a failure can be retried
+		if err := s.suspensions.Suspend(ctx, *authorID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.ResolveReport(ctx, report, moderatorID, req.Action, authorID, req.Note); err != nil {
+		return err
+	}
+
+	// Open streams and chat connections of a suspended user are closed
+	if req.Action == models.ModerationSuspend {
+		s.hub.Publish(ctx, realtime.UserTopic(*authorID), realtime.EventSuspended, nil)
+	}
+
+	return nil
+}
+
+// GetActions returns the moderation audit trail
+func (s *Service) GetActions(ctx context.Context, moderatorID uuid.UUID, page, limit int) ([]models.ModerationAction, error) {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+	return s.repo.GetActions(ctx, limit, offset)
+}
+
+// GetHeldItems returns content of the given type held for review by the content filters
+func (s *Service) GetHeldItems(ctx context.Context, moderatorID uuid.UUID, itemType string, page, limit int) ([]models.HeldItem, error) {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+	return s.repo.GetHeldItems(ctx, itemType, limit, offset)
+}
+
+// ApproveHeldItem publishes held content
+func (s *Service) ApproveHeldItem(ctx context.Context, moderatorID uuid.UUID, itemType string, itemID uuid.UUID, note *string) error {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReviewHeldItem(ctx, moderatorID, itemType, itemID, models.ModerationApprove, note); err != nil {
+		return err
+	}
+
+	// Notifications and live updates were held back along with the content
+	switch itemType {
+	case models.ReportTargetPost:
+		s.posts.PublishApprovedPost(ctx, itemID)
+	case models.ReportTargetComment:
+		s.posts.PublishApprovedComment(ctx, itemID)
+	}
+
+	return nil
+}
+
+// RejectHeldItem deletes held content without publishing it
+func (s *Service) RejectHeldItem(ctx context.Context, moderatorID uuid.UUID, itemType string, itemID uuid.UUID, note *string) error {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return err
+	}
+
+	return s.repo.ReviewHeldItem(ctx, moderatorID, itemType, itemID, models.ModerationReject, note)
+}
+
+// getPendingReport loads a report that is still waiting for a decision
+func (s *Service) getPendingReport(ctx context.Context, moderatorID, reportID uuid.UUID) (*models.Report, error) {
+	if err := s.checkModerator(ctx, moderatorID); err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.GetReportByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Status != models.ReportStatusPending {
+		return nil, fmt.Errorf("report already resolved")
+	}
+
+	return report, nil
+}
+
+// checkModerator returns an error unless the user is a moderator or admin
+func (s *Service) checkModerator(ctx context.Context, userID uuid.UUID) error {
+	isModerator, err := s.repo.IsModerator(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check role: %w", err)
+	}
+
+	if !isModerator {
+		return fmt.Errorf("unauthorized: moderator role required")
+	}
+
+	return nil
+}
@@ -27,9 +27,9 @@ func NewRepository(db *pgxpool.Pool) Repository {
 
 func (r *repository) SaveArticle(ctx context.Context, article *models.SavedArticle) error {
 	query := `
-        INSERT INTO saved_articles (id, user_id, article_url, article_title, article_image, article_source, saved_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
-        ON CONFLICT (user_id, article_url) DO NOTHING
+        INSERT INTO bookmarks (id, user_id, item_type, article_url, article_title, article_image, article_source, saved_at)
+        VALUES ($1, $2, 'article', $3, $4, $5, $6, $7)
+        ON CONFLICT (user_id, article_url) WHERE article_url IS NOT NULL DO NOTHING
         RETURNING id
     `
 
@@ -59,8 +59,8 @@ func (r *repository) SaveArticle(ctx context.Context, article *models.SavedArtic
 func (r *repository) GetSavedArticles(ctx context.Context, userID uuid.UUID) ([]models.SavedArticle, error) {
 	query := `
         SELECT id, user_id, article_url, article_title, article_image, article_source, saved_at
-        FROM saved_articles
-        WHERE user_id = $1
+        FROM bookmarks
+        WHERE user_id = $1 AND item_type = 'article'
         ORDER BY saved_at DESC
     `
 
@@ -97,8 +97,8 @@ func (r *repository) GetSavedArticles(ctx context.Context, userID uuid.UUID) ([]
 
 func (r *repository) DeleteSavedArticle(ctx context.Context, userID uuid.UUID, articleURL string) error {
 	query := `
-        DELETE FROM saved_articles
-        WHERE user_id = $1 AND article_url = $2
+        DELETE FROM bookmarks
+        WHERE user_id = $1 AND item_type = 'article' AND article_url = $2
     `
 
 	result, err := r.db.Exec(ctx, query, userID, articleURL)
@@ -117,8 +117,8 @@ func (r *repository) DeleteSavedArticle(ctx context.Context, userID uuid.UUID, a
 func (r *repository) IsArticleSaved(ctx context.Context, userID uuid.UUID, articleURL string) (bool, error) {
 	query := `
         SELECT EXISTS(
-            SELECT 1 FROM saved_articles
-            WHERE user_id = $1 AND article_url = $2
+            SELECT 1 FROM bookmarks
+            WHERE user_id = $1 AND item_type = 'article' AND article_url = $2
         )
     `
 
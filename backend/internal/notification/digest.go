@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+)
+
+// digestItems is how many notifications and upcoming events a digest lists at most
+const digestItems = 10
+
+// Digester emails the daily and weekly digests of the users who chose one. A
+// digest summarizes the notifications since the previous one and the events
+// the user RSVP'd to that are coming up before the next one.
+type Digester struct {
+	repo      *Repository
+	emailer   *Emailer
+	interval  time.Duration
+	hour      int
+	batchSize int
+}
+
+func NewDigester(repo *Repository, emailer *Emailer, cfg *config.Config) *Digester {
+	return &Digester{
+		repo:      repo,
+		emailer:   emailer,
+		interval:  cfg.Digest.Interval,
+		hour:      cfg.Digest.Hour,
+		batchSize: cfg.Digest.BatchSize,
+	}
+}
+
+// Run sends the due digests right away and then on every interval until ctx is cancelled
+func (d *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.SendDue(ctx); err != nil {
+			log.Printf("Email digests error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends every due digest, one batch at a time. Digests are marked as sent
+// before being sent, so a failure skips a digest rather than sending it twice.
+func (d *Digester) SendDue(ctx context.Context) error {
+	sent := 0
+	for {
+		claims, err := d.repo.ClaimDueDigests(ctx, d.hour, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, claim := range claims {
+			ok, err := d.send(ctx, claim)
+			if err != nil {
+				log.Printf("Email digest to %s error: %v", claim.UserID, err)
+			} else if ok {
+				sent++
+			}
+		}
+
+		if len(claims) < d.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("Sent %d email digests", sent)
+	}
+
+	return nil
+}
+
+// send emails a digest, unless there is nothing to tell, and reports whether it was sent
+func (d *Digester) send(ctx context.Context, claim digestClaim) (bool, error) {
+	to, err := d.repo.GetRecipient(ctx, claim.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	notifications, total, err := d.repo.GetActivitySince(ctx, claim.UserID, claim.Since, digestItems)
+	if err != nil {
+		return false, err
+	}
+
+	if len(notifications) > 0 {
+		actors, err := d.repo.GetActors(ctx, notificationIDs(notifications), actorsShown)
+		if err != nil {
+			return false, err
+		}
+		for i := range notifications {
+			notifications[i].Actors = actors[notifications[i].ID]
+		}
+	}
+
+	within := 24 * time.Hour
+	if claim.Frequency == models.DigestWeekly {
+		within = 7 * 24 * time.Hour
+	}
+	events, err := d.repo.GetUpcomingEvents(ctx, claim.UserID, within, digestItems)
+	if err != nil {
+		return false, err
+	}
+
+	if len(notifications) == 0 && len(events) == 0 {
+		return false, nil
+	}
+
+	return true, d.emailer.SendDigest(ctx, to, &digestEmail{
+		Recipient:     to,
+		Frequency:     claim.Frequency,
+		Notifications: notifications,
+		More:          total - len(notifications),
+		Events:        events,
+	})
+}
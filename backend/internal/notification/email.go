@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/Aolakije/City-Buzz/pkg/mailer"
+)
+
+// Emailer renders notification emails in the recipient's language and sends them
+type Emailer struct {
+	mailer    mailer.Mailer
+	templates *templates
+}
+
+// notificationEmail is the data of the email sent for a single notification
+type notificationEmail struct {
+	Recipient    *recipient
+	Notification models.Notification
+}
+
+// digestEmail is the data of a daily or weekly digest
+type digestEmail struct {
+	Recipient     *recipient
+	Frequency     string
+	Notifications []models.Notification
+	More          int // Notifications left out of the digest
+	Events        []models.Event
+}
+
+func NewEmailer(m mailer.Mailer, cfg *config.Config) (*Emailer, error) {
+	templates, err := loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Emailer{mailer: m, templates: templates}, nil
+}
+
+// SendNotification emails a single notification to its recipient
+func (e *Emailer) SendNotification(ctx context.Context, to *recipient, n *models.Notification) error {
+	return e.send(ctx, to, "notification", &notificationEmail{Recipient: to, Notification: *n})
+}
+
+// SendDigest emails a digest to its recipient
+func (e *Emailer) SendDigest(ctx context.Context, to *recipient, digest *digestEmail) error {
+	return e.send(ctx, to, "digest", digest)
+}
+
+// send renders the subject, text and HTML templates of the given email in the
+// recipient's language and sends the result
+func (e *Emailer) send(ctx context.Context, to *recipient, name string, data interface{}) error {
+	if to.Email == nil || *to.Email == "" {
+		return fmt.Errorf("user has no email address")
+	}
+
+	subject, err := e.templates.renderText(to.Language, name+"_subject", data)
+	if err != nil {
+		return err
+	}
+	text, err := e.templates.renderText(to.Language, name+"_text", data)
+	if err != nil {
+		return err
+	}
+	html, err := e.templates.renderHTML(to.Language, name+"_html", data)
+	if err != nil {
+		return err
+	}
+
+	return e.mailer.Send(ctx, &mailer.Message{
+		To:      *to.Email,
+		Subject: strings.TrimSpace(subject),
+		Text:    text,
+		HTML:    html,
+	})
+}
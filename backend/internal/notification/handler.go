@@ -0,0 +1,236 @@
+package notification
+
+import (
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetNotifications handles retrieval of the user's notifications, most recent activity first
+// GET /api/v1/notifications?cursor=...&limit=20&unread=true
+func (h *Handler) GetNotifications(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	notifications, nextCursor, err := h.service.GetNotifications(c.Context(), userID, c.Query("cursor"), c.QueryBool("unread"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid cursor")
+		}
+		log.Printf("Get notifications error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get notifications")
+	}
+
+	unreadCount, err := h.service.GetUnreadCount(c.Context(), userID)
+	if err != nil {
+		log.Printf("Count unread notifications error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get notifications")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"notifications": notifications,
+		"next_cursor":   nextCursor,
+		"unread_count":  unreadCount,
+		"limit":         limit,
+	})
+}
+
+// GetUnreadCount handles retrieval of the number of unread notifications
+// GET /api/v1/notifications/unread-count
+func (h *Handler) GetUnreadCount(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	count, err := h.service.GetUnreadCount(c.Context(), userID)
+	if err != nil {
+		log.Printf("Count unread notifications error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to count notifications")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"unread_count": count,
+	})
+}
+
+// MarkRead handles marking a notification as read
+// PUT /api/v1/notifications/:id/read
+func (h *Handler) MarkRead(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	notificationID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid notification ID")
+	}
+
+	notification, err := h.service.MarkRead(c.Context(), notificationID, userID)
+	if err != nil {
+		if err.Error() == "notification not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Notification not found")
+		}
+		log.Printf("Mark notification read error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to mark notification as read")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Notification marked as read", fiber.Map{
+		"notification": notification,
+	})
+}
+
+// MarkAllRead handles marking all of the user's notifications as read
+// POST /api/v1/notifications/read-all
+func (h *Handler) MarkAllRead(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	count, err := h.service.MarkAllRead(c.Context(), userID)
+	if err != nil {
+		log.Printf("Mark all notifications read error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to mark notifications as read")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Notifications marked as read", fiber.Map{
+		"marked": count,
+	})
+}
+
+// GetSettings handles retrieval of the user's notification preferences and digest frequency
+// GET /api/v1/notifications/preferences
+func (h *Handler) GetSettings(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	settings, err := h.service.GetSettings(c.Context(), userID)
+	if err != nil {
+		log.Printf("Get notification settings error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get notification preferences")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", settings)
+}
+
+// UpdateSettings handles changes to the user's notification preferences and digest frequency
+// PUT /api/v1/notifications/preferences
+func (h *Handler) UpdateSettings(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.UpdateNotificationSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	settings, err := h.service.UpdateSettings(c.Context(), userID, &req)
+	if err != nil {
+		log.Printf("Update notification settings error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update notification preferences")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Notification preferences updated", settings)
+}
+
+// GetPushPublicKey handles retrieval of the VAPID public key browsers subscribe with
+// GET /api/v1/push/vapid-public-key
+func (h *Handler) GetPushPublicKey(c *fiber.Ctx) error {
+	publicKey, err := h.service.PushPublicKey()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Push notifications are disabled")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"public_key": publicKey,
+	})
+}
+
+// SubscribePush handles registration of a browser's push subscription
+// POST /api/v1/push/subscriptions
+func (h *Handler) SubscribePush(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.SubscribePushRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	subscription, err := h.service.SubscribePush(c.Context(), userID, &req, c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		switch err.Error() {
+		case "push notifications are disabled":
+			return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Push notifications are disabled")
+		case "invalid push subscription":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid push subscription")
+		}
+		log.Printf("Subscribe push error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to subscribe to push notifications")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Subscribed to push notifications", fiber.Map{
+		"subscription": subscription,
+	})
+}
+
+// UnsubscribePush handles removal of a browser's push subscription
+// DELETE /api/v1/push/subscriptions
+func (h *Handler) UnsubscribePush(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.UnsubscribePushRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.UnsubscribePush(c.Context(), userID, req.Endpoint); err != nil {
+		if err.Error() == "push subscription not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Push subscription not found")
+		}
+		log.Printf("Unsubscribe push error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unsubscribe from push notifications")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Unsubscribed from push notifications", nil)
+}
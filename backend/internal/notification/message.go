@@ -0,0 +1,72 @@
+package notification
+
+import (
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+)
+
+// message writes the text shown for a notification, such as
+// "Marie and 3 others liked your post"
+func message(n *models.Notification) string {
+	if n.Type == models.NotificationEventReminder {
+		return "An event you're going to starts soon"
+	}
+	return actorsText(n) + " " + actionText(n)
+}
+
+// actorsText names the latest actor and counts the others
+func actorsText(n *models.Notification) string {
+	if len(n.Actors) == 0 {
+		return "Someone"
+	}
+
+	first := displayName(&n.Actors[0])
+	switch {
+	case n.ActorsCount <= 1:
+		return first
+	case n.ActorsCount == 2 && len(n.Actors) > 1:
+		return first + " and " + displayName(&n.Actors[1])
+	case n.ActorsCount == 2:
+		return first + " and 1 other"
+	default:
+		return fmt.Sprintf("%s and %d others", first, n.ActorsCount-1)
+	}
+}
+
+// actionText describes what the actors did
+func actionText(n *models.Notification) string {
+	switch n.Type {
+	case models.NotificationLike:
+		if n.TargetType == models.NotificationTargetComment {
+			return "liked your comment"
+		}
+		return "liked your post"
+	case models.NotificationComment:
+		return "commented on your post"
+	case models.NotificationReply:
+		return "replied to a post you commented on"
+	case models.NotificationMention:
+		if n.TargetType == models.NotificationTargetComment {
+			return "mentioned you in a comment"
+		}
+		return "mentioned you in a post"
+	case models.NotificationFollow:
+		return "started following you"
+	case models.NotificationRSVP:
+		return "responded to your event"
+	case models.NotificationEventUpdated:
+		return "updated an event you're attending"
+	case models.NotificationEventCancelled:
+		return "cancelled an event you're attending"
+	default:
+		return "interacted with you"
+	}
+}
+
+func displayName(user *models.UserResponse) string {
+	if user.FirstName != "" {
+		return user.FirstName
+	}
+	return user.Username
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+)
+
+// defaultPreference returns the channels of a type the user never configured:
+// everything shows in the app, cancellations are also emailed since they
+// matter even to users who rarely open it, and urgent types are pushed to the
+// browsers the user subscribed
+func defaultPreference(kind string) models.NotificationPreference {
+	return models.NotificationPreference{
+		Type:  kind,
+		InApp: true,
+		Email: kind == models.NotificationEventCancelled,
+		Push:  isUrgent(kind),
+	}
+}
+
+// preference returns the channels a user chose for a type of notification
+func (s *Service) preference(ctx context.Context, userID uuid.UUID, kind string) (models.NotificationPreference, error) {
+	preferences, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return models.NotificationPreference{}, err
+	}
+
+	if preference, ok := preferences[kind]; ok {
+		return preference, nil
+	}
+	return defaultPreference(kind), nil
+}
+
+// GetSettings retrieves the user's preferences for every type of notification
+// and their digest frequency
+func (s *Service) GetSettings(ctx context.Context, userID uuid.UUID) (*models.NotificationSettings, error) {
+	saved, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := s.repo.GetDigest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.NotificationSettings{
+		Preferences: make([]models.NotificationPreference, 0, len(models.NotificationTypes)),
+		Digest:      digest,
+	}
+	for _, kind := range models.NotificationTypes {
+		preference, ok := saved[kind]
+		if !ok {
+			preference = defaultPreference(kind)
+		}
+		settings.Preferences = append(settings.Preferences, preference)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings changes the preferences of the types given in the request and,
+// when set, the digest frequency. Types left out keep their current preferences.
+func (s *Service) UpdateSettings(ctx context.Context, userID uuid.UUID, req *models.UpdateNotificationSettingsRequest) (*models.NotificationSettings, error) {
+	// The last preference given for a type wins
+	byType := make(map[string]int, len(req.Preferences))
+	preferences := make([]models.NotificationPreference, 0, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		if i, ok := byType[preference.Type]; ok {
+			preferences[i] = preference
+			continue
+		}
+		byType[preference.Type] = len(preferences)
+		preferences = append(preferences, preference)
+	}
+
+	if err := s.repo.SaveSettings(ctx, userID, preferences, req.Digest); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(ctx, userID)
+}
@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/Aolakije/City-Buzz/pkg/webpush"
+	"github.com/google/uuid"
+)
+
+// pushTitle is the title of every push notification
+const pushTitle = "City-Buzz"
+
+// Pusher delivers notifications with Web Push to the browsers their recipient subscribed
+type Pusher struct {
+	sender    *webpush.Sender
+	repo      *Repository
+	templates *templates
+}
+
+// pushMessage is the payload handed to the service worker
+type pushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	Tag   string `json:"tag"` // Lets the browser replace an older notification about the same thing
+}
+
+// NewPusher returns nil when Web Push is disabled, that is without a sender
+func NewPusher(sender *webpush.Sender, repo *Repository, cfg *config.Config) (*Pusher, error) {
+	if sender == nil {
+		return nil, nil
+	}
+
+	templates, err := loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Pusher{sender: sender, repo: repo, templates: templates}, nil
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with
+func (p *Pusher) PublicKey() string {
+	return p.sender.PublicKey()
+}
+
+// Validate checks that a subscription can be delivered to
+func (p *Pusher) Validate(sub *webpush.Subscription) error {
+	if err := p.sender.ValidateEndpoint(sub.Endpoint); err != nil {
+		return err
+	}
+	if _, _, err := sub.Keys(); err != nil {
+		return fmt.Errorf("invalid subscription keys")
+	}
+	return nil
+}
+
+// Send pushes a notification to every browser of its recipient. Subscriptions
+// the push service reports as gone are deleted.
+func (p *Pusher) Send(ctx context.Context, to *recipient, n *models.Notification) error {
+	subscriptions, err := p.repo.GetPushSubscriptions(ctx, to.ID)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	body, err := p.templates.renderText(to.Language, "message", n)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(&pushMessage{
+		Title: pushTitle,
+		Body:  body,
+		URL:   p.templates.link(n),
+		Type:  n.Type,
+		Tag:   n.Type + ":" + n.TargetID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	urgency := webpush.UrgencyNormal
+	if isUrgent(n.Type) {
+		urgency = webpush.UrgencyHigh
+	}
+
+	for _, sub := range subscriptions {
+		err := p.sender.Send(ctx, &webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload, urgency)
+		switch {
+		case errors.Is(err, webpush.ErrGone):
+			if err := p.repo.PrunePushSubscription(ctx, sub.ID); err != nil {
+				log.Printf("Prune push subscription %s error: %v", sub.ID, err)
+			}
+		case err != nil:
+			log.Printf("Push to subscription %s error: %v", sub.ID, err)
+		default:
+			if err := p.repo.TouchPushSubscription(ctx, sub.ID); err != nil {
+				log.Printf("Push subscription %s update error: %v", sub.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isUrgent reports whether a type of notification should wake the device right away
+func isUrgent(kind string) bool {
+	return kind == models.NotificationMention || kind == models.NotificationEventCancelled ||
+		kind == models.NotificationEventReminder
+}
+
+// PushPublicKey returns the VAPID public key browsers subscribe with
+func (s *Service) PushPublicKey() (string, error) {
+	if s.pusher == nil {
+		return "", fmt.Errorf("push notifications are disabled")
+	}
+	return s.pusher.PublicKey(), nil
+}
+
+// SubscribePush stores a browser's push subscription for the user
+func (s *Service) SubscribePush(ctx context.Context, userID uuid.UUID, req *models.SubscribePushRequest, userAgent string) (*models.PushSubscription, error) {
+	if s.pusher == nil {
+		return nil, fmt.Errorf("push notifications are disabled")
+	}
+
+	if err := s.pusher.Validate(&webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}); err != nil {
+		return nil, fmt.Errorf("invalid push subscription")
+	}
+
+	sub := &models.PushSubscription{
+		UserID:   userID,
+		Endpoint: req.Endpoint,
+		P256dh:   req.Keys.P256dh,
+		Auth:     req.Keys.Auth,
+	}
+	if userAgent != "" {
+		if len(userAgent) > 500 {
+			userAgent = userAgent[:500]
+		}
+		sub.UserAgent = &userAgent
+	}
+
+	if err := s.repo.SavePushSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// UnsubscribePush removes one of the user's push subscriptions
+func (s *Service) UnsubscribePush(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	return s.repo.DeletePushSubscription(ctx, userID, endpoint)
+}
@@ -0,0 +1,605 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// recipient is the account details needed to deliver a notification
+type recipient struct {
+	ID        uuid.UUID
+	Email     *string
+	Username  string
+	FirstName string
+	Language  string
+	IsActive  bool
+}
+
+// digestClaim is a digest reserved for sending, covering the activity since Since
+type digestClaim struct {
+	UserID    uuid.UUID
+	Frequency string
+	Since     time.Time
+}
+
+// AddActor records that actor acted on the target, grouping the action into the
+// recipient's unread notification of the same type and target when there is one.
+// A nil actor records a notification no user caused. It returns the ID of the
+// notification.
+func (r *Repository) AddActor(ctx context.Context, recipientID, actorID uuid.UUID, kind string, target Target) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var notificationID uuid.UUID
+	query := `
+		INSERT INTO notifications (user_id, type, target_type, target_id, post_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, type, target_type, target_id) WHERE read_at IS NULL
+		DO UPDATE SET updated_at = NOW()
+		RETURNING id
+	`
+	err = tx.QueryRow(ctx, query, recipientID, kind, target.Type, target.ID, target.PostID).Scan(&notificationID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	if actorID != uuid.Nil {
+		actorQuery := `
+			INSERT INTO notification_actors (notification_id, actor_id)
+			VALUES ($1, $2)
+			ON CONFLICT (notification_id, actor_id) DO UPDATE SET created_at = NOW()
+		`
+		if _, err := tx.Exec(ctx, actorQuery, notificationID, actorID); err != nil {
+			return uuid.Nil, fmt.Errorf("failed to save notification actor: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit notification: %w", err)
+	}
+
+	return notificationID, nil
+}
+
+// GetNotifications retrieves a user's notifications by latest activity, starting
+// after the (updatedAt, id) position of before when it is set
+func (r *Repository) GetNotifications(ctx context.Context, userID uuid.UUID, before *utils.Cursor, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := `
+		SELECT n.id, n.user_id, n.type, n.target_type, n.target_id, n.post_id, n.read_at,
+		       n.created_at, n.updated_at,
+		       (SELECT COUNT(*) FROM notification_actors na WHERE na.notification_id = n.id)
+		FROM notifications n
+		WHERE n.user_id = $1
+		  AND ($2 = false OR n.read_at IS NULL)
+		  AND ($3::timestamp IS NULL OR (n.updated_at, n.id) < ($3::timestamp, $4::uuid))
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $5
+	`
+
+	var beforeTime *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeTime = &before.At
+		beforeID = &before.ID
+	}
+
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly, beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.TargetType,
+			&n.TargetID,
+			&n.PostID,
+			&n.ReadAt,
+			&n.CreatedAt,
+			&n.UpdatedAt,
+			&n.ActorsCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		n.IsRead = n.ReadAt != nil
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// GetNotification retrieves one of a user's notifications
+func (r *Repository) GetNotification(ctx context.Context, notificationID, userID uuid.UUID) (*models.Notification, error) {
+	query := `
+		SELECT n.id, n.user_id, n.type, n.target_type, n.target_id, n.post_id, n.read_at,
+		       n.created_at, n.updated_at,
+		       (SELECT COUNT(*) FROM notification_actors na WHERE na.notification_id = n.id)
+		FROM notifications n
+		WHERE n.id = $1 AND n.user_id = $2
+	`
+
+	var n models.Notification
+	err := r.db.QueryRow(ctx, query, notificationID, userID).Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Type,
+		&n.TargetType,
+		&n.TargetID,
+		&n.PostID,
+		&n.ReadAt,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+		&n.ActorsCount,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("notification not found")
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	n.IsRead = n.ReadAt != nil
+	return &n, nil
+}
+
+// GetActors retrieves the most recent actors of each notification, up to perNotification each
+func (r *Repository) GetActors(ctx context.Context, notificationIDs []uuid.UUID, perNotification int) (map[uuid.UUID][]models.UserResponse, error) {
+	query := `
+		SELECT notification_id, id, username, first_name, last_name, avatar_url
+		FROM (
+			SELECT na.notification_id, u.id, u.username, u.first_name, u.last_name, u.avatar_url,
+			       ROW_NUMBER() OVER (PARTITION BY na.notification_id ORDER BY na.created_at DESC) AS rank
+			FROM notification_actors na
+			JOIN users u ON u.id = na.actor_id
+			WHERE na.notification_id = ANY($1)
+		) actors
+		WHERE rank <= $2
+		ORDER BY notification_id, rank
+	`
+
+	rows, err := r.db.Query(ctx, query, notificationIDs, perNotification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification actors: %w", err)
+	}
+	defer rows.Close()
+
+	actors := make(map[uuid.UUID][]models.UserResponse)
+	for rows.Next() {
+		var notificationID uuid.UUID
+		var actor models.UserResponse
+		err := rows.Scan(
+			&notificationID,
+			&actor.ID,
+			&actor.Username,
+			&actor.FirstName,
+			&actor.LastName,
+			&actor.AvatarURL,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification actor: %w", err)
+		}
+		actors[notificationID] = append(actors[notificationID], actor)
+	}
+
+	return actors, rows.Err()
+}
+
+// CountUnread counts a user's unread notifications
+func (r *Repository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications as read. Marking a read notification is a no-op.
+func (r *Repository) MarkRead(ctx context.Context, notificationID, userID uuid.UUID) error {
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("notification not found")
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns how many there were
+func (r *Repository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+
+	result, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetActiveUserIDs looks up the IDs of the active users among the given usernames
+func (r *Repository) GetActiveUserIDs(ctx context.Context, usernames []string) ([]uuid.UUID, error) {
+	query := `SELECT id FROM users WHERE username = ANY($1) AND is_active = true`
+
+	rows, err := r.db.Query(ctx, query, usernames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetPreferences retrieves the preferences a user has saved, keyed by type
+func (r *Repository) GetPreferences(ctx context.Context, userID uuid.UUID) (map[string]models.NotificationPreference, error) {
+	query := `SELECT type, in_app, email, push FROM notification_preferences WHERE user_id = $1`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]models.NotificationPreference)
+	for rows.Next() {
+		var p models.NotificationPreference
+		if err := rows.Scan(&p.Type, &p.InApp, &p.Email, &p.Push); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences[p.Type] = p
+	}
+
+	return preferences, rows.Err()
+}
+
+// GetDigest retrieves a user's digest frequency
+func (r *Repository) GetDigest(ctx context.Context, userID uuid.UUID) (string, error) {
+	var digest string
+	query := `SELECT digest FROM notification_settings WHERE user_id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&digest)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.DigestNone, nil
+		}
+		return "", fmt.Errorf("failed to get digest frequency: %w", err)
+	}
+	return digest, nil
+}
+
+// SaveSettings stores the given preferences and, when set, the digest frequency.
+// A newly chosen frequency starts counting from now, so the first digest does
+// not go out right away.
+func (r *Repository) SaveSettings(ctx context.Context, userID uuid.UUID, preferences []models.NotificationPreference, digest *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	preferenceQuery := `
+		INSERT INTO notification_preferences (user_id, type, in_app, email, push)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, type) DO UPDATE
+		SET in_app = EXCLUDED.in_app, email = EXCLUDED.email, push = EXCLUDED.push
+	`
+	for _, p := range preferences {
+		if _, err := tx.Exec(ctx, preferenceQuery, userID, p.Type, p.InApp, p.Email, p.Push); err != nil {
+			return fmt.Errorf("failed to save notification preference: %w", err)
+		}
+	}
+
+	if digest != nil {
+		digestQuery := `
+			INSERT INTO notification_settings (user_id, digest, digest_sent_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (user_id) DO UPDATE
+			SET digest = EXCLUDED.digest,
+			    digest_sent_at = CASE
+			        WHEN notification_settings.digest = EXCLUDED.digest THEN notification_settings.digest_sent_at
+			        ELSE NOW()
+			    END
+		`
+		if _, err := tx.Exec(ctx, digestQuery, userID, *digest); err != nil {
+			return fmt.Errorf("failed to save digest frequency: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit notification settings: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecipient retrieves the account details of a user
+func (r *Repository) GetRecipient(ctx context.Context, userID uuid.UUID) (*recipient, error) {
+	query := `
+		SELECT id, email, username, first_name, COALESCE(language, ''), is_active
+		FROM users
+		WHERE id = $1
+	`
+
+	var u recipient
+	err := r.db.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Email, &u.Username, &u.FirstName, &u.Language, &u.IsActive)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &u, nil
+}
+
+// GetUser retrieves the public profile of a user
+func (r *Repository) GetUser(ctx context.Context, userID uuid.UUID) (*models.UserResponse, error) {
+	query := `SELECT id, username, first_name, last_name, avatar_url FROM users WHERE id = $1`
+
+	var u models.UserResponse
+	err := r.db.QueryRow(ctx, query, userID).Scan(&u.ID, &u.Username, &u.FirstName, &u.LastName, &u.AvatarURL)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &u, nil
+}
+
+// ClaimDueDigests reserves up to limit digests whose period has started since
+// they were last sent, marking them as sent so no other instance sends them too.
+// Periods start every day, or every Monday for weekly digests, at the given hour.
+func (r *Repository) ClaimDueDigests(ctx context.Context, hour, limit int) ([]digestClaim, error) {
+	query := `
+		WITH due AS (
+			SELECT s.user_id, s.digest, s.digest_sent_at
+			FROM notification_settings s
+			JOIN users u ON u.id = s.user_id
+			WHERE u.is_active = true AND u.email IS NOT NULL
+			  AND (
+			      (s.digest = 'daily' AND (s.digest_sent_at IS NULL OR s.digest_sent_at <
+			          date_trunc('day', NOW() - $1 * INTERVAL '1 hour') + $1 * INTERVAL '1 hour'))
+			      OR (s.digest = 'weekly' AND (s.digest_sent_at IS NULL OR s.digest_sent_at <
+			          date_trunc('week', NOW() - $1 * INTERVAL '1 hour') + $1 * INTERVAL '1 hour'))
+			  )
+			ORDER BY s.digest_sent_at NULLS FIRST
+			LIMIT $2
+			FOR UPDATE OF s SKIP LOCKED
+		)
+		UPDATE notification_settings s
+		SET digest_sent_at = NOW()
+		FROM due
+		WHERE s.user_id = due.user_id
+		RETURNING s.user_id, due.digest,
+		          COALESCE(due.digest_sent_at, NOW() - CASE WHEN due.digest = 'daily' THEN INTERVAL '1 day' ELSE INTERVAL '7 days' END)
+	`
+
+	rows, err := r.db.Query(ctx, query, hour, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim digests: %w", err)
+	}
+	defer rows.Close()
+
+	var claims []digestClaim
+	for rows.Next() {
+		var c digestClaim
+		if err := rows.Scan(&c.UserID, &c.Frequency, &c.Since); err != nil {
+			return nil, fmt.Errorf("failed to scan digest: %w", err)
+		}
+		claims = append(claims, c)
+	}
+
+	return claims, rows.Err()
+}
+
+// GetActivitySince retrieves a user's notifications with activity after since,
+// most recent first, up to limit, and how many there are in total
+func (r *Repository) GetActivitySince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]models.Notification, int, error) {
+	query := `
+		SELECT n.id, n.user_id, n.type, n.target_type, n.target_id, n.post_id, n.read_at,
+		       n.created_at, n.updated_at,
+		       (SELECT COUNT(*) FROM notification_actors na WHERE na.notification_id = n.id),
+		       COUNT(*) OVER ()
+		FROM notifications n
+		WHERE n.user_id = $1 AND n.updated_at > $2
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, since, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	total := 0
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.TargetType,
+			&n.TargetID,
+			&n.PostID,
+			&n.ReadAt,
+			&n.CreatedAt,
+			&n.UpdatedAt,
+			&n.ActorsCount,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		n.IsRead = n.ReadAt != nil
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, rows.Err()
+}
+
+// GetUpcomingEvents retrieves the events a user RSVP'd to that start within the
+// given time, soonest first
+func (r *Repository) GetUpcomingEvents(ctx context.Context, userID uuid.UUID, within time.Duration, limit int) ([]models.Event, error) {
+	query := `
+		SELECT e.id, e.title, e.start_date, e.location, e.city
+		FROM event_rsvps er
+		JOIN events e ON e.id = er.event_id
+		WHERE er.user_id = $1 AND e.is_deleted = false
+		  AND e.start_date >= NOW() AND e.start_date < NOW() + $2 * INTERVAL '1 second'
+		ORDER BY e.start_date ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, within.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		if err := rows.Scan(&e.ID, &e.Title, &e.StartDate, &e.Location, &e.City); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// SavePushSubscription stores a browser's push subscription for a user. A known
+// endpoint gets its keys refreshed and moves to the user.
+func (r *Repository) SavePushSubscription(ctx context.Context, sub *models.PushSubscription) error {
+	query := `
+		INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id, p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth,
+		    user_agent = EXCLUDED.user_agent
+		RETURNING id, created_at, last_used_at
+	`
+
+	err := r.db.QueryRow(ctx, query, sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent).
+		Scan(&sub.ID, &sub.CreatedAt, &sub.LastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePushSubscription removes one of a user's push subscriptions by endpoint
+func (r *Repository) DeletePushSubscription(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	query := `DELETE FROM push_subscriptions WHERE user_id = $1 AND endpoint = $2`
+
+	result, err := r.db.Exec(ctx, query, userID, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("push subscription not found")
+	}
+
+	return nil
+}
+
+// GetPushSubscriptions retrieves the push subscriptions of a user
+func (r *Repository) GetPushSubscriptions(ctx context.Context, userID uuid.UUID) ([]models.PushSubscription, error) {
+	query := `
+		SELECT id, user_id, endpoint, p256dh, auth, user_agent, created_at, last_used_at
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.PushSubscription{}
+	for rows.Next() {
+		var sub models.PushSubscription
+		err := rows.Scan(
+			&sub.ID,
+			&sub.UserID,
+			&sub.Endpoint,
+			&sub.P256dh,
+			&sub.Auth,
+			&sub.UserAgent,
+			&sub.CreatedAt,
+			&sub.LastUsedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// PrunePushSubscription removes a subscription the push service reported as gone
+func (r *Repository) PrunePushSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	query := `DELETE FROM push_subscriptions WHERE id = $1`
+	if _, err := r.db.Exec(ctx, query, subscriptionID); err != nil {
+		return fmt.Errorf("failed to prune push subscription: %w", err)
+	}
+	return nil
+}
+
+// TouchPushSubscription records a successful delivery to a subscription
+func (r *Repository) TouchPushSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	query := `UPDATE push_subscriptions SET last_used_at = NOW() WHERE id = $1`
+	if _, err := r.db.Exec(ctx, query, subscriptionID); err != nil {
+		return fmt.Errorf("failed to update push subscription: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// actorsShown is how many actors are returned with each notification
+const actorsShown = 3
+
+// maxMentions is how many distinct users a single text can notify
+const maxMentions = 10
+
+// deliveryTimeout bounds the time spent emailing and pushing a notification
+const deliveryTimeout = 30 * time.Second
+
+// mentionPattern matches @username, using the username format enforced by the users table
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([a-zA-Z0-9_-]{3,20})\b`)
+
+// Target identifies what a notification is about
+type Target struct {
+	Type   string
+	ID     uuid.UUID
+	PostID *uuid.UUID // Post of a comment target
+}
+
+// PostTarget returns the target for a post
+func PostTarget(postID uuid.UUID) Target {
+	return Target{Type: models.NotificationTargetPost, ID: postID}
+}
+
+// CommentTarget returns the target for a comment of a post
+func CommentTarget(commentID, postID uuid.UUID) Target {
+	return Target{Type: models.NotificationTargetComment, ID: commentID, PostID: &postID}
+}
+
+// EventTarget returns the target for an event
+func EventTarget(eventID uuid.UUID) Target {
+	return Target{Type: models.NotificationTargetEvent, ID: eventID}
+}
+
+// UserTarget returns the target for a user account
+func UserTarget(userID uuid.UUID) Target {
+	return Target{Type: models.NotificationTargetUser, ID: userID}
+}
+
+type Service struct {
+	repo    *Repository
+	hub     *realtime.Hub
+	emailer *Emailer
+	pusher  *Pusher // nil when Web Push is disabled
+}
+
+func NewService(repo *Repository, hub *realtime.Hub, emailer *Emailer, pusher *Pusher) *Service {
+	return &Service{repo: repo, hub: hub, emailer: emailer, pusher: pusher}
+}
+
+// Notify tells the recipient that actor did something of the given type on the
+// target, through the channels the recipient chose for that type. Users are
+// never notified of their own actions. actorID is uuid.Nil for notifications no
+// user caused, such as reminders. Failures are logged rather than returned so
+// they never fail the action being notified.
+func (s *Service) Notify(ctx context.Context, recipientID, actorID uuid.UUID, kind string, target Target) {
+	if recipientID == actorID {
+		return
+	}
+
+	preference, err := s.preference(ctx, recipientID, kind)
+	if err != nil {
+		log.Printf("Notify %s of %s on %s %s error: %v", recipientID, kind, target.Type, target.ID, err)
+		return
+	}
+
+	if preference.InApp {
+		notificationID, err := s.repo.AddActor(ctx, recipientID, actorID, kind, target)
+		if err != nil {
+			log.Printf("Notify %s of %s on %s %s error: %v", recipientID, kind, target.Type, target.ID, err)
+		} else {
+			s.publish(ctx, notificationID, recipientID)
+		}
+	}
+
+	push := preference.Push && s.pusher != nil
+	if preference.Email || push {
+		go s.deliver(recipientID, actorID, kind, target, preference.Email, push)
+	}
+}
+
+// deliver emails and pushes a notification in the background, as both go
+// through remote services that can be slow
+func (s *Service) deliver(recipientID, actorID uuid.UUID, kind string, target Target, email, push bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	to, err := s.repo.GetRecipient(ctx, recipientID)
+	if err != nil {
+		log.Printf("Deliver %s to %s error: %v", kind, recipientID, err)
+		return
+	}
+	if !to.IsActive {
+		return
+	}
+
+	now := time.Now()
+	n := &models.Notification{
+		UserID:     recipientID,
+		Type:       kind,
+		TargetType: target.Type,
+		TargetID:   target.ID,
+		PostID:     target.PostID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Actors:     []models.UserResponse{},
+	}
+
+	if actorID != uuid.Nil {
+		actor, err := s.repo.GetUser(ctx, actorID)
+		if err != nil {
+			log.Printf("Deliver %s to %s error: %v", kind, recipientID, err)
+			return
+		}
+		n.Actors = append(n.Actors, *actor)
+		n.ActorsCount = 1
+	}
+
+	if email && to.Email != nil {
+		if err := s.emailer.SendNotification(ctx, to, n); err != nil {
+			log.Printf("Email %s of %s error: %v", recipientID, kind, err)
+		}
+	}
+
+	if push {
+		if err := s.pusher.Send(ctx, to, n); err != nil {
+			log.Printf("Push %s to %s error: %v", kind, recipientID, err)
+		}
+	}
+}
+
+// publish streams a new or regrouped notification to the recipient's open clients
+func (s *Service) publish(ctx context.Context, notificationID, recipientID uuid.UUID) {
+	notification, err := s.getNotification(ctx, notificationID, recipientID)
+	if err != nil {
+		log.Printf("Publish notification %s error: %v", notificationID, err)
+		return
+	}
+
+	unreadCount, err := s.repo.CountUnread(ctx, recipientID)
+	if err != nil {
+		log.Printf("Publish notification %s error: %v", notificationID, err)
+		return
+	}
+
+	s.hub.Publish(ctx, realtime.UserTopic(recipientID), realtime.EventNotification, map[string]interface{}{
+		"notification": notification,
+		"unread_count": unreadCount,
+	})
+}
+
+// NotifyAll notifies each of the recipients once
+func (s *Service) NotifyAll(ctx context.Context, recipientIDs []uuid.UUID, actorID uuid.UUID, kind string, target Target) {
+	seen := make(map[uuid.UUID]bool, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		if seen[recipientID] {
+			continue
+		}
+		seen[recipientID] = true
+		s.Notify(ctx, recipientID, actorID, kind, target)
+	}
+}
+
+// MentionedUserIDs returns the active users mentioned with @username in a text
+func (s *Service) MentionedUserIDs(ctx context.Context, text string) ([]uuid.UUID, error) {
+	usernames := extractMentions(text)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	return s.repo.GetActiveUserIDs(ctx, usernames)
+}
+
+// GetNotifications retrieves a page of the user's notifications, most recent
+// activity first, and the cursor of the next page, empty on the last page
+func (s *Service) GetNotifications(ctx context.Context, userID uuid.UUID, rawCursor string, unreadOnly bool, limit int) ([]models.Notification, string, error) {
+	before, err := utils.ParseCursor(rawCursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// One more row than asked tells whether there is a next page
+	notifications, err := s.repo.GetNotifications(ctx, userID, before, unreadOnly, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		last := notifications[limit-1]
+		nextCursor = utils.EncodeCursor(&utils.Cursor{At: last.UpdatedAt, ID: last.ID})
+	}
+
+	if err := s.attachActors(ctx, notifications); err != nil {
+		return nil, "", err
+	}
+
+	return notifications, nextCursor, nil
+}
+
+// GetUnreadCount counts the user's unread notifications
+func (s *Service) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *Service) MarkRead(ctx context.Context, notificationID, userID uuid.UUID) (*models.Notification, error) {
+	if err := s.repo.MarkRead(ctx, notificationID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getNotification(ctx, notificationID, userID)
+}
+
+// getNotification retrieves one of the user's notifications with its actors
+func (s *Service) getNotification(ctx context.Context, notificationID, userID uuid.UUID) (*models.Notification, error) {
+	notification, err := s.repo.GetNotification(ctx, notificationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []models.Notification{*notification}
+	if err := s.attachActors(ctx, notifications); err != nil {
+		return nil, err
+	}
+
+	return &notifications[0], nil
+}
+
+// MarkAllRead marks all of the user's notifications as read
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// attachActors loads the latest actors of the notifications and writes their messages
+func (s *Service) attachActors(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	actors, err := s.repo.GetActors(ctx, notificationIDs(notifications), actorsShown)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		notifications[i].Actors = actors[notifications[i].ID]
+		if notifications[i].Actors == nil {
+			notifications[i].Actors = []models.UserResponse{}
+		}
+		notifications[i].Message = message(&notifications[i])
+	}
+
+	return nil
+}
+
+func notificationIDs(notifications []models.Notification) []uuid.UUID {
+	ids := make([]uuid.UUID, len(notifications))
+	for i, n := range notifications {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+// extractMentions returns the distinct usernames mentioned in a text
+func extractMentions(text string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		if seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		usernames = append(usernames, match[1])
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
@@ -0,0 +1,137 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+)
+
+// defaultLanguage is used for users whose language has no templates, matching
+// the default language of new accounts
+const defaultLanguage = "fr"
+
+// templatesFS holds one directory of templates per language. Each directory has
+// the same templates: messages.tmpl defines the shared wording, *.txt.tmpl the
+// email subjects and text bodies and *.html.tmpl the HTML bodies.
+//
+//go:embed templates
+var templatesFS embed.FS
+
+// templates renders notifications in the language of their recipient
+type templates struct {
+	frontendURL string
+	text        map[string]*texttemplate.Template
+	html        map[string]*htmltemplate.Template
+}
+
+func loadTemplates(cfg *config.Config) (*templates, error) {
+	t := &templates{
+		frontendURL: strings.TrimRight(cfg.CORS.FrontendURL, "/"),
+		text:        make(map[string]*texttemplate.Template),
+		html:        make(map[string]*htmltemplate.Template),
+	}
+
+	funcs := map[string]interface{}{
+		"name": func(user models.UserResponse) string { return displayName(&user) },
+		"sub":  func(a, b int) int { return a - b },
+		"link": func(n models.Notification) string { return t.link(&n) },
+		"eventLink": func(id uuid.UUID) string {
+			return t.frontendURL + "/events/" + id.String()
+		},
+		"notificationsLink": func() string { return t.frontendURL + "/notifications" },
+		"settingsLink":      func() string { return t.frontendURL + "/settings/notifications" },
+	}
+
+	languages, err := fs.ReadDir(templatesFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	for _, language := range languages {
+		dir := "templates/" + language.Name()
+
+		text, err := texttemplate.New(language.Name()).Funcs(funcs).
+			ParseFS(templatesFS, dir+"/messages.tmpl", dir+"/*.txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s templates: %w", language.Name(), err)
+		}
+
+		html, err := htmltemplate.New(language.Name()).Funcs(funcs).
+			ParseFS(templatesFS, dir+"/messages.tmpl", dir+"/*.html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s templates: %w", language.Name(), err)
+		}
+
+		t.text[language.Name()] = text
+		t.html[language.Name()] = html
+	}
+
+	if t.text[defaultLanguage] == nil {
+		return nil, fmt.Errorf("missing %s templates", defaultLanguage)
+	}
+
+	return t, nil
+}
+
+// renderText executes a text template in the given language, or the default
+// one when it has no templates
+func (t *templates) renderText(language, name string, data interface{}) (string, error) {
+	tmpl, ok := t.text[language]
+	if !ok {
+		tmpl = t.text[defaultLanguage]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderHTML executes an HTML template in the given language, or the default
+// one when it has no templates
+func (t *templates) renderHTML(language, name string, data interface{}) (string, error) {
+	tmpl, ok := t.html[language]
+	if !ok {
+		tmpl = t.html[defaultLanguage]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// link returns the frontend URL of what a notification is about
+func (t *templates) link(n *models.Notification) string {
+	return t.frontendURL + targetPath(n)
+}
+
+// targetPath returns the frontend path of what a notification is about
+func targetPath(n *models.Notification) string {
+	switch n.TargetType {
+	case models.NotificationTargetPost:
+		return "/posts/" + n.TargetID.String()
+	case models.NotificationTargetComment:
+		if n.PostID != nil {
+			return "/posts/" + n.PostID.String()
+		}
+	case models.NotificationTargetEvent:
+		return "/events/" + n.TargetID.String()
+	case models.NotificationTargetUser:
+		// Follows target the followed user, so link to the follower instead
+		if len(n.Actors) > 0 {
+			return "/users/" + n.Actors[0].Username
+		}
+	}
+	return "/notifications"
+}
@@ -1,9 +1,13 @@
 package post
 
 import (
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/Aolakije/City-Buzz/internal/geo"
 	"github.com/Aolakije/City-Buzz/internal/models"
 	"github.com/Aolakije/City-Buzz/pkg/utils"
 )
@@ -35,69 +39,610 @@ func (h *Handler) CreatePost(c *fiber.Ctx) error {
 
 	post, err := h.service.CreatePost(c.Context(), userID, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "poll ") || isScheduleError(err) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		if err.Error() == "event not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Event not found")
+		}
 		log.Printf("Create post error: %v", err)
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create post")
 	}
 
-	log.Printf("Post created: ID=%s by User=%s", post.ID, userID)
+	log.Printf("Post created: ID=%s by User=%s", post.ID, userID)
+
+	message := "Post created successfully"
+	switch {
+	case post.IsHeld:
+		message = "Post submitted for review"
+	case post.Status == models.PostStatusDraft:
+		message = "Draft saved"
+	case post.Status == models.PostStatusScheduled:
+		message = "Post scheduled"
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, message, fiber.Map{
+		"post": post,
+	})
+}
+
+// GetFeed handles feed retrieval
+// GET /api/v1/posts?page=1&limit=10
+func (h *Handler) GetFeed(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetFeed(c.Context(), page, limit, userID)
+	if err != nil {
+		log.Printf("Get feed error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get feed")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts": posts,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetPost handles single post retrieval
+// GET /api/v1/posts/:id
+func (h *Handler) GetPost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	post, err := h.service.GetPostByID(c.Context(), postID, userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"post": post,
+	})
+}
+
+// UpdatePost handles post update
+// PUT /api/v1/posts/:id
+func (h *Handler) UpdatePost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	var req models.UpdatePostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.UpdatePost(c.Context(), postID, userID, &req); err != nil {
+		switch err.Error() {
+		case "post not found", "post not found or already deleted":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		case "plain reposts cannot be edited":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Plain reposts cannot be edited")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		log.Printf("Update post error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update post")
+	}
+
+	log.Printf("Post updated: ID=%s by User=%s", postID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post updated successfully", nil)
+}
+
+// GetPostRevisions handles post edit history retrieval
+// GET /api/v1/posts/:id/revisions
+func (h *Handler) GetPostRevisions(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	post, revisions, err := h.service.GetPostRevisions(c.Context(), postID, userID)
+	if err != nil {
+		if err.Error() == "post not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		log.Printf("Get post revisions error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get post revisions")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"post":      post,
+		"revisions": revisions,
+	})
+}
+
+// DeletePost handles post deletion
+// DELETE /api/v1/posts/:id
+func (h *Handler) DeletePost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	if err := h.service.DeletePost(c.Context(), postID, userID); err != nil {
+		if err.Error() == "post not found or already deleted" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		log.Printf("Delete post error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete post")
+	}
+
+	log.Printf("Post deleted: ID=%s by User=%s", postID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post deleted successfully", nil)
+}
+
+// GetTrash handles retrieval of the user's deleted posts that can still be restored
+// GET /api/v1/posts/trash?page=1&limit=10
+func (h *Handler) GetTrash(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetTrash(c.Context(), userID, page, limit)
+	if err != nil {
+		log.Printf("Get trash error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get trash")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts":          posts,
+		"retention_days": int(h.service.GetTrashRetention().Hours() / 24),
+		"page":           page,
+		"limit":          limit,
+	})
+}
+
+// RestorePost handles taking a deleted post out of the trash
+// POST /api/v1/posts/:id/restore
+func (h *Handler) RestorePost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	post, err := h.service.RestorePost(c.Context(), postID, userID)
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found in trash")
+		case "restore period expired":
+			return utils.ErrorResponse(c, fiber.StatusGone, "Post can no longer be restored")
+		case "original post unavailable":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "The reposted post is no longer available or was reposted again")
+		}
+		log.Printf("Restore post error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to restore post")
+	}
+
+	log.Printf("Post restored: ID=%s by User=%s", postID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post restored", fiber.Map{
+		"post": post,
+	})
+}
+
+// GetNearbyPosts handles retrieval of posts tagged near a point
+// GET /api/v1/posts/nearby?lat=49.44&lon=1.09&radius_km=5&sort=recent&page=1&limit=10
+func (h *Handler) GetNearbyPosts(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	latitude, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	longitude, errLon := strconv.ParseFloat(c.Query("lon"), 64)
+	if errLat != nil || errLon != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "lat and lon are required")
+	}
+
+	radiusKm := c.QueryFloat("radius_km", 5)
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetNearbyPosts(c.Context(), latitude, longitude, radiusKm, c.Query("sort"), userID, page, limit)
+	if err != nil {
+		switch err.Error() {
+		case "invalid coordinates":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid coordinates")
+		case "invalid radius":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("radius_km must be between 0 and %g", geo.MaxRadiusKm))
+		case "invalid sort":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Sort must be recent or distance")
+		}
+		log.Printf("Get nearby posts error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get nearby posts")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts":     posts,
+		"radius_km": radiusKm,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// GetEventPosts handles retrieval of an event's discussion thread
+// GET /api/v1/events/:id/posts?page=1&limit=10
+func (h *Handler) GetEventPosts(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	eventID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid event ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetEventPosts(c.Context(), eventID, userID, page, limit)
+	if err != nil {
+		log.Printf("Get event posts error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get event posts")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts": posts,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// PinEventPost handles pinning a post at the top of an event's thread
+// POST /api/v1/events/:id/posts/:postId/pin
+func (h *Handler) PinEventPost(c *fiber.Ctx) error {
+	return h.setEventPin(c, true)
+}
+
+// UnpinEventPost handles unpinning a post from an event's thread
+// DELETE /api/v1/events/:id/posts/:postId/pin
+func (h *Handler) UnpinEventPost(c *fiber.Ctx) error {
+	return h.setEventPin(c, false)
+}
+
+func (h *Handler) setEventPin(c *fiber.Ctx, pin bool) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	eventID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid event ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("postId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	message := "Post pinned"
+	if pin {
+		err = h.service.PinEventPost(c.Context(), eventID, postID, userID)
+	} else {
+		message = "Post unpinned"
+		err = h.service.UnpinEventPost(c.Context(), eventID, postID, userID)
+	}
+
+	if err != nil {
+		switch err.Error() {
+		case "event not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Event not found")
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found in this event")
+		case "post not pinned":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post is not pinned")
+		case "too many pinned posts":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Unpin a post before pinning another one")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Only the event organizer can pin posts")
+		}
+		log.Printf("Event pin error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update pinned post")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, message, nil)
+}
+
+// GetUserPosts handles retrieval of a user's posts for their profile
+// GET /api/v1/users/:username/posts?page=1&limit=10
+func (h *Handler) GetUserPosts(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetUserPosts(c.Context(), c.Params("username"), page, limit, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Get user posts error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get posts")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts": posts,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// PinProfilePost handles pinning one of the user's posts to their profile
+// POST /api/v1/posts/:id/pin
+func (h *Handler) PinProfilePost(c *fiber.Ctx) error {
+	return h.setProfilePin(c, true)
+}
+
+// UnpinProfilePost handles unpinning one of the user's posts from their profile
+// DELETE /api/v1/posts/:id/pin
+func (h *Handler) UnpinProfilePost(c *fiber.Ctx) error {
+	return h.setProfilePin(c, false)
+}
+
+func (h *Handler) setProfilePin(c *fiber.Ctx, pin bool) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	message := "Post pinned to profile"
+	if pin {
+		err = h.service.PinProfilePost(c.Context(), postID, userID)
+	} else {
+		message = "Post unpinned from profile"
+		err = h.service.UnpinProfilePost(c.Context(), postID, userID)
+	}
+
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found among your published posts")
+		case "post not pinned":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post is not pinned")
+		case "too many pinned posts":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Unpin a post before pinning another one")
+		}
+		log.Printf("Profile pin error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update pinned post")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, message, nil)
+}
+
+// GetDrafts handles retrieval of the user's drafts and scheduled posts
+// GET /api/v1/posts/drafts?page=1&limit=10
+func (h *Handler) GetDrafts(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	posts, err := h.service.GetDrafts(c.Context(), userID, page, limit)
+	if err != nil {
+		log.Printf("Get drafts error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get drafts")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"posts": posts,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// SchedulePost handles scheduling a draft, or rescheduling a scheduled post
+// PUT /api/v1/posts/:id/schedule
+func (h *Handler) SchedulePost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	var req models.SchedulePostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	post, err := h.service.SchedulePost(c.Context(), postID, userID, &req)
+	if err != nil {
+		return scheduleErrorResponse(c, "Schedule post", err)
+	}
 
-	return utils.SuccessResponse(c, fiber.StatusCreated, "Post created successfully", fiber.Map{
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post scheduled", fiber.Map{
 		"post": post,
 	})
 }
 
-// GetFeed handles feed retrieval
-// GET /api/v1/posts?page=1&limit=10
-func (h *Handler) GetFeed(c *fiber.Ctx) error {
+// UnschedulePost handles turning a scheduled post back into a draft
+// DELETE /api/v1/posts/:id/schedule
+func (h *Handler) UnschedulePost(c *fiber.Ctx) error {
 	userID, err := utils.ParseUUID(c.Locals("userID").(string))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 10)
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 50 {
-		limit = 10
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
 	}
 
-	posts, err := h.service.GetFeed(c.Context(), page, limit, userID)
+	post, err := h.service.UnschedulePost(c.Context(), postID, userID)
 	if err != nil {
-		log.Printf("Get feed error: %v", err)
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get feed")
+		return scheduleErrorResponse(c, "Unschedule post", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
-		"posts": posts,
-		"page":  page,
-		"limit": limit,
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post moved back to drafts", fiber.Map{
+		"post": post,
 	})
 }
 
-// GetPost handles single post retrieval
-// GET /api/v1/posts/:id
-func (h *Handler) GetPost(c *fiber.Ctx) error {
+// PublishPost handles publishing a draft or scheduled post right away
+// POST /api/v1/posts/:id/publish
+func (h *Handler) PublishPost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
 	postID, err := utils.ParseUUID(c.Params("id"))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
 	}
 
-	post, err := h.service.GetPostByID(c.Context(), postID)
+	post, err := h.service.PublishPost(c.Context(), postID, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		return scheduleErrorResponse(c, "Publish post", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+	log.Printf("Post published: ID=%s by User=%s", postID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Post published", fiber.Map{
 		"post": post,
 	})
 }
 
-// UpdatePost handles post update
-// PUT /api/v1/posts/:id
-func (h *Handler) UpdatePost(c *fiber.Ctx) error {
+// isScheduleError reports whether err is a user error about a post's publication time
+func isScheduleError(err error) bool {
+	return strings.HasPrefix(err.Error(), "scheduled time ") || err.Error() == "a post cannot be both a draft and scheduled"
+}
+
+// scheduleErrorResponse maps errors from draft and schedule operations to responses
+func scheduleErrorResponse(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case err.Error() == "post not found":
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+	case err.Error() == "post already published":
+		return utils.ErrorResponse(c, fiber.StatusConflict, "Post is already published")
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+	case strings.HasPrefix(err.Error(), "poll ") || isScheduleError(err):
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	log.Printf("%s error: %v", action, err)
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update post")
+}
+
+// Repost handles reposting and quoting a post
+// POST /api/v1/posts/:id/repost
+func (h *Handler) Repost(c *fiber.Ctx) error {
 	userID, err := utils.ParseUUID(c.Locals("userID").(string))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
@@ -108,28 +653,41 @@ func (h *Handler) UpdatePost(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
 	}
 
-	var req models.UpdatePostRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	var req models.RepostRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
 	}
 
 	if err := utils.ValidateStruct(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	if err := h.service.UpdatePost(c.Context(), postID, userID, &req); err != nil {
-		log.Printf("Update post error: %v", err)
-		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+	repost, err := h.service.Repost(c.Context(), postID, userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		case "post already reposted":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Post already reposted")
+		case "only public posts can be reposted":
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Only public posts can be reposted")
+		}
+		log.Printf("Repost error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to repost")
 	}
 
-	log.Printf("Post updated: ID=%s by User=%s", postID, userID)
+	log.Printf("Post reposted: ID=%s of Post=%s by User=%s", repost.ID, postID, userID)
 
-	return utils.SuccessResponse(c, fiber.StatusOK, "Post updated successfully", nil)
+	return utils.SuccessResponse(c, fiber.StatusCreated, "Post reposted", fiber.Map{
+		"post": repost,
+	})
 }
 
-// DeletePost handles post deletion
-// DELETE /api/v1/posts/:id
-func (h *Handler) DeletePost(c *fiber.Ctx) error {
+// DeleteRepost handles undoing a plain repost
+// DELETE /api/v1/posts/:id/repost
+func (h *Handler) DeleteRepost(c *fiber.Ctx) error {
 	userID, err := utils.ParseUUID(c.Locals("userID").(string))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
@@ -140,14 +698,55 @@ func (h *Handler) DeletePost(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
 	}
 
-	if err := h.service.DeletePost(c.Context(), postID, userID); err != nil {
-		log.Printf("Delete post error: %v", err)
-		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+	if err := h.service.DeleteRepost(c.Context(), postID, userID); err != nil {
+		if err.Error() == "post not reposted" {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Post not reposted")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to remove repost")
 	}
 
-	log.Printf("Post deleted: ID=%s by User=%s", postID, userID)
+	return utils.SuccessResponse(c, fiber.StatusOK, "Repost removed", nil)
+}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, "Post deleted successfully", nil)
+// VotePoll handles voting on a post's poll
+// POST /api/v1/posts/:id/poll/vote
+func (h *Handler) VotePoll(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	var req models.PollVoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	poll, err := h.service.VotePoll(c.Context(), postID, userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "post not found", "poll not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Poll not found")
+		case "already voted":
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Already voted")
+		case "poll is closed", "invalid poll option", "this poll allows a single choice":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		log.Printf("Vote poll error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to vote")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Vote recorded", fiber.Map{
+		"poll": poll,
+	})
 }
 
 // LikePost handles post liking
@@ -164,7 +763,10 @@ func (h *Handler) LikePost(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.LikePost(c.Context(), postID, userID); err != nil {
-		if err.Error() == "post already liked" {
+		switch err.Error() {
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		case "post already liked":
 			return utils.ErrorResponse(c, fiber.StatusConflict, "Post already liked")
 		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to like post")
@@ -196,6 +798,74 @@ func (h *Handler) UnlikePost(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, fiber.StatusOK, "Post unliked", nil)
 }
 
+// ReactToPost handles setting a reaction on a post
+// PUT /api/v1/posts/:id/reaction
+func (h *Handler) ReactToPost(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	var req models.ReactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.ReactToPost(c.Context(), postID, userID, req.Reaction); err != nil {
+		switch err.Error() {
+		case "post not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		case "invalid reaction":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid reaction")
+		}
+		log.Printf("React to post error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to react to post")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Reaction saved", nil)
+}
+
+// RemovePostReaction handles removing the user's reaction from a post
+// DELETE /api/v1/posts/:id/reaction
+func (h *Handler) RemovePostReaction(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	postID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+	}
+
+	if err := h.service.RemovePostReaction(c.Context(), postID, userID); err != nil {
+		if err.Error() == "reaction not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Reaction not found")
+		}
+		log.Printf("Remove post reaction error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to remove reaction")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Reaction removed", nil)
+}
+
+// GetReactionTypes returns the available reactions
+// GET /api/v1/reactions
+func (h *Handler) GetReactionTypes(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, fiber.StatusOK, "Reactions retrieved successfully", fiber.Map{
+		"reactions": h.service.GetReactionTypes(),
+	})
+}
+
 // CreateComment handles comment creation
 // POST /api/v1/posts/:id/comments
 func (h *Handler) CreateComment(c *fiber.Ctx) error {
@@ -226,7 +896,12 @@ func (h *Handler) CreateComment(c *fiber.Ctx) error {
 
 	log.Printf("Comment created: ID=%s on Post=%s by User=%s", comment.ID, postID, userID)
 
-	return utils.SuccessResponse(c, fiber.StatusCreated, "Comment created successfully", fiber.Map{
+	message := "Comment created successfully"
+	if comment.IsHeld {
+		message = "Comment submitted for review"
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, message, fiber.Map{
 		"comment": comment,
 	})
 }
@@ -246,6 +921,9 @@ func (h *Handler) GetComments(c *fiber.Ctx) error {
 
 	comments, err := h.service.GetCommentsByPostID(c.Context(), postID, userID)
 	if err != nil {
+		if err.Error() == "post not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		}
 		log.Printf("Get comments error: %v", err)
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get comments")
 	}
@@ -255,6 +933,74 @@ func (h *Handler) GetComments(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateComment handles comment update
+// PUT /api/v1/comments/:id
+func (h *Handler) UpdateComment(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.UpdateComment(c.Context(), commentID, userID, &req); err != nil {
+		if err.Error() == "comment not found or already deleted" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		log.Printf("Update comment error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update comment")
+	}
+
+	log.Printf("Comment updated: ID=%s by User=%s", commentID, userID)
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Comment updated successfully", nil)
+}
+
+// GetCommentRevisions handles comment edit history retrieval
+// GET /api/v1/comments/:id/revisions
+func (h *Handler) GetCommentRevisions(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	revisions, err := h.service.GetCommentRevisions(c.Context(), commentID, userID)
+	if err != nil {
+		if err.Error() == "comment not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		}
+		if strings.HasPrefix(err.Error(), "unauthorized") {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+		}
+		log.Printf("Get comment revisions error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get comment revisions")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"revisions": revisions,
+	})
+}
+
 // DeleteComment handles comment deletion
 // DELETE /api/v1/comments/:id
 func (h *Handler) DeleteComment(c *fiber.Ctx) error {
@@ -276,4 +1022,112 @@ func (h *Handler) DeleteComment(c *fiber.Ctx) error {
 	log.Printf("Comment deleted: ID=%s by User=%s", commentID, userID)
 
 	return utils.SuccessResponse(c, fiber.StatusOK, "Comment deleted successfully", nil)
-}
\ No newline at end of file
+}
+
+// LikeComment handles comment liking
+// POST /api/v1/comments/:id/like
+func (h *Handler) LikeComment(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	if err := h.service.LikeComment(c.Context(), commentID, userID); err != nil {
+		if err.Error() == "comment not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		}
+		log.Printf("Like comment error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to like comment")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Comment liked", nil)
+}
+
+// UnlikeComment handles comment unliking
+// DELETE /api/v1/comments/:id/like
+func (h *Handler) UnlikeComment(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	if err := h.service.UnlikeComment(c.Context(), commentID, userID); err != nil {
+		if err.Error() == "comment not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		}
+		log.Printf("Unlike comment error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unlike comment")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Comment unliked", nil)
+}
+
+// ReactToComment handles setting a reaction on a comment
+// PUT /api/v1/comments/:id/reaction
+func (h *Handler) ReactToComment(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	var req models.ReactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.service.ReactToComment(c.Context(), commentID, userID, req.Reaction); err != nil {
+		switch err.Error() {
+		case "comment not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		case "invalid reaction":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid reaction")
+		}
+		log.Printf("React to comment error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to react to comment")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Reaction saved", nil)
+}
+
+// RemoveCommentReaction handles removing the user's reaction from a comment
+// DELETE /api/v1/comments/:id/reaction
+func (h *Handler) RemoveCommentReaction(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	commentID, err := utils.ParseUUID(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid comment ID")
+	}
+
+	if err := h.service.RemoveCommentReaction(c.Context(), commentID, userID); err != nil {
+		if err.Error() == "comment not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Comment not found")
+		}
+		log.Printf("Remove comment reaction error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to remove reaction")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Reaction removed", nil)
+}
@@ -0,0 +1,120 @@
+package post
+
+import (
+	"context"
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/google/uuid"
+)
+
+// notifyMentions notifies the users mentioned in a published post who can see it
+func notifyMentions(ctx context.Context, repo *Repository, notifications *notification.Service, post *models.Post) {
+	if post.IsHeld || !post.IsPublished() {
+		return
+	}
+
+	recipients := visibleMentions(ctx, repo, notifications, post, post.Content, nil)
+	notifications.NotifyAll(ctx, recipients, post.UserID, models.NotificationMention, notification.PostTarget(post.ID))
+}
+
+// visibleMentions returns the users mentioned in text who can see the post,
+// leaving out those in skip
+func visibleMentions(ctx context.Context, repo *Repository, notifications *notification.Service, post *models.Post, text string, skip map[uuid.UUID]bool) []uuid.UUID {
+	mentioned, err := notifications.MentionedUserIDs(ctx, text)
+	if err != nil {
+		log.Printf("Mentions of post %s error: %v", post.ID, err)
+		return nil
+	}
+
+	var recipients []uuid.UUID
+	for _, userID := range mentioned {
+		if skip[userID] {
+			continue
+		}
+		canView, err := canViewPost(ctx, repo, post, userID)
+		if err != nil {
+			log.Printf("Mention visibility of post %s error: %v", post.ID, err)
+			continue
+		}
+		if canView {
+			recipients = append(recipients, userID)
+		}
+	}
+
+	return recipients
+}
+
+// notifyComment notifies the author of the post, the other users who commented
+// on it and the users mentioned in the comment
+func (s *Service) notifyComment(ctx context.Context, post *models.Post, comment *models.Comment) {
+	if comment.IsHeld {
+		return
+	}
+
+	notified := map[uuid.UUID]bool{post.UserID: true, comment.UserID: true}
+	s.notifications.Notify(ctx, post.UserID, comment.UserID, models.NotificationComment, notification.PostTarget(post.ID))
+
+	commenters, err := s.repo.GetCommenterIDs(ctx, post.ID)
+	if err != nil {
+		log.Printf("Commenters of post %s error: %v", post.ID, err)
+	}
+
+	var replied []uuid.UUID
+	for _, userID := range commenters {
+		if notified[userID] {
+			continue
+		}
+		// Commenters may have lost access to the post since, e.g. by unfollowing
+		canView, err := canViewPost(ctx, s.repo, post, userID)
+		if err != nil {
+			log.Printf("Commenter visibility of post %s error: %v", post.ID, err)
+			continue
+		}
+		if canView {
+			notified[userID] = true
+			replied = append(replied, userID)
+		}
+	}
+	s.notifications.NotifyAll(ctx, replied, comment.UserID, models.NotificationReply, notification.PostTarget(post.ID))
+
+	mentioned := visibleMentions(ctx, s.repo, s.notifications, post, comment.Content, notified)
+	s.notifications.NotifyAll(ctx, mentioned, comment.UserID, models.NotificationMention, notification.CommentTarget(comment.ID, post.ID))
+}
+
+// PublishApprovedPost notifies the users mentioned in a post a moderator
+// approved, as notifyMentions skipped them while it was held
+func (s *Service) PublishApprovedPost(ctx context.Context, postID uuid.UUID) {
+	post, err := s.repo.GetPostByID(ctx, postID, uuid.Nil)
+	if err != nil {
+		log.Printf("Approved post %s notification error: %v", postID, err)
+		return
+	}
+	notifyMentions(ctx, s.repo, s.notifications, post)
+}
+
+// PublishApprovedComment sends the notifications and the realtime event a
+// comment skipped while it was held, once a moderator approved it
+func (s *Service) PublishApprovedComment(ctx context.Context, commentID uuid.UUID) {
+	comment, err := s.repo.GetComment(ctx, commentID)
+	if err != nil {
+		log.Printf("Approved comment %s notification error: %v", commentID, err)
+		return
+	}
+
+	post, err := s.repo.GetPostByID(ctx, comment.PostID, uuid.Nil)
+	if err != nil {
+		log.Printf("Approved comment %s notification error: %v", commentID, err)
+		return
+	}
+
+	s.notifyComment(ctx, post, comment)
+	s.hub.Publish(ctx, realtime.PostTopic(post.ID), realtime.EventComment, comment)
+}
+
+// notifyCommentReaction notifies the author of a comment that it received a reaction
+func (s *Service) notifyCommentReaction(ctx context.Context, comment *models.Comment, userID uuid.UUID) {
+	s.notifications.Notify(ctx, comment.UserID, userID, models.NotificationLike, notification.CommentTarget(comment.ID, comment.PostID))
+}
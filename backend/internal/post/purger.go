@@ -0,0 +1,67 @@
+package post
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+)
+
+// Purger permanently deletes posts that have stayed in the trash longer than
+// the retention period
+type Purger struct {
+	repo      *Repository
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPurger(repo *Repository, cfg *config.Config) *Purger {
+	return &Purger{
+		repo:      repo,
+		retention: cfg.Trash.Retention,
+		interval:  cfg.Trash.PurgeInterval,
+		batchSize: cfg.Trash.PurgeBatchSize,
+	}
+}
+
+// Run purges expired posts right away and then on every interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PurgeExpired(ctx); err != nil {
+			log.Printf("Trash purge error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired permanently deletes every expired post, one batch at a time
+func (p *Purger) PurgeExpired(ctx context.Context) error {
+	var total int64
+	for {
+		purged, err := p.repo.PurgeDeletedPosts(ctx, p.retention, p.batchSize)
+		if err != nil {
+			return err
+		}
+
+		total += purged
+		if purged < int64(p.batchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Purged %d deleted posts", total)
+	}
+
+	return nil
+}
@@ -3,7 +3,10 @@ package post
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
+	"github.com/Aolakije/City-Buzz/internal/geo"
 	"github.com/Aolakije/City-Buzz/internal/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -18,18 +21,85 @@ func NewRepository(db *pgxpool.Pool) *Repository {
 	return &Repository{db: db}
 }
 
-// CreatePost creates a new post
+// postColumns lists the post and author columns shared by post queries.
+// Queries using it must alias posts as p and users as u, and scan with scanPost.
+const postColumns = `
+		p.id, p.user_id, p.content, p.likes_count, p.comments_count, p.repost_count,
+		p.reaction_counts, p.visibility, p.status, p.scheduled_at, p.city, p.link_url, p.repost_of_id,
+		p.event_id, p.event_pinned_at, p.profile_pinned_at, p.latitude, p.longitude, p.place_name, p.created_at, p.updated_at, p.edited_at, p.is_deleted,
+		p.deleted_at, p.is_held, u.id, u.username, u.first_name, u.last_name, u.avatar_url`
+
+// scanPost scans a row selected with postColumns, followed by any extra columns
+func scanPost(row pgx.Row, extra ...interface{}) (*models.Post, error) {
+	var post models.Post
+	var author models.UserResponse
+
+	dest := []interface{}{
+		&post.ID,
+		&post.UserID,
+		&post.Content,
+		&post.LikesCount,
+		&post.CommentsCount,
+		&post.RepostCount,
+		&post.ReactionCounts,
+		&post.Visibility,
+		&post.Status,
+		&post.ScheduledAt,
+		&post.City,
+		&post.LinkURL,
+		&post.RepostOfID,
+		&post.EventID,
+		&post.EventPinnedAt,
+		&post.ProfilePinnedAt,
+		&post.Latitude,
+		&post.Longitude,
+		&post.PlaceName,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.EditedAt,
+		&post.IsDeleted,
+		&post.DeletedAt,
+		&post.IsHeld,
+		&author.ID,
+		&author.Username,
+		&author.FirstName,
+		&author.LastName,
+		&author.AvatarURL,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	post.Author = &author
+	post.IsEdited = post.EditedAt != nil
+	return &post, nil
+}
+
+// CreatePost creates a new post, along with its poll when it has one
 func (r *Repository) CreatePost(ctx context.Context, post *models.Post) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO posts (user_id, content)
-		VALUES ($1, $2)
-		RETURNING id, likes_count, comments_count, created_at, updated_at, is_deleted
+		INSERT INTO posts (user_id, content, visibility, status, scheduled_at, city, link_url, repost_of_id, event_id,
+		                   latitude, longitude, place_name, is_held, held_reasons)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, likes_count, comments_count, repost_count, reaction_counts, created_at, updated_at, is_deleted
 	`
 
-	err := r.db.QueryRow(ctx, query, post.UserID, post.Content).Scan(
+	err = tx.QueryRow(ctx, query,
+		post.UserID, post.Content, post.Visibility, post.Status, post.ScheduledAt, post.City, post.LinkURL, post.RepostOfID, post.EventID,
+		post.Latitude, post.Longitude, post.PlaceName, post.IsHeld, post.HeldReasons,
+	).Scan(
 		&post.ID,
 		&post.LikesCount,
 		&post.CommentsCount,
+		&post.RepostCount,
+		&post.ReactionCounts,
 		&post.CreatedAt,
 		&post.UpdatedAt,
 		&post.IsDeleted,
@@ -39,202 +109,948 @@ func (r *Repository) CreatePost(ctx context.Context, post *models.Post) error {
 		return fmt.Errorf("failed to create post: %w", err)
 	}
 
+	if post.Poll != nil {
+		if err := createPoll(ctx, tx, post.ID, post.Poll); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit post creation: %w", err)
+	}
+
 	return nil
 }
 
-// GetPostByID retrieves a post by ID
-func (r *Repository) GetPostByID(ctx context.Context, postID uuid.UUID) (*models.Post, error) {
+// createPoll inserts a post's poll and its options
+func createPoll(ctx context.Context, tx pgx.Tx, postID uuid.UUID, poll *models.Poll) error {
 	query := `
-		SELECT p.id, p.user_id, p.content, p.likes_count, p.comments_count, 
-		       p.created_at, p.updated_at, p.is_deleted,
-		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		INSERT INTO polls (post_id, allows_multiple, closes_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	poll.PostID = postID
+	if err := tx.QueryRow(ctx, query, postID, poll.AllowsMultiple, poll.ClosesAt).Scan(&poll.ID, &poll.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	optionQuery := `
+		INSERT INTO poll_options (poll_id, position, text)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	for i := range poll.Options {
+		option := &poll.Options[i]
+		option.PollID = poll.ID
+		option.Position = i
+		if err := tx.QueryRow(ctx, optionQuery, poll.ID, option.Position, option.Text).Scan(&option.ID); err != nil {
+			return fmt.Errorf("failed to create poll option: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetPostByID retrieves a post by ID, along with the current user's reaction
+// and repost of it
+func (r *Repository) GetPostByID(ctx context.Context, postID, currentUserID uuid.UUID) (*models.Post, error) {
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $2) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $2 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted
 		FROM posts p
 		JOIN users u ON p.user_id = u.id
-		WHERE p.id = $1 AND p.is_deleted = false
+		WHERE p.id = $1 AND p.is_deleted = false AND p.is_hidden = false
 	`
 
-	var post models.Post
-	var author models.UserResponse
+	var myReaction *string
+	var isReposted bool
 
-	err := r.db.QueryRow(ctx, query, postID).Scan(
-		&post.ID,
-		&post.UserID,
-		&post.Content,
-		&post.LikesCount,
-		&post.CommentsCount,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-		&post.IsDeleted,
-		&author.ID,
-		&author.Username,
-		&author.FirstName,
-		&author.LastName,
-		&author.AvatarURL,
-	)
+	post, err := scanPost(r.db.QueryRow(ctx, query, postID, currentUserID), &myReaction, &isReposted)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("post not found")
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	post.MyReaction = myReaction
+	post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+	post.IsReposted = isReposted
+	return post, nil
+}
+
+// GetPostsByIDs retrieves the published, non-deleted posts among the given IDs,
+// along with the current user's reaction and repost of each, keyed by ID
+func (r *Repository) GetPostsByIDs(ctx context.Context, postIDs []uuid.UUID, currentUserID uuid.UUID) (map[uuid.UUID]*models.Post, error) {
+	posts := make(map[uuid.UUID]*models.Post, len(postIDs))
+	if len(postIDs) == 0 {
+		return posts, nil
+	}
+
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $2) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $2 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.id = ANY($1) AND p.is_deleted = false AND p.is_hidden = false AND p.is_held = false
+		  AND p.status = 'published'
+	`
+
+	rows, err := r.db.Query(ctx, query, postIDs, currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var myReaction *string
+		var isReposted bool
+
+		post, err := scanPost(rows, &myReaction, &isReposted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+
+		post.MyReaction = myReaction
+		post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+		post.IsReposted = isReposted
+		posts[post.ID] = post
+	}
+
+	return posts, rows.Err()
+}
+
+// GetFeed retrieves paginated posts for feed, limited to posts the current user can see
+func (r *Repository) GetFeed(ctx context.Context, limit, offset int, currentUserID uuid.UUID) ([]models.Post, error) {
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $1) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $1 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.is_deleted = false AND p.is_hidden = false
+		  AND p.status = 'published'
+		  AND (p.is_held = false OR p.user_id = $1)
+		  AND (
+		      p.user_id = $1
+		      OR p.visibility = 'public'
+		      OR (p.visibility = 'followers' AND EXISTS(
+		          SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.following_id = p.user_id
+		      ))
+		  )
+		ORDER BY p.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, currentUserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+
+	for rows.Next() {
+		var myReaction *string
+		var isReposted bool
+
+		post, err := scanPost(rows, &myReaction, &isReposted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+
+		post.MyReaction = myReaction
+		post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+		post.IsReposted = isReposted
+		posts = append(posts, *post)
+	}
+
+	return posts, nil
+}
+
+// GetUserPosts retrieves the published posts of one author that the current user
+// can see, pinned posts first and then newest first
+func (r *Repository) GetUserPosts(ctx context.Context, authorID uuid.UUID, limit, offset int, currentUserID uuid.UUID) ([]models.Post, error) {
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $1) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $1 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.user_id = $2 AND p.is_deleted = false AND p.is_hidden = false
+		  AND p.status = 'published'
+		  AND (p.is_held = false OR p.user_id = $1)
+		  AND (
+		      p.user_id = $1
+		      OR p.visibility = 'public'
+		      OR (p.visibility = 'followers' AND EXISTS(
+		          SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.following_id = p.user_id
+		      ))
+		  )
+		ORDER BY p.profile_pinned_at DESC NULLS LAST, p.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, currentUserID, authorID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		var myReaction *string
+		var isReposted bool
+
+		post, err := scanPost(rows, &myReaction, &isReposted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+
+		post.MyReaction = myReaction
+		post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+		post.IsReposted = isReposted
+		posts = append(posts, *post)
+	}
+
+	return posts, rows.Err()
+}
+
+// PinProfilePost pins one of a user's published posts to the top of their profile
+func (r *Repository) PinProfilePost(ctx context.Context, postID, userID uuid.UUID, maxPins int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize pinning per user so the limit holds under concurrent requests
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('profile_pins:' || $1::text))`, userID); err != nil {
+		return fmt.Errorf("failed to lock profile pins: %w", err)
+	}
+
+	var pinned int
+	countQuery := `SELECT COUNT(*) FROM posts WHERE user_id = $1 AND profile_pinned_at IS NOT NULL AND is_deleted = false AND id <> $2`
+	if err := tx.QueryRow(ctx, countQuery, userID, postID).Scan(&pinned); err != nil {
+		return fmt.Errorf("failed to count pinned posts: %w", err)
+	}
+	if pinned >= maxPins {
+		return fmt.Errorf("too many pinned posts")
+	}
+
+	query := `
+		UPDATE posts SET profile_pinned_at = COALESCE(profile_pinned_at, NOW())
+		WHERE id = $1 AND user_id = $2 AND status = 'published' AND is_deleted = false AND is_hidden = false
+	`
+	result, err := tx.Exec(ctx, query, postID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to pin post: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit pin: %w", err)
+	}
+
+	return nil
+}
+
+// UnpinProfilePost unpins a post from its author's profile
+func (r *Repository) UnpinProfilePost(ctx context.Context, postID, userID uuid.UUID) error {
+	query := `UPDATE posts SET profile_pinned_at = NULL WHERE id = $1 AND user_id = $2 AND profile_pinned_at IS NOT NULL`
+
+	result, err := r.db.Exec(ctx, query, postID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unpin post: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not pinned")
+	}
+
+	return nil
+}
+
+// GetNearbyPosts retrieves published posts tagged within a radius of a point that
+// the current user can see, newest first or closest first
+func (r *Repository) GetNearbyPosts(ctx context.Context, near *geo.Query, byDistance bool, limit, offset int, currentUserID uuid.UUID) ([]models.Post, error) {
+	order := "p.created_at DESC"
+	if byDistance {
+		order = "distance ASC, p.created_at DESC"
+	}
+
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $1) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $1 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted,
+		       ` + near.Distance("p", 4) + ` as distance
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE ` + near.Where("p", 4) + `
+		  AND p.is_deleted = false AND p.is_hidden = false
+		  AND p.status = 'published'
+		  AND (p.is_held = false OR p.user_id = $1)
+		  AND (
+		      p.user_id = $1
+		      OR p.visibility = 'public'
+		      OR (p.visibility = 'followers' AND EXISTS(
+		          SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.following_id = p.user_id
+		      ))
+		  )
+		ORDER BY ` + order + `
+		LIMIT $2 OFFSET $3
+	`
+
+	args := append([]interface{}{currentUserID, limit, offset}, near.Args()...)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nearby posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		var myReaction *string
+		var isReposted bool
+		var distance float64
+
+		post, err := scanPost(rows, &myReaction, &isReposted, &distance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+
+		post.MyReaction = myReaction
+		post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+		post.IsReposted = isReposted
+		distance = math.Round(distance*100) / 100
+		post.DistanceKm = &distance
+		posts = append(posts, *post)
+	}
+
+	return posts, rows.Err()
+}
+
+// GetEventPosts retrieves the discussion thread of an event: pinned posts first,
+// then the rest newest first, limited to posts the current user can see. Each
+// post carries its author's RSVP to the event.
+func (r *Repository) GetEventPosts(ctx context.Context, eventID uuid.UUID, limit, offset int, currentUserID uuid.UUID) ([]models.Post, error) {
+	query := `
+		SELECT` + postColumns + `,
+		       (SELECT pr.reaction FROM post_reactions pr WHERE pr.post_id = p.id AND pr.user_id = $1) as my_reaction,
+		       EXISTS(
+		           SELECT 1 FROM posts rp
+		           WHERE rp.repost_of_id = p.id AND rp.user_id = $1 AND rp.content = '' AND rp.is_deleted = false
+		       ) as is_reposted,
+		       er.status as author_rsvp
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		LEFT JOIN event_rsvps er ON er.event_id = p.event_id AND er.user_id = p.user_id
+		WHERE p.event_id = $2
+		  AND p.is_deleted = false AND p.is_hidden = false
+		  AND p.status = 'published'
+		  AND (p.is_held = false OR p.user_id = $1)
+		  AND (
+		      p.user_id = $1
+		      OR p.visibility = 'public'
+		      OR (p.visibility = 'followers' AND EXISTS(
+		          SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.following_id = p.user_id
+		      ))
+		  )
+		ORDER BY p.event_pinned_at DESC NULLS LAST, p.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, currentUserID, eventID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		var myReaction, authorRSVP *string
+		var isReposted bool
+
+		post, err := scanPost(rows, &myReaction, &isReposted, &authorRSVP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+
+		post.MyReaction = myReaction
+		post.IsLiked = myReaction != nil && *myReaction == models.ReactionLike
+		post.IsReposted = isReposted
+		post.AuthorRSVP = authorRSVP
+		posts = append(posts, *post)
+	}
+
+	return posts, rows.Err()
+}
+
+// GetEventOrganizer returns the user who created an event, or nil for imported
+// events, which have no organizer on the platform
+func (r *Repository) GetEventOrganizer(ctx context.Context, eventID uuid.UUID) (*uuid.UUID, error) {
+	var organizerID *uuid.UUID
+	query := `SELECT created_by FROM events WHERE id = $1 AND is_deleted = false AND is_hidden = false`
+
+	err := r.db.QueryRow(ctx, query, eventID).Scan(&organizerID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return organizerID, nil
+}
+
+// PinEventPost pins a post of an event's thread, keeping at most maxPins pinned posts
+func (r *Repository) PinEventPost(ctx context.Context, eventID, postID uuid.UUID, maxPins int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize pinning per event so the limit holds under concurrent requests
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('event_pins:' || $1::text))`, eventID); err != nil {
+		return fmt.Errorf("failed to lock event pins: %w", err)
+	}
+
+	var pinned int
+	countQuery := `SELECT COUNT(*) FROM posts WHERE event_id = $1 AND event_pinned_at IS NOT NULL AND is_deleted = false AND id <> $2`
+	if err := tx.QueryRow(ctx, countQuery, eventID, postID).Scan(&pinned); err != nil {
+		return fmt.Errorf("failed to count pinned posts: %w", err)
+	}
+	if pinned >= maxPins {
+		return fmt.Errorf("too many pinned posts")
+	}
+
+	query := `
+		UPDATE posts SET event_pinned_at = COALESCE(event_pinned_at, NOW())
+		WHERE id = $1 AND event_id = $2 AND status = 'published' AND is_deleted = false AND is_hidden = false
+	`
+	result, err := tx.Exec(ctx, query, postID, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to pin post: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit pin: %w", err)
+	}
+
+	return nil
+}
+
+// UnpinEventPost unpins a post of an event's thread
+func (r *Repository) UnpinEventPost(ctx context.Context, eventID, postID uuid.UUID) error {
+	query := `UPDATE posts SET event_pinned_at = NULL WHERE id = $1 AND event_id = $2 AND event_pinned_at IS NOT NULL`
+
+	result, err := r.db.Exec(ctx, query, postID, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to unpin post: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not pinned")
+	}
+
+	return nil
+}
+
+// UpdatePost updates a post, keeping its previous content as a revision.
+// Drafts and scheduled posts are edited in place, without history.
+func (r *Repository) UpdatePost(ctx context.Context, postID, editorID uuid.UUID, content string, linkURL *string, held bool, heldReasons []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revisionQuery := `
+		INSERT INTO post_revisions (post_id, content, edited_by)
+		SELECT id, content, $2 FROM posts WHERE id = $1 AND is_deleted = false AND status = 'published'
+	`
+	if _, err := tx.Exec(ctx, revisionQuery, postID, editorID); err != nil {
+		return fmt.Errorf("failed to save post revision: %w", err)
+	}
+
+	query := `
+		UPDATE posts
+		SET content = $1, link_url = $2, updated_at = NOW(),
+		    edited_at = CASE WHEN status = 'published' THEN NOW() ELSE edited_at END,
+		    is_held = is_held OR $4,
+		    held_reasons = CASE WHEN $4 THEN $5 ELSE held_reasons END
+		WHERE id = $3 AND is_deleted = false
+	`
+	result, err := tx.Exec(ctx, query, content, linkURL, postID, held, heldReasons)
+	if err != nil {
+		return fmt.Errorf("failed to update post: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found or already deleted")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit post update: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnpublishedPosts retrieves a user's scheduled posts, soonest first, followed
+// by their drafts, most recently edited first
+func (r *Repository) GetUnpublishedPosts(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Post, error) {
+	query := `
+		SELECT` + postColumns + `
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.user_id = $1 AND p.status <> 'published' AND p.is_deleted = false
+		ORDER BY p.scheduled_at ASC NULLS LAST, p.updated_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drafts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, *post)
+	}
+
+	return posts, rows.Err()
+}
+
+// SetPostSchedule turns an unpublished post into a draft (nil scheduledAt) or
+// schedules it for the given time
+func (r *Repository) SetPostSchedule(ctx context.Context, postID uuid.UUID, scheduledAt *time.Time) error {
+	status := models.PostStatusDraft
+	if scheduledAt != nil {
+		status = models.PostStatusScheduled
+	}
+
+	query := `
+		UPDATE posts SET status = $1, scheduled_at = $2, updated_at = NOW()
+		WHERE id = $3 AND status <> 'published' AND is_deleted = false
+	`
+	result, err := r.db.Exec(ctx, query, status, scheduledAt, postID)
+	if err != nil {
+		return fmt.Errorf("failed to schedule post: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post already published")
+	}
+
+	return nil
+}
+
+// PublishPost publishes a draft or scheduled post right away. The post takes
+// the publication time as its creation time so it shows up as new in feeds.
+func (r *Repository) PublishPost(ctx context.Context, postID uuid.UUID) error {
+	query := `
+		UPDATE posts
+		SET status = 'published', scheduled_at = NULL, created_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status <> 'published' AND is_deleted = false
+	`
+	result, err := r.db.Exec(ctx, query, postID)
+	if err != nil {
+		return fmt.Errorf("failed to publish post: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post already published")
+	}
+
+	return nil
+}
+
+// PublishDuePosts publishes every scheduled post whose time has come and returns
+// their IDs. Only one instance publishes at a time, and the status check makes
+// publishing a post idempotent, so a post is never published twice.
+func (r *Repository) PublishDuePosts(ctx context.Context) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('publish_scheduled_posts'))`).Scan(&locked); err != nil {
+		return nil, fmt.Errorf("failed to lock scheduled posts: %w", err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	// Posts keep their scheduled time as creation time, even when published late
+	query := `
+		UPDATE posts
+		SET status = 'published', created_at = scheduled_at, updated_at = NOW()
+		WHERE status = 'scheduled' AND scheduled_at <= NOW() AND is_deleted = false
+		RETURNING id
+	`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish scheduled posts: %w", err)
+	}
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan published post: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to publish scheduled posts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit scheduled posts: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetPostRevisions retrieves the previous versions of a post, newest first
+func (r *Repository) GetPostRevisions(ctx context.Context, postID uuid.UUID) ([]models.Revision, error) {
+	query := `
+		SELECT id, content, edited_by, created_at
+		FROM post_revisions
+		WHERE post_id = $1
+		ORDER BY created_at DESC
+	`
 
+	return r.queryRevisions(ctx, query, postID)
+}
+
+// DeletePost moves a post to its author's trash, soft deleting its plain reposts
+// along with it. Quote posts are kept since they carry their author's own commentary.
+func (r *Repository) DeletePost(ctx context.Context, postID, deletedBy uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("post not found")
-		}
-		return nil, fmt.Errorf("failed to get post: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	post.Author = &author
-	return &post, nil
+	query := `UPDATE posts SET is_deleted = true, deleted_by = $2 WHERE id = $1 AND is_deleted = false`
+	result, err := tx.Exec(ctx, query, postID, deletedBy)
+	if err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("post not found or already deleted")
+	}
+
+	// The reposts are marked as deleted by the original's author, keeping them
+	// out of their own authors' trash, so they come back only with the original
+	repostsQuery := `
+		UPDATE posts SET is_deleted = true, deleted_by = $2
+		WHERE repost_of_id = $1 AND content = '' AND is_deleted = false
+	`
+	if _, err := tx.Exec(ctx, repostsQuery, postID, deletedBy); err != nil {
+		return fmt.Errorf("failed to delete reposts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit post deletion: %w", err)
+	}
+
+	return nil
 }
 
-// GetFeed retrieves paginated posts for feed
-func (r *Repository) GetFeed(ctx context.Context, limit, offset int, currentUserID uuid.UUID) ([]models.Post, error) {
+// GetTrashedPosts retrieves the posts a user deleted within the retention
+// period, most recently deleted first
+func (r *Repository) GetTrashedPosts(ctx context.Context, userID uuid.UUID, retention time.Duration, limit, offset int) ([]models.Post, error) {
 	query := `
-		SELECT p.id, p.user_id, p.content, p.likes_count, p.comments_count, 
-		       p.created_at, p.updated_at,
-		       u.id, u.username, u.first_name, u.last_name, u.avatar_url,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $1) as is_liked
+		SELECT` + postColumns + `
 		FROM posts p
 		JOIN users u ON p.user_id = u.id
-		WHERE p.is_deleted = false
-		ORDER BY p.created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE p.user_id = $1 AND p.deleted_by = $1 AND p.is_deleted = true
+		  AND p.deleted_at > NOW() - $2 * INTERVAL '1 second'
+		ORDER BY p.deleted_at DESC
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.Query(ctx, query, currentUserID, limit, offset)
+	rows, err := r.db.Query(ctx, query, userID, retention.Seconds(), limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feed: %w", err)
+		return nil, fmt.Errorf("failed to get trash: %w", err)
 	}
 	defer rows.Close()
 
-	var posts []models.Post
-
+	posts := []models.Post{}
 	for rows.Next() {
-		var post models.Post
-		var author models.UserResponse
-
-		err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Content,
-			&post.LikesCount,
-			&post.CommentsCount,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-			&author.ID,
-			&author.Username,
-			&author.FirstName,
-			&author.LastName,
-			&author.AvatarURL,
-			&post.IsLiked,
-		)
-
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan post: %w", err)
 		}
-
-		post.Author = &author
-		posts = append(posts, post)
+		posts = append(posts, *post)
 	}
 
-	return posts, nil
+	return posts, rows.Err()
 }
 
-// UpdatePost updates a post
-func (r *Repository) UpdatePost(ctx context.Context, postID uuid.UUID, content string) error {
-	query := `UPDATE posts SET content = $1, updated_at = NOW() WHERE id = $2 AND is_deleted = false`
-	result, err := r.db.Exec(ctx, query, content, postID)
+// RestorePost takes a post out of its author's trash, along with the plain
+// reposts that were deleted with it
+func (r *Repository) RestorePost(ctx context.Context, postID, userID uuid.UUID, retention time.Duration) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to update post: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("post not found or already deleted")
+	var deletedAt time.Time
+	var repostOfID *uuid.UUID
+	var content string
+	var restorable bool
+	query := `
+		SELECT deleted_at, repost_of_id, content, deleted_at > NOW() - $3 * INTERVAL '1 second'
+		FROM posts
+		WHERE id = $1 AND user_id = $2 AND deleted_by = $2 AND is_deleted = true
+		FOR UPDATE
+	`
+	err = tx.QueryRow(ctx, query, postID, userID, retention.Seconds()).Scan(&deletedAt, &repostOfID, &content, &restorable)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("post not found")
+		}
+		return fmt.Errorf("failed to get trashed post: %w", err)
+	}
+
+	if !restorable {
+		return fmt.Errorf("restore period expired")
+	}
+
+	// A plain repost can only come back while the original is still there and
+	// the user has not reposted it again
+	if repostOfID != nil && content == "" {
+		var available bool
+		availableQuery := `
+			SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND is_deleted = false)
+			   AND NOT EXISTS(
+			       SELECT 1 FROM posts
+			       WHERE repost_of_id = $1 AND user_id = $2 AND content = '' AND is_deleted = false
+			   )
+		`
+		if err := tx.QueryRow(ctx, availableQuery, *repostOfID, userID).Scan(&available); err != nil {
+			return fmt.Errorf("failed to check original post: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("original post unavailable")
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE posts SET is_deleted = false WHERE id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to restore post: %w", err)
+	}
+
+	repostsQuery := `
+		UPDATE posts SET is_deleted = false
+		WHERE repost_of_id = $1 AND content = '' AND is_deleted = true
+		  AND deleted_by = $2 AND deleted_at = $3
+	`
+	if _, err := tx.Exec(ctx, repostsQuery, postID, userID, deletedAt); err != nil {
+		return fmt.Errorf("failed to restore reposts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit post restore: %w", err)
 	}
 
 	return nil
 }
 
-// DeletePost soft deletes a post
-func (r *Repository) DeletePost(ctx context.Context, postID uuid.UUID) error {
-	query := `UPDATE posts SET is_deleted = true WHERE id = $1 AND is_deleted = false`
-	result, err := r.db.Exec(ctx, query, postID)
+// PurgeDeletedPosts permanently deletes up to limit posts deleted longer than
+// retention ago, with the plain reposts of those posts. Comments, likes,
+// reactions, polls, revisions and bookmarks go with them through their foreign
+// keys. Only one instance purges at a time.
+func (r *Repository) PurgeDeletedPosts(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete post: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("post not found or already deleted")
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('purge_deleted_posts'))`).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock deleted posts: %w", err)
+	}
+	if !locked {
+		return 0, nil
 	}
 
-	return nil
+	query := `
+		WITH expired AS (
+			SELECT id FROM posts
+			WHERE is_deleted = true AND deleted_at < NOW() - $1 * INTERVAL '1 second'
+			ORDER BY deleted_at ASC
+			LIMIT $2
+		), reposts AS (
+			DELETE FROM posts
+			WHERE repost_of_id IN (SELECT id FROM expired) AND content = ''
+			  AND id NOT IN (SELECT id FROM expired)
+		)
+		DELETE FROM posts WHERE id IN (SELECT id FROM expired)
+	`
+	result, err := tx.Exec(ctx, query, retention.Seconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted posts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit purge: %w", err)
+	}
+
+	return result.RowsAffected(), nil
 }
 
-// LikePost adds a like to a post
-func (r *Repository) LikePost(ctx context.Context, postID, userID uuid.UUID) error {
+// HasReposted checks if user has a live plain repost of the post
+func (r *Repository) HasReposted(ctx context.Context, postID, userID uuid.UUID) (bool, error) {
+	var exists bool
 	query := `
-		INSERT INTO post_likes (post_id, user_id)
-		VALUES ($1, $2)
-		ON CONFLICT (post_id, user_id) DO NOTHING
+		SELECT EXISTS(
+			SELECT 1 FROM posts
+			WHERE repost_of_id = $1 AND user_id = $2 AND content = '' AND is_deleted = false
+		)
+	`
+	err := r.db.QueryRow(ctx, query, postID, userID).Scan(&exists)
+	return exists, err
+}
+
+// DeleteRepost soft deletes the user's plain repost of a post
+func (r *Repository) DeleteRepost(ctx context.Context, postID, userID uuid.UUID) error {
+	query := `
+		UPDATE posts SET is_deleted = true, deleted_by = $2
+		WHERE repost_of_id = $1 AND user_id = $2 AND content = '' AND is_deleted = false
 	`
 
 	result, err := r.db.Exec(ctx, query, postID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to like post: %w", err)
+		return fmt.Errorf("failed to delete repost: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("post already liked")
+		return fmt.Errorf("post not reposted")
 	}
 
 	return nil
 }
 
-// UnlikePost removes a like from a post
-func (r *Repository) UnlikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	query := `DELETE FROM post_likes WHERE post_id = $1 AND user_id = $2`
+// SetPostReaction sets the user's reaction on a post, replacing any previous one.
+// It reports false when the user already had that same reaction.
+func (r *Repository) SetPostReaction(ctx context.Context, postID, userID uuid.UUID, reaction string) (bool, error) {
+	query := `
+		INSERT INTO post_reactions (post_id, user_id, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (post_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction
+		WHERE post_reactions.reaction IS DISTINCT FROM EXCLUDED.reaction
+	`
 
-	result, err := r.db.Exec(ctx, query, postID, userID)
+	result, err := r.db.Exec(ctx, query, postID, userID, reaction)
 	if err != nil {
-		return fmt.Errorf("failed to unlike post: %w", err)
+		return false, fmt.Errorf("failed to react to post: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("post not liked")
+	return result.RowsAffected() > 0, nil
+}
+
+// RemovePostReaction removes the user's reaction from a post. When reaction is
+// not empty, only that reaction is removed. It reports false when nothing was removed.
+func (r *Repository) RemovePostReaction(ctx context.Context, postID, userID uuid.UUID, reaction string) (bool, error) {
+	query := `DELETE FROM post_reactions WHERE post_id = $1 AND user_id = $2 AND ($3 = '' OR reaction = $3)`
+
+	result, err := r.db.Exec(ctx, query, postID, userID, reaction)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove post reaction: %w", err)
 	}
 
-	return nil
+	return result.RowsAffected() > 0, nil
 }
 
 // CreateComment creates a new comment
 func (r *Repository) CreateComment(ctx context.Context, comment *models.Comment) error {
 	query := `
-		INSERT INTO comments (post_id, user_id, content)
-		VALUES ($1, $2, $3)
-		RETURNING id, likes_count, created_at, updated_at, is_deleted
+		WITH inserted AS (
+			INSERT INTO comments (post_id, user_id, content, is_held, held_reasons)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, user_id, likes_count, reaction_counts, created_at, updated_at, is_deleted
+		)
+		SELECT i.id, i.likes_count, i.reaction_counts, i.created_at, i.updated_at, i.is_deleted,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM inserted i
+		JOIN users u ON u.id = i.user_id
 	`
 
-	err := r.db.QueryRow(ctx, query, comment.PostID, comment.UserID, comment.Content).Scan(
+	var author models.UserResponse
+	err := r.db.QueryRow(ctx, query,
+		comment.PostID, comment.UserID, comment.Content, comment.IsHeld, comment.HeldReasons,
+	).Scan(
 		&comment.ID,
 		&comment.LikesCount,
+		&comment.ReactionCounts,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
 		&comment.IsDeleted,
+		&author.ID,
+		&author.Username,
+		&author.FirstName,
+		&author.LastName,
+		&author.AvatarURL,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
 
+	comment.Author = &author
 	return nil
 }
 
 // GetCommentsByPostID retrieves all comments for a post
 func (r *Repository) GetCommentsByPostID(ctx context.Context, postID uuid.UUID, currentUserID uuid.UUID) ([]models.Comment, error) {
 	query := `
-		SELECT c.id, c.post_id, c.user_id, c.content, c.likes_count, 
-		       c.created_at, c.updated_at,
+		SELECT c.id, c.post_id, c.user_id, c.content, c.likes_count, c.reaction_counts,
+		       c.created_at, c.updated_at, c.edited_at, c.is_held,
 		       u.id, u.username, u.first_name, u.last_name, u.avatar_url,
-		       EXISTS(SELECT 1 FROM comment_likes cl WHERE cl.comment_id = c.id AND cl.user_id = $2) as is_liked
+		       (SELECT cr.reaction FROM comment_reactions cr WHERE cr.comment_id = c.id AND cr.user_id = $2) as my_reaction
 		FROM comments c
 		JOIN users u ON c.user_id = u.id
-		WHERE c.post_id = $1 AND c.is_deleted = false
+		WHERE c.post_id = $1 AND c.is_deleted = false AND c.is_hidden = false
+		  AND (c.is_held = false OR c.user_id = $2)
 		ORDER BY c.created_at ASC
 	`
 
@@ -256,14 +1072,17 @@ func (r *Repository) GetCommentsByPostID(ctx context.Context, postID uuid.UUID,
 			&comment.UserID,
 			&comment.Content,
 			&comment.LikesCount,
+			&comment.ReactionCounts,
 			&comment.CreatedAt,
 			&comment.UpdatedAt,
+			&comment.EditedAt,
+			&comment.IsHeld,
 			&author.ID,
 			&author.Username,
 			&author.FirstName,
 			&author.LastName,
 			&author.AvatarURL,
-			&comment.IsLiked,
+			&comment.MyReaction,
 		)
 
 		if err != nil {
@@ -271,12 +1090,57 @@ func (r *Repository) GetCommentsByPostID(ctx context.Context, postID uuid.UUID,
 		}
 
 		comment.Author = &author
+		comment.IsEdited = comment.EditedAt != nil
+		comment.IsLiked = comment.MyReaction != nil && *comment.MyReaction == models.ReactionLike
 		comments = append(comments, comment)
 	}
 
 	return comments, nil
 }
 
+// GetComment retrieves a visible comment by ID
+func (r *Repository) GetComment(ctx context.Context, commentID uuid.UUID) (*models.Comment, error) {
+	query := `
+		SELECT c.id, c.post_id, c.user_id, c.content, c.likes_count, c.reaction_counts,
+		       c.created_at, c.updated_at, c.edited_at, c.is_held,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM comments c
+		JOIN users u ON c.user_id = u.id
+		WHERE c.id = $1 AND c.is_deleted = false AND c.is_hidden = false
+	`
+
+	var comment models.Comment
+	var author models.UserResponse
+
+	err := r.db.QueryRow(ctx, query, commentID).Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.UserID,
+		&comment.Content,
+		&comment.LikesCount,
+		&comment.ReactionCounts,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+		&comment.IsHeld,
+		&author.ID,
+		&author.Username,
+		&author.FirstName,
+		&author.LastName,
+		&author.AvatarURL,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	comment.Author = &author
+	comment.IsEdited = comment.EditedAt != nil
+	return &comment, nil
+}
+
 // DeleteComment soft deletes a comment
 func (r *Repository) DeleteComment(ctx context.Context, commentID uuid.UUID) error {
 	query := `UPDATE comments SET is_deleted = true WHERE id = $1 AND is_deleted = false`
@@ -292,6 +1156,139 @@ func (r *Repository) DeleteComment(ctx context.Context, commentID uuid.UUID) err
 	return nil
 }
 
+// UpdateComment updates a comment, keeping its previous content as a revision
+func (r *Repository) UpdateComment(ctx context.Context, commentID, editorID uuid.UUID, content string, held bool, heldReasons []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revisionQuery := `
+		INSERT INTO comment_revisions (comment_id, content, edited_by)
+		SELECT id, content, $2 FROM comments WHERE id = $1 AND is_deleted = false
+	`
+	result, err := tx.Exec(ctx, revisionQuery, commentID, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to save comment revision: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("comment not found or already deleted")
+	}
+
+	query := `
+		UPDATE comments
+		SET content = $1, edited_at = NOW(), updated_at = NOW(),
+		    is_held = is_held OR $3,
+		    held_reasons = CASE WHEN $3 THEN $4 ELSE held_reasons END
+		WHERE id = $2 AND is_deleted = false
+	`
+	if _, err := tx.Exec(ctx, query, content, commentID, held, heldReasons); err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit comment update: %w", err)
+	}
+
+	return nil
+}
+
+// GetCommentRevisions retrieves the previous versions of a comment, newest first
+func (r *Repository) GetCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]models.Revision, error) {
+	query := `
+		SELECT id, content, edited_by, created_at
+		FROM comment_revisions
+		WHERE comment_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.queryRevisions(ctx, query, commentID)
+}
+
+// queryRevisions runs a revision query and scans the results
+func (r *Repository) queryRevisions(ctx context.Context, query string, args ...interface{}) ([]models.Revision, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.Revision{}
+	for rows.Next() {
+		var revision models.Revision
+		if err := rows.Scan(&revision.ID, &revision.Content, &revision.EditedBy, &revision.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// SetCommentReaction sets the user's reaction on a comment, replacing any previous one
+func (r *Repository) SetCommentReaction(ctx context.Context, commentID, userID uuid.UUID, reaction string) (bool, error) {
+	query := `
+		INSERT INTO comment_reactions (comment_id, user_id, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (comment_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction
+		WHERE comment_reactions.reaction IS DISTINCT FROM EXCLUDED.reaction
+	`
+
+	result, err := r.db.Exec(ctx, query, commentID, userID, reaction)
+	if err != nil {
+		return false, fmt.Errorf("failed to react to comment: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// RemoveCommentReaction removes the user's reaction from a comment. When reaction
+// is not empty, only that reaction is removed. Removing a missing reaction is a no-op.
+func (r *Repository) RemoveCommentReaction(ctx context.Context, commentID, userID uuid.UUID, reaction string) error {
+	query := `DELETE FROM comment_reactions WHERE comment_id = $1 AND user_id = $2 AND ($3 = '' OR reaction = $3)`
+
+	if _, err := r.db.Exec(ctx, query, commentID, userID, reaction); err != nil {
+		return fmt.Errorf("failed to remove comment reaction: %w", err)
+	}
+
+	return nil
+}
+
+// CommentExists checks if a comment exists and is not deleted
+func (r *Repository) CommentExists(ctx context.Context, commentID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM comments WHERE id = $1 AND is_deleted = false AND is_hidden = false AND is_held = false)`
+	err := r.db.QueryRow(ctx, query, commentID).Scan(&exists)
+	return exists, err
+}
+
+// GetCommenterIDs retrieves the distinct authors of the visible comments on a post
+func (r *Repository) GetCommenterIDs(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT user_id FROM comments
+		WHERE post_id = $1 AND is_deleted = false AND is_hidden = false AND is_held = false
+	`
+
+	rows, err := r.db.Query(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commenters: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan commenter: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
 // CheckPostOwnership checks if user owns the post
 func (r *Repository) CheckPostOwnership(ctx context.Context, postID, userID uuid.UUID) (bool, error) {
 	var exists bool
@@ -307,3 +1304,133 @@ func (r *Repository) CheckCommentOwnership(ctx context.Context, commentID, userI
 	err := r.db.QueryRow(ctx, query, commentID, userID).Scan(&exists)
 	return exists, err
 }
+
+// IsFollowing checks if follower follows the given user
+func (r *Repository) IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = $2)`
+	err := r.db.QueryRow(ctx, query, followerID, followingID).Scan(&exists)
+	return exists, err
+}
+
+// IsModerator checks if user has a moderator or admin role
+func (r *Repository) IsModerator(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var isModerator bool
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND role IN ('moderator', 'admin'))`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&isModerator)
+	return isModerator, err
+}
+
+// GetPollsByPostIDs retrieves the polls of the given posts with their tallies
+// and the options the current user voted for, keyed by post ID
+func (r *Repository) GetPollsByPostIDs(ctx context.Context, postIDs []uuid.UUID, currentUserID uuid.UUID) (map[uuid.UUID]*models.Poll, error) {
+	polls := make(map[uuid.UUID]*models.Poll)
+	if len(postIDs) == 0 {
+		return polls, nil
+	}
+
+	query := `
+		SELECT id, post_id, allows_multiple, closes_at, voters_count, created_at
+		FROM polls
+		WHERE post_id = ANY($1)
+	`
+
+	rows, err := r.db.Query(ctx, query, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[uuid.UUID]*models.Poll)
+	pollIDs := []uuid.UUID{}
+
+	for rows.Next() {
+		poll := &models.Poll{
+			Options:        []models.PollOption{},
+			VotedOptionIDs: []uuid.UUID{},
+		}
+		err := rows.Scan(&poll.ID, &poll.PostID, &poll.AllowsMultiple, &poll.ClosesAt, &poll.VotersCount, &poll.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan poll: %w", err)
+		}
+		polls[poll.PostID] = poll
+		byID[poll.ID] = poll
+		pollIDs = append(pollIDs, poll.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(pollIDs) == 0 {
+		return polls, nil
+	}
+
+	optionsQuery := `
+		SELECT o.id, o.poll_id, o.position, o.text, o.votes_count,
+		       EXISTS(SELECT 1 FROM poll_votes pv WHERE pv.option_id = o.id AND pv.user_id = $2) as is_voted
+		FROM poll_options o
+		WHERE o.poll_id = ANY($1)
+		ORDER BY o.poll_id, o.position
+	`
+
+	optionRows, err := r.db.Query(ctx, optionsQuery, pollIDs, currentUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll options: %w", err)
+	}
+	defer optionRows.Close()
+
+	for optionRows.Next() {
+		var option models.PollOption
+		var isVoted bool
+		err := optionRows.Scan(&option.ID, &option.PollID, &option.Position, &option.Text, &option.VotesCount, &isVoted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan poll option: %w", err)
+		}
+
+		poll := byID[option.PollID]
+		poll.Options = append(poll.Options, option)
+		if isVoted {
+			poll.VotedOptionIDs = append(poll.VotedOptionIDs, option.ID)
+		}
+	}
+
+	return polls, optionRows.Err()
+}
+
+// VotePoll records a user's ballot. Each user can vote only once per poll.
+func (r *Repository) VotePoll(ctx context.Context, pollID, userID uuid.UUID, optionIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	voterQuery := `
+		INSERT INTO poll_voters (poll_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (poll_id, user_id) DO NOTHING
+	`
+
+	result, err := tx.Exec(ctx, voterQuery, pollID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record voter: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("already voted")
+	}
+
+	voteQuery := `INSERT INTO poll_votes (poll_id, option_id, user_id) VALUES ($1, $2, $3)`
+	for _, optionID := range optionIDs {
+		if _, err := tx.Exec(ctx, voteQuery, pollID, optionID, userID); err != nil {
+			return fmt.Errorf("failed to record vote: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit vote: %w", err)
+	}
+
+	return nil
+}
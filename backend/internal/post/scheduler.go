@@ -0,0 +1,69 @@
+package post
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+)
+
+// Scheduler publishes scheduled posts once they are due. Schedules live in the
+// database, so posts due while the server was down are published on startup.
+type Scheduler struct {
+	repo          *Repository
+	notifications *notification.Service
+	interval      time.Duration
+}
+
+func NewScheduler(repo *Repository, cfg *config.Config, notifications *notification.Service) *Scheduler {
+	return &Scheduler{
+		repo:          repo,
+		notifications: notifications,
+		interval:      cfg.Scheduling.Interval,
+	}
+}
+
+// Run publishes due posts right away and then on every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PublishDue(ctx); err != nil {
+			log.Printf("Scheduled posts error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes every scheduled post whose time has come
+func (s *Scheduler) PublishDue(ctx context.Context) error {
+	ids, err := s.repo.PublishDuePosts(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) > 0 {
+		log.Printf("Published %d scheduled posts", len(ids))
+	}
+
+	// Mentions are only notified once the post is out
+	for _, id := range ids {
+		post, err := s.repo.GetPostByID(ctx, id, uuid.Nil)
+		if err != nil {
+			log.Printf("Scheduled post %s notification error: %v", id, err)
+			continue
+		}
+		notifyMentions(ctx, s.repo, s.notifications, post)
+	}
+
+	return nil
+}
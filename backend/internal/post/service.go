@@ -3,32 +3,135 @@ package post
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/Aolakije/City-Buzz/internal/contentfilter"
+	"github.com/Aolakije/City-Buzz/internal/event"
+	"github.com/Aolakije/City-Buzz/internal/geo"
+	"github.com/Aolakije/City-Buzz/internal/linkpreview"
 	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/Aolakije/City-Buzz/internal/realtime"
+	"github.com/Aolakije/City-Buzz/internal/user"
+	"github.com/Aolakije/City-Buzz/pkg/config"
 	"github.com/google/uuid"
 )
 
 type Service struct {
-	repo *Repository
+	repo          *Repository
+	filter        *contentfilter.Pipeline
+	previews      *linkpreview.Service
+	events        event.Service
+	users         *user.Service
+	notifications *notification.Service
+	hub           *realtime.Hub
+	reactionTypes []string
+	retention     time.Duration // How long deleted posts stay in the trash
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, cfg *config.Config, filter *contentfilter.Pipeline, previews *linkpreview.Service, events event.Service, users *user.Service, notifications *notification.Service, hub *realtime.Hub) *Service {
+	return &Service{
+		repo:          repo,
+		filter:        filter,
+		previews:      previews,
+		events:        events,
+		users:         users,
+		notifications: notifications,
+		hub:           hub,
+		reactionTypes: cfg.Reactions.Types,
+		retention:     cfg.Trash.Retention,
+	}
 }
 
-// CreatePost creates a new post
+// maxScheduleAhead is how far in the future a post can be scheduled
+const maxScheduleAhead = 365 * 24 * time.Hour
+
+// maxEventPins is how many posts an organizer can pin in an event's thread
+const maxEventPins = 3
+
+// maxProfilePins is how many posts a user can pin to their profile
+const maxProfilePins = 3
+
+// CreatePost creates a new post, published right away unless it is saved as a
+// draft or scheduled for later
 func (s *Service) CreatePost(ctx context.Context, userID uuid.UUID, req *models.CreatePostRequest) (*models.Post, error) {
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+
+	if req.Draft && req.ScheduledAt != nil {
+		return nil, fmt.Errorf("a post cannot be both a draft and scheduled")
+	}
+
 	post := &models.Post{
-		UserID:  userID,
-		Content: req.Content,
+		UserID:     userID,
+		Content:    req.Content,
+		Visibility: visibility,
+		Status:     models.PostStatusPublished,
+		City:       req.City,
+		LinkURL:    linkpreview.ExtractURL(req.Content),
+		EventID:    req.EventID,
+	}
+
+	if req.Location != nil {
+		latitude, longitude := req.Location.Latitude, req.Location.Longitude
+		post.Latitude = &latitude
+		post.Longitude = &longitude
+		if req.Location.PlaceName != nil {
+			if placeName := strings.TrimSpace(*req.Location.PlaceName); placeName != "" {
+				post.PlaceName = &placeName
+			}
+		}
+	}
+
+	// Events only known from OpenAgenda are imported so posts can reference them
+	if req.EventID != nil {
+		if err := s.events.EnsureEvent(ctx, *req.EventID); err != nil {
+			log.Printf("Post event %s error: %v", *req.EventID, err)
+			return nil, fmt.Errorf("event not found")
+		}
+	}
+
+	if req.Draft {
+		post.Status = models.PostStatusDraft
+	}
+	if req.ScheduledAt != nil {
+		scheduledAt, err := checkScheduleTime(*req.ScheduledAt)
+		if err != nil {
+			return nil, err
+		}
+		post.Status = models.PostStatusScheduled
+		post.ScheduledAt = &scheduledAt
+	}
+
+	text := req.Content
+	if req.Poll != nil {
+		poll, err := newPoll(req.Poll)
+		if err != nil {
+			return nil, err
+		}
+		if post.ScheduledAt != nil && !poll.ClosesAt.After(*post.ScheduledAt) {
+			return nil, fmt.Errorf("poll must close after the scheduled time")
+		}
+		post.Poll = poll
+		text += "\n" + strings.Join(req.Poll.Options, "\n")
+	}
+
+	if err := s.screenPost(ctx, post, text); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.CreatePost(ctx, post); err != nil {
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
+	s.enqueuePreview(post.LinkURL)
+	notifyMentions(ctx, s.repo, s.notifications, post)
 
 	// Get post with author info
-	fullPost, err := s.repo.GetPostByID(ctx, post.ID)
+	fullPost, err := s.GetPostByID(ctx, post.ID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get created post: %w", err)
 	}
@@ -36,15 +139,101 @@ func (s *Service) CreatePost(ctx context.Context, userID uuid.UUID, req *models.
 	return fullPost, nil
 }
 
-// GetPostByID retrieves a post by ID
-func (s *Service) GetPostByID(ctx context.Context, postID uuid.UUID) (*models.Post, error) {
-	return s.repo.GetPostByID(ctx, postID)
+// GetPostByID retrieves a post by ID if the user is allowed to see it
+func (s *Service) GetPostByID(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	post, err := s.repo.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	canView, err := canViewPost(ctx, s.repo, post, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hidden posts are reported as missing so their existence isn't leaked
+	if !canView {
+		return nil, fmt.Errorf("post not found")
+	}
+
+	if err := s.enrichPosts(ctx, []*models.Post{post}, userID); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// GetVisiblePosts retrieves the posts among the given IDs that the user can see, keyed by ID
+func (s *Service) GetVisiblePosts(ctx context.Context, postIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID]*models.Post, error) {
+	posts, err := s.repo.GetPostsByIDs(ctx, postIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*models.Post, 0, len(posts))
+	for id, post := range posts {
+		canView, err := canViewPost(ctx, s.repo, post, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			delete(posts, id)
+			continue
+		}
+		visible = append(visible, post)
+	}
+
+	if err := s.enrichPosts(ctx, visible, userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
 }
 
 // GetFeed retrieves paginated feed
 func (s *Service) GetFeed(ctx context.Context, page, limit int, userID uuid.UUID) ([]models.Post, error) {
 	offset := (page - 1) * limit
-	return s.repo.GetFeed(ctx, limit, offset, userID)
+	posts, err := s.repo.GetFeed(ctx, limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// GetUserPosts retrieves the posts of the user with the given username that the
+// current user can see, pinned posts first
+func (s *Service) GetUserPosts(ctx context.Context, username string, page, limit int, userID uuid.UUID) ([]models.Post, error) {
+	authorID, err := s.users.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+	posts, err := s.repo.GetUserPosts(ctx, authorID, limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// PinProfilePost pins one of the user's posts to the top of their profile
+func (s *Service) PinProfilePost(ctx context.Context, postID, userID uuid.UUID) error {
+	return s.repo.PinProfilePost(ctx, postID, userID, maxProfilePins)
+}
+
+// UnpinProfilePost unpins one of the user's posts from their profile
+func (s *Service) UnpinProfilePost(ctx context.Context, postID, userID uuid.UUID) error {
+	return s.repo.UnpinProfilePost(ctx, postID, userID)
 }
 
 // UpdatePost updates a post
@@ -59,7 +248,244 @@ func (s *Service) UpdatePost(ctx context.Context, postID, userID uuid.UUID, req
 		return fmt.Errorf("unauthorized: you don't own this post")
 	}
 
-	return s.repo.UpdatePost(ctx, postID, req.Content)
+	post, err := s.repo.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+
+	if post.IsPlainRepost() {
+		return fmt.Errorf("plain reposts cannot be edited")
+	}
+
+	// Edits are screened like new posts; a flagged edit hides the post until
+	// a moderator approves it
+	post.IsHeld, post.HeldReasons = false, nil
+	if err := s.screenPost(ctx, post, req.Content); err != nil {
+		return err
+	}
+
+	linkURL := linkpreview.ExtractURL(req.Content)
+	if err := s.repo.UpdatePost(ctx, postID, userID, req.Content, linkURL, post.IsHeld, post.HeldReasons); err != nil {
+		return err
+	}
+	s.enqueuePreview(linkURL)
+
+	return nil
+}
+
+// GetNearbyPosts retrieves posts tagged within a radius of a point, newest first,
+// or closest first when sortBy is "distance"
+func (s *Service) GetNearbyPosts(ctx context.Context, latitude, longitude, radiusKm float64, sortBy string, userID uuid.UUID, page, limit int) ([]models.Post, error) {
+	near, err := geo.NewQuery(latitude, longitude, radiusKm)
+	if err != nil {
+		return nil, err
+	}
+
+	if sortBy != "" && sortBy != "recent" && sortBy != "distance" {
+		return nil, fmt.Errorf("invalid sort")
+	}
+
+	offset := (page - 1) * limit
+	posts, err := s.repo.GetNearbyPosts(ctx, near, sortBy == "distance", limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// GetEventPosts retrieves the discussion thread of an event
+func (s *Service) GetEventPosts(ctx context.Context, eventID, userID uuid.UUID, page, limit int) ([]models.Post, error) {
+	offset := (page - 1) * limit
+	posts, err := s.repo.GetEventPosts(ctx, eventID, limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// PinEventPost pins a post at the top of an event's thread
+func (s *Service) PinEventPost(ctx context.Context, eventID, postID, userID uuid.UUID) error {
+	if err := s.checkEventOrganizer(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.PinEventPost(ctx, eventID, postID, maxEventPins)
+}
+
+// UnpinEventPost unpins a post from an event's thread
+func (s *Service) UnpinEventPost(ctx context.Context, eventID, postID, userID uuid.UUID) error {
+	if err := s.checkEventOrganizer(ctx, eventID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.UnpinEventPost(ctx, eventID, postID)
+}
+
+// checkEventOrganizer ensures the user organizes the event or is a moderator
+func (s *Service) checkEventOrganizer(ctx context.Context, eventID, userID uuid.UUID) error {
+	organizerID, err := s.repo.GetEventOrganizer(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if organizerID != nil && *organizerID == userID {
+		return nil
+	}
+
+	isModerator, err := s.repo.IsModerator(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check role: %w", err)
+	}
+
+	if !isModerator {
+		return fmt.Errorf("unauthorized: only the event organizer can pin posts")
+	}
+
+	return nil
+}
+
+// GetDrafts retrieves the user's scheduled posts and drafts
+func (s *Service) GetDrafts(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.Post, error) {
+	offset := (page - 1) * limit
+	posts, err := s.repo.GetUnpublishedPosts(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// SchedulePost schedules one of the user's drafts, or reschedules a scheduled post
+func (s *Service) SchedulePost(ctx context.Context, postID, userID uuid.UUID, req *models.SchedulePostRequest) (*models.Post, error) {
+	post, err := s.getOwnUnpublishedPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledAt, err := checkScheduleTime(req.ScheduledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Poll != nil && !post.Poll.ClosesAt.After(scheduledAt) {
+		return nil, fmt.Errorf("poll must close after the scheduled time")
+	}
+
+	if err := s.repo.SetPostSchedule(ctx, postID, &scheduledAt); err != nil {
+		return nil, err
+	}
+
+	return s.GetPostByID(ctx, postID, userID)
+}
+
+// UnschedulePost turns one of the user's scheduled posts back into a draft
+func (s *Service) UnschedulePost(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	if _, err := s.getOwnUnpublishedPost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPostSchedule(ctx, postID, nil); err != nil {
+		return nil, err
+	}
+
+	return s.GetPostByID(ctx, postID, userID)
+}
+
+// PublishPost publishes one of the user's drafts or scheduled posts right away
+func (s *Service) PublishPost(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	if _, err := s.getOwnUnpublishedPost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PublishPost(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	post, err := s.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	notifyMentions(ctx, s.repo, s.notifications, post)
+
+	return post, nil
+}
+
+// getOwnUnpublishedPost loads a draft or scheduled post, checking the user wrote it
+func (s *Service) getOwnUnpublishedPost(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	post, err := s.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: you don't own this post")
+	}
+
+	if post.IsPublished() {
+		return nil, fmt.Errorf("post already published")
+	}
+
+	return post, nil
+}
+
+// checkScheduleTime makes sure a publication time is in the future, but not too far
+func checkScheduleTime(scheduledAt time.Time) (time.Time, error) {
+	now := time.Now()
+	if !scheduledAt.After(now) {
+		return time.Time{}, fmt.Errorf("scheduled time must be in the future")
+	}
+	if scheduledAt.After(now.Add(maxScheduleAhead)) {
+		return time.Time{}, fmt.Errorf("scheduled time is too far ahead")
+	}
+	return scheduledAt.UTC(), nil
+}
+
+// getPublishedPost retrieves a post the user can see and interact with;
+// drafts and scheduled posts are reported as missing
+func (s *Service) getPublishedPost(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	post, err := s.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !post.IsPublished() {
+		return nil, fmt.Errorf("post not found")
+	}
+
+	return post, nil
+}
+
+// GetPostRevisions retrieves a post's edit history, visible to its author and moderators
+func (s *Service) GetPostRevisions(ctx context.Context, postID, userID uuid.UUID) (*models.Post, []models.Revision, error) {
+	post, err := s.repo.GetPostByID(ctx, postID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkAuthorOrModerator(ctx, post.UserID == userID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	revisions, err := s.repo.GetPostRevisions(ctx, postID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get post revisions: %w", err)
+	}
+
+	return post, revisions, nil
 }
 
 // DeletePost deletes a post
@@ -74,23 +500,308 @@ func (s *Service) DeletePost(ctx context.Context, postID, userID uuid.UUID) erro
 		return fmt.Errorf("unauthorized: you don't own this post")
 	}
 
-	return s.repo.DeletePost(ctx, postID)
+	return s.repo.DeletePost(ctx, postID, userID)
+}
+
+// GetTrash retrieves the posts the user deleted that can still be restored
+func (s *Service) GetTrash(ctx context.Context, userID uuid.UUID, page, limit int) ([]models.Post, error) {
+	offset := (page - 1) * limit
+	posts, err := s.repo.GetTrashedPosts(ctx, userID, s.retention, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichPosts(ctx, postPointers(posts), userID); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// RestorePost takes one of the user's posts out of the trash
+func (s *Service) RestorePost(ctx context.Context, postID, userID uuid.UUID) (*models.Post, error) {
+	if err := s.repo.RestorePost(ctx, postID, userID, s.retention); err != nil {
+		return nil, err
+	}
+
+	return s.GetPostByID(ctx, postID, userID)
+}
+
+// GetTrashRetention returns how long deleted posts can be restored
+func (s *Service) GetTrashRetention() time.Duration {
+	return s.retention
+}
+
+// Repost re-shares a post, as a plain repost or as a quote post when content is given
+func (s *Service) Repost(ctx context.Context, postID, userID uuid.UUID, req *models.RepostRequest) (*models.Post, error) {
+	original, err := s.getPublishedPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reposting a plain repost re-shares the underlying post
+	if original.IsPlainRepost() {
+		if original.RepostOf == nil {
+			return nil, fmt.Errorf("post not found")
+		}
+		original = original.RepostOf
+	}
+
+	if original.Visibility != models.VisibilityPublic {
+		return nil, fmt.Errorf("only public posts can be reposted")
+	}
+
+	if req.Content == "" {
+		reposted, err := s.repo.HasReposted(ctx, original.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check repost: %w", err)
+		}
+		if reposted {
+			return nil, fmt.Errorf("post already reposted")
+		}
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+
+	repost := &models.Post{
+		UserID:     userID,
+		Content:    req.Content,
+		Visibility: visibility,
+		RepostOfID: &original.ID,
+		LinkURL:    linkpreview.ExtractURL(req.Content),
+	}
+
+	if err := s.screenPost(ctx, repost, req.Content); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreatePost(ctx, repost); err != nil {
+		return nil, fmt.Errorf("failed to create repost: %w", err)
+	}
+	s.enqueuePreview(repost.LinkURL)
+
+	return s.GetPostByID(ctx, repost.ID, userID)
+}
+
+// DeleteRepost undoes the user's plain repost of a post
+func (s *Service) DeleteRepost(ctx context.Context, postID, userID uuid.UUID) error {
+	return s.repo.DeleteRepost(ctx, postID, userID)
+}
+
+// VotePoll records the user's ballot on a post's poll and returns the updated tallies
+func (s *Service) VotePoll(ctx context.Context, postID, userID uuid.UUID, req *models.PollVoteRequest) (*models.Poll, error) {
+	post, err := s.getPublishedPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := post.Poll
+	if poll == nil {
+		return nil, fmt.Errorf("poll not found")
+	}
+
+	if poll.IsClosed {
+		return nil, fmt.Errorf("poll is closed")
+	}
+
+	if len(poll.VotedOptionIDs) > 0 {
+		return nil, fmt.Errorf("already voted")
+	}
+
+	validOptions := make(map[uuid.UUID]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		validOptions[option.ID] = true
+	}
+
+	optionIDs := []uuid.UUID{}
+	seen := make(map[uuid.UUID]bool)
+	for _, optionID := range req.OptionIDs {
+		if !validOptions[optionID] {
+			return nil, fmt.Errorf("invalid poll option")
+		}
+		if !seen[optionID] {
+			seen[optionID] = true
+			optionIDs = append(optionIDs, optionID)
+		}
+	}
+
+	if !poll.AllowsMultiple && len(optionIDs) > 1 {
+		return nil, fmt.Errorf("this poll allows a single choice")
+	}
+
+	if err := s.repo.VotePoll(ctx, poll.ID, userID, optionIDs); err != nil {
+		return nil, err
+	}
+
+	polls, err := s.repo.GetPollsByPostIDs(ctx, []uuid.UUID{postID}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	updated := polls[postID]
+	updated.IsClosed = time.Now().After(updated.ClosesAt)
+	return updated, nil
+}
+
+// GetReactionTypes returns the reactions users can leave on posts and comments
+func (s *Service) GetReactionTypes() []string {
+	return s.reactionTypes
 }
 
 // LikePost likes a post
 func (s *Service) LikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	return s.repo.LikePost(ctx, postID, userID)
+	post, err := s.getPublishedPost(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.repo.SetPostReaction(ctx, postID, userID, models.ReactionLike)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		return fmt.Errorf("post already liked")
+	}
+
+	s.notifications.Notify(ctx, post.UserID, userID, models.NotificationLike, notification.PostTarget(postID))
+	return nil
 }
 
 // UnlikePost unlikes a post
 func (s *Service) UnlikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	return s.repo.UnlikePost(ctx, postID, userID)
+	removed, err := s.repo.RemovePostReaction(ctx, postID, userID, models.ReactionLike)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return fmt.Errorf("post not liked")
+	}
+
+	return nil
+}
+
+// ReactToPost sets the user's reaction on a post, replacing any previous one
+func (s *Service) ReactToPost(ctx context.Context, postID, userID uuid.UUID, reaction string) error {
+	if !s.isValidReaction(reaction) {
+		return fmt.Errorf("invalid reaction")
+	}
+
+	post, err := s.getPublishedPost(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.repo.SetPostReaction(ctx, postID, userID, reaction)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.notifications.Notify(ctx, post.UserID, userID, models.NotificationLike, notification.PostTarget(postID))
+	}
+	return nil
+}
+
+// RemovePostReaction removes the user's reaction from a post
+func (s *Service) RemovePostReaction(ctx context.Context, postID, userID uuid.UUID) error {
+	removed, err := s.repo.RemovePostReaction(ctx, postID, userID, "")
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return fmt.Errorf("reaction not found")
+	}
+
+	return nil
+}
+
+// LikeComment likes a comment
+func (s *Service) LikeComment(ctx context.Context, commentID, userID uuid.UUID) error {
+	return s.ReactToComment(ctx, commentID, userID, models.ReactionLike)
+}
+
+// UnlikeComment unlikes a comment
+func (s *Service) UnlikeComment(ctx context.Context, commentID, userID uuid.UUID) error {
+	if _, err := s.getVisibleComment(ctx, commentID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveCommentReaction(ctx, commentID, userID, models.ReactionLike)
+}
+
+// ReactToComment sets the user's reaction on a comment, replacing any previous one
+func (s *Service) ReactToComment(ctx context.Context, commentID, userID uuid.UUID, reaction string) error {
+	if !s.isValidReaction(reaction) {
+		return fmt.Errorf("invalid reaction")
+	}
+
+	comment, err := s.getVisibleComment(ctx, commentID, userID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.repo.SetCommentReaction(ctx, commentID, userID, reaction)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		s.notifyCommentReaction(ctx, comment, userID)
+	}
+	return nil
+}
+
+// RemoveCommentReaction removes the user's reaction from a comment
+func (s *Service) RemoveCommentReaction(ctx context.Context, commentID, userID uuid.UUID) error {
+	if _, err := s.getVisibleComment(ctx, commentID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveCommentReaction(ctx, commentID, userID, "")
+}
+
+// getVisibleComment retrieves a published comment on a post the user can see.
+// Comments inherit the visibility of their post, so others are reported as missing.
+func (s *Service) getVisibleComment(ctx context.Context, commentID, userID uuid.UUID) (*models.Comment, error) {
+	comment, err := s.repo.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IsHeld {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	if _, err := s.getPublishedPost(ctx, comment.PostID, userID); err != nil {
+		if err.Error() == "post not found" {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// isValidReaction reports whether the reaction is one of the configured types
+func (s *Service) isValidReaction(reaction string) bool {
+	for _, t := range s.reactionTypes {
+		if t == reaction {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateComment creates a comment on a post
 func (s *Service) CreateComment(ctx context.Context, postID, userID uuid.UUID, req *models.CreateCommentRequest) (*models.Comment, error) {
-	// Check if post exists
-	_, err := s.repo.GetPostByID(ctx, postID)
+	// Check if post exists, is published and is visible to the user
+	post, err := s.getPublishedPost(ctx, postID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("post not found")
 	}
@@ -101,18 +812,77 @@ func (s *Service) CreateComment(ctx context.Context, postID, userID uuid.UUID, r
 		Content: req.Content,
 	}
 
+	if err := s.screenComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.CreateComment(ctx, comment); err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
+	s.notifyComment(ctx, post, comment)
+
+	// Held comments stay hidden from other users until a moderator approves them
+	if !comment.IsHeld {
+		s.hub.Publish(ctx, realtime.PostTopic(postID), realtime.EventComment, comment)
+	}
 
 	return comment, nil
 }
 
 // GetCommentsByPostID retrieves comments for a post
 func (s *Service) GetCommentsByPostID(ctx context.Context, postID, userID uuid.UUID) ([]models.Comment, error) {
+	// Comments inherit the visibility of their post
+	if _, err := s.GetPostByID(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
 	return s.repo.GetCommentsByPostID(ctx, postID, userID)
 }
 
+// UpdateComment updates a comment
+func (s *Service) UpdateComment(ctx context.Context, commentID, userID uuid.UUID, req *models.UpdateCommentRequest) error {
+	// Check ownership
+	isOwner, err := s.repo.CheckCommentOwnership(ctx, commentID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check ownership: %w", err)
+	}
+
+	if !isOwner {
+		return fmt.Errorf("unauthorized: you don't own this comment")
+	}
+
+	// A flagged edit hides the comment until a moderator approves it
+	comment := &models.Comment{ID: commentID, UserID: userID, Content: req.Content}
+	if err := s.screenComment(ctx, comment); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateComment(ctx, commentID, userID, req.Content, comment.IsHeld, comment.HeldReasons)
+}
+
+// GetCommentRevisions retrieves a comment's edit history, visible to its author and moderators
+func (s *Service) GetCommentRevisions(ctx context.Context, commentID, userID uuid.UUID) ([]models.Revision, error) {
+	exists, err := s.repo.CommentExists(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check comment: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	isOwner, err := s.repo.CheckCommentOwnership(ctx, commentID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ownership: %w", err)
+	}
+
+	if err := s.checkAuthorOrModerator(ctx, isOwner, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetCommentRevisions(ctx, commentID)
+}
+
 // DeleteComment deletes a comment
 func (s *Service) DeleteComment(ctx context.Context, commentID, userID uuid.UUID) error {
 	// Check ownership
@@ -127,3 +897,242 @@ func (s *Service) DeleteComment(ctx context.Context, commentID, userID uuid.UUID
 
 	return s.repo.DeleteComment(ctx, commentID)
 }
+
+// checkAuthorOrModerator ensures the user is the content's author or a moderator
+func (s *Service) checkAuthorOrModerator(ctx context.Context, isAuthor bool, userID uuid.UUID) error {
+	if isAuthor {
+		return nil
+	}
+
+	isModerator, err := s.repo.IsModerator(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check role: %w", err)
+	}
+
+	if !isModerator {
+		return fmt.Errorf("unauthorized: only the author or a moderator can view edit history")
+	}
+
+	return nil
+}
+
+// canViewPost checks the post's visibility level against the user
+func canViewPost(ctx context.Context, repo *Repository, post *models.Post, userID uuid.UUID) (bool, error) {
+	if post.UserID == userID {
+		return true, nil
+	}
+
+	// Posts held for review are only visible to their author until approved,
+	// and so are drafts and scheduled posts until published
+	if post.IsHeld || !post.IsPublished() {
+		return false, nil
+	}
+
+	switch post.Visibility {
+	case models.VisibilityPublic:
+		return true, nil
+	case models.VisibilityFollowers:
+		isFollower, err := repo.IsFollowing(ctx, userID, post.UserID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check follow status: %w", err)
+		}
+		return isFollower, nil
+	default:
+		return false, nil
+	}
+}
+
+// screenPost runs the post's text through the content filters and marks it as
+// held for review when flagged. Plain reposts carry no text and are not screened.
+func (s *Service) screenPost(ctx context.Context, post *models.Post, text string) error {
+	if text == "" {
+		return nil
+	}
+
+	result, err := s.filter.Run(ctx, &contentfilter.Content{
+		AuthorID: post.UserID,
+		ItemID:   post.ID,
+		Kind:     contentfilter.KindPost,
+		Text:     text,
+	})
+	if err != nil {
+		return err
+	}
+
+	post.IsHeld = result.Held
+	post.HeldReasons = result.Reasons
+	return nil
+}
+
+// screenComment runs the comment's text through the content filters and marks
+// it as held for review when flagged
+func (s *Service) screenComment(ctx context.Context, comment *models.Comment) error {
+	result, err := s.filter.Run(ctx, &contentfilter.Content{
+		AuthorID: comment.UserID,
+		ItemID:   comment.ID,
+		Kind:     contentfilter.KindComment,
+		Text:     comment.Content,
+	})
+	if err != nil {
+		return err
+	}
+
+	comment.IsHeld = result.Held
+	comment.HeldReasons = result.Reasons
+	return nil
+}
+
+// enrichPosts attaches reposted originals, polls and link previews to posts
+func (s *Service) enrichPosts(ctx context.Context, posts []*models.Post, userID uuid.UUID) error {
+	if err := s.attachReposts(ctx, posts, userID); err != nil {
+		return err
+	}
+
+	// Originals shown inside reposts get their polls too
+	withOriginals := append([]*models.Post{}, posts...)
+	for _, post := range posts {
+		if post.RepostOf != nil {
+			withOriginals = append(withOriginals, post.RepostOf)
+		}
+	}
+
+	if err := s.attachPolls(ctx, withOriginals, userID); err != nil {
+		return err
+	}
+
+	return s.attachLinkPreviews(ctx, withOriginals)
+}
+
+// attachLinkPreviews adds the cached preview of each post's link, when it has been fetched
+func (s *Service) attachLinkPreviews(ctx context.Context, posts []*models.Post) error {
+	var urls []string
+	for _, post := range posts {
+		if post.LinkURL != nil {
+			urls = append(urls, *post.LinkURL)
+		}
+	}
+
+	if len(urls) == 0 {
+		return nil
+	}
+
+	previews, err := s.previews.GetPreviews(ctx, urls)
+	if err != nil {
+		return fmt.Errorf("failed to get link previews: %w", err)
+	}
+
+	for _, post := range posts {
+		if post.LinkURL != nil {
+			post.LinkPreview = previews[*post.LinkURL]
+		}
+	}
+
+	return nil
+}
+
+// enqueuePreview starts fetching the preview of a post's link in the background
+func (s *Service) enqueuePreview(linkURL *string) {
+	if linkURL != nil {
+		s.previews.Enqueue(*linkURL)
+	}
+}
+
+// attachPolls loads the poll of every post that has one
+func (s *Service) attachPolls(ctx context.Context, posts []*models.Post, userID uuid.UUID) error {
+	ids := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	polls, err := s.repo.GetPollsByPostIDs(ctx, ids, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get polls: %w", err)
+	}
+
+	now := time.Now()
+	for _, post := range posts {
+		if poll, ok := polls[post.ID]; ok {
+			poll.IsClosed = now.After(poll.ClosesAt)
+			post.Poll = poll
+		}
+	}
+
+	return nil
+}
+
+// newPoll builds a poll from the request, checking its closing time
+func newPoll(req *models.CreatePollRequest) (*models.Poll, error) {
+	if !req.ClosesAt.After(time.Now()) {
+		return nil, fmt.Errorf("poll closing time must be in the future")
+	}
+
+	poll := &models.Poll{
+		AllowsMultiple: req.AllowsMultiple,
+		ClosesAt:       req.ClosesAt.UTC(),
+		Options:        make([]models.PollOption, 0, len(req.Options)),
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	for _, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, fmt.Errorf("poll options cannot be empty")
+		}
+		if seen[strings.ToLower(text)] {
+			return nil, fmt.Errorf("poll options must be unique")
+		}
+		seen[strings.ToLower(text)] = true
+		poll.Options = append(poll.Options, models.PollOption{Text: text})
+	}
+
+	return poll, nil
+}
+
+// attachReposts loads the original post of every repost and quote post the user can see
+func (s *Service) attachReposts(ctx context.Context, posts []*models.Post, userID uuid.UUID) error {
+	ids := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		if post.RepostOfID != nil {
+			ids = append(ids, *post.RepostOfID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	originals, err := s.repo.GetPostsByIDs(ctx, ids, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get reposted posts: %w", err)
+	}
+
+	for _, post := range posts {
+		if post.RepostOfID == nil {
+			continue
+		}
+
+		original, ok := originals[*post.RepostOfID]
+		if !ok {
+			continue
+		}
+
+		canView, err := canViewPost(ctx, s.repo, original, userID)
+		if err != nil {
+			return err
+		}
+		if canView {
+			post.RepostOf = original
+		}
+	}
+
+	return nil
+}
+
+// postPointers returns pointers into the posts slice so it can be enriched in place
+func postPointers(posts []models.Post) []*models.Post {
+	pointers := make([]*models.Post, len(posts))
+	for i := range posts {
+		pointers[i] = &posts[i]
+	}
+	return pointers
+}
@@ -0,0 +1,59 @@
+package post
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckScheduleTimeAcrossTimeZones(t *testing.T) {
+	// Paris is ahead of UTC and Honolulu behind it, so their wall clocks sit on
+	// either side of the current UTC one
+	paris := time.FixedZone("Europe/Paris", 2*60*60)
+	honolulu := time.FixedZone("Pacific/Honolulu", -10*60*60)
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		scheduledAt time.Time
+		wantErr     bool
+	}{
+		{
+			// Wall clock earlier than UTC now, but an hour in the future
+			name:        "future time behind UTC",
+			scheduledAt: now.Add(time.Hour).In(honolulu),
+		},
+		{
+			// Wall clock later than UTC now, but an hour in the past
+			name:        "past time ahead of UTC",
+			scheduledAt: now.Add(-time.Hour).In(paris),
+			wantErr:     true,
+		},
+		{
+			name:        "future time ahead of UTC",
+			scheduledAt: now.Add(time.Hour).In(paris),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkScheduleTime(tt.scheduledAt)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("checkScheduleTime(%v) = %v, want an error", tt.scheduledAt, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkScheduleTime(%v): %v", tt.scheduledAt, err)
+			}
+
+			// Stored as a UTC wall clock holding the same instant
+			if got.Location() != time.UTC {
+				t.Errorf("location = %v, want UTC", got.Location())
+			}
+			if !got.Equal(tt.scheduledAt) {
+				t.Errorf("scheduled at %v, want %v", got, tt.scheduledAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// heartbeatInterval keeps idle streams open through proxies and detects closed connections
+const heartbeatInterval = 25 * time.Second
+
+// reconnectDelay is how long browsers wait before reopening a dropped stream
+const reconnectDelay = 3 * time.Second
+
+// Authorizer checks that a user may follow the events of a post or event,
+// returning an error when it does not exist or is not visible to them
+type Authorizer func(ctx context.Context, id, userID uuid.UUID) error
+
+type Handler struct {
+	hub       *Hub
+	viewPost  Authorizer
+	viewEvent Authorizer
+}
+
+func NewHandler(hub *Hub, viewPost, viewEvent Authorizer) *Handler {
+	return &Handler{hub: hub, viewPost: viewPost, viewEvent: viewEvent}
+}
+
+// Stream handles a Server-Sent Events stream of the user's new notifications
+// and, when requested, of the new comments on an open post and the RSVP counts
+// of an open event
+// GET /api/v1/realtime/stream?post_id=...&event_id=...
+func (h *Handler) Stream(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	topics := []string{UserTopic(userID)}
+
+	if raw := c.Query("post_id"); raw != "" {
+		postID, err := utils.ParseUUID(raw)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid post ID")
+		}
+		if err := h.viewPost(c.Context(), postID, userID); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Post not found")
+		}
+		topics = append(topics, PostTopic(postID))
+	}
+
+	if raw := c.Query("event_id"); raw != "" {
+		eventID, err := utils.ParseUUID(raw)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid event ID")
+		}
+		if err := h.viewEvent(c.Context(), eventID, userID); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Event not found")
+		}
+		topics = append(topics, EventTopic(eventID))
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	sub := h.hub.Subscribe(topics...)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+		for {
+			if err := w.Flush(); err != nil {
+				// The client went away
+				return
+			}
+
+			select {
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(w, event); err != nil {
+					log.Printf("Realtime %s event on %s encoding error: %v", event.Type, event.Topic, err)
+				}
+				// A suspended user's stream ends; reconnecting is refused
+				if event.Type == EventSuspended {
+					w.Flush()
+					return
+				}
+			case <-heartbeat.C:
+				w.WriteString(": ping\n\n")
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeEvent writes an event in the Server-Sent Events format, named after its type
+func writeEvent(w *bufio.Writer, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
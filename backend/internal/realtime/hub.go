@@ -0,0 +1,202 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// channelPrefix namespaces the Redis pub/sub channels carrying real-time events
+const channelPrefix = "realtime:"
+
+// subscriberBuffer is how many events can wait for a slow client before new ones are dropped
+const subscriberBuffer = 32
+
+// Event types streamed to clients
+const (
+	EventNotification = "notification"
+	EventComment      = "comment"
+	EventRSVPCounts   = "rsvp_counts"
+	EventMessage      = "message"
+	EventTyping       = "typing"
+	EventReceipt      = "receipt"
+	EventPresence     = "presence"
+	EventSuspended    = "suspended"
+)
+
+// Event is a message delivered to the clients subscribed to its topic
+type Event struct {
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// UserTopic returns the topic of events addressed to a user
+func UserTopic(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// PostTopic returns the topic of events happening on a post
+func PostTopic(postID uuid.UUID) string {
+	return "post:" + postID.String()
+}
+
+// EventTopic returns the topic of events happening on an event
+func EventTopic(eventID uuid.UUID) string {
+	return "event:" + eventID.String()
+}
+
+// Subscription receives the events of a set of topics until it is unsubscribed
+// or the hub stops, at which point Events is closed
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	topics []string
+}
+
+// Hub fans real-time events out to the clients connected to this API instance.
+// Events are published through Redis so that clients connected to any instance
+// receive them.
+type Hub struct {
+	redis   *redis.Client
+	mu      sync.RWMutex
+	topics  map[string]map[*Subscription]struct{}
+	stopped bool
+}
+
+func NewHub(client *redis.Client) *Hub {
+	return &Hub{
+		redis:  client,
+		topics: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends an event to the subscribers of a topic on every API instance.
+// Failures are logged rather than returned so they never fail the action being
+// published.
+func (h *Hub) Publish(ctx context.Context, topic, kind string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Realtime %s event on %s encoding error: %v", kind, topic, err)
+		return
+	}
+
+	message, err := json.Marshal(Event{Topic: topic, Type: kind, Data: payload})
+	if err != nil {
+		log.Printf("Realtime %s event on %s encoding error: %v", kind, topic, err)
+		return
+	}
+
+	if err := h.redis.Publish(ctx, channelPrefix+topic, message).Err(); err != nil {
+		log.Printf("Realtime %s event on %s publish error: %v", kind, topic, err)
+	}
+}
+
+// Subscribe starts receiving the events of the given topics
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	events := make(chan Event, subscriberBuffer)
+	sub := &Subscription{Events: events, events: events, topics: topics}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		close(sub.events)
+		return sub
+	}
+
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Subscription]struct{})
+		}
+		h.topics[topic][sub] = struct{}{}
+	}
+
+	return sub
+}
+
+// Unsubscribe stops a subscription and closes its events channel
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// Run relays the events published by every instance to the local subscribers
+// until ctx is cancelled, then ends all subscriptions
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.redis.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			h.stop()
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				h.stop()
+				return
+			}
+			h.dispatch(msg.Payload)
+		}
+	}
+}
+
+// dispatch delivers a published event to the local subscribers of its topic
+func (h *Hub) dispatch(payload string) {
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Printf("Realtime event decoding error: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.topics[event.Topic] {
+		select {
+		case sub.events <- event:
+		default:
+			log.Printf("Realtime %s event on %s dropped for a slow client", event.Type, event.Topic)
+		}
+	}
+}
+
+// stop ends every subscription and refuses new ones
+func (h *Hub) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for _, subs := range h.topics {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove detaches a subscription from its topics; h.mu must be held
+func (h *Hub) remove(sub *Subscription) {
+	removed := false
+	for _, topic := range sub.topics {
+		if _, ok := h.topics[topic][sub]; !ok {
+			continue
+		}
+		removed = true
+		delete(h.topics[topic], sub)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+
+	if removed {
+		close(sub.events)
+	}
+}
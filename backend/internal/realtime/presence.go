@@ -0,0 +1,140 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// presenceTTL is how long a connection counts as online without a refresh, so
+// that users connected to an instance that crashed go offline on their own
+const presenceTTL = 90 * time.Second
+
+// PresenceRefreshInterval is how often open connections must call Refresh
+const PresenceRefreshInterval = 30 * time.Second
+
+// PresenceStatus tells whether a user is online and when they were last seen
+type PresenceStatus struct {
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+// Presence tracks the users connected to any API instance. Each connection is
+// a member of a Redis sorted set per user, scored by when it expires; a user is
+// online while that set has live members.
+type Presence struct {
+	redis *redis.Client
+}
+
+func NewPresence(client *redis.Client) *Presence {
+	return &Presence{redis: client}
+}
+
+func connectionsKey(userID uuid.UUID) string {
+	return "presence:connections:" + userID.String()
+}
+
+func lastSeenKey(userID uuid.UUID) string {
+	return "presence:last_seen:" + userID.String()
+}
+
+// Connect records a new connection of a user and reports whether they just came online
+func (p *Presence) Connect(ctx context.Context, userID uuid.UUID, connectionID string) (bool, error) {
+	count, err := p.touch(ctx, userID, connectionID)
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// Refresh keeps a connection alive and the user's last seen time current
+func (p *Presence) Refresh(ctx context.Context, userID uuid.UUID, connectionID string) error {
+	_, err := p.touch(ctx, userID, connectionID)
+	return err
+}
+
+// Disconnect removes a connection of a user and reports whether it was their
+// last, in which case they went offline at the returned time
+func (p *Presence) Disconnect(ctx context.Context, userID uuid.UUID, connectionID string) (bool, time.Time, error) {
+	now := time.Now()
+	key := connectionsKey(userID)
+
+	var removed *redis.IntCmd
+	var count *redis.IntCmd
+	_, err := p.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removed = pipe.ZRem(ctx, key, connectionID)
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+		count = pipe.ZCard(ctx, key)
+		pipe.Set(ctx, lastSeenKey(userID), now.UnixMilli(), 0)
+		return nil
+	})
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to record disconnection: %w", err)
+	}
+
+	return removed.Val() == 1 && count.Val() == 0, now, nil
+}
+
+// touch (re)registers a connection until presenceTTL from now and returns the
+// user's number of live connections
+func (p *Presence) touch(ctx context.Context, userID uuid.UUID, connectionID string) (int64, error) {
+	now := time.Now()
+	key := connectionsKey(userID)
+
+	var count *redis.IntCmd
+	_, err := p.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Add(presenceTTL).UnixMilli()), Member: connectionID})
+		count = pipe.ZCard(ctx, key)
+		pipe.Expire(ctx, key, presenceTTL)
+		pipe.Set(ctx, lastSeenKey(userID), now.UnixMilli(), 0)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record presence: %w", err)
+	}
+
+	return count.Val(), nil
+}
+
+// Get retrieves the presence of the given users
+func (p *Presence) Get(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]PresenceStatus, error) {
+	statuses := make(map[uuid.UUID]PresenceStatus, len(userIDs))
+	if len(userIDs) == 0 {
+		return statuses, nil
+	}
+
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	counts := make([]*redis.IntCmd, len(userIDs))
+	lastSeen := make([]*redis.StringCmd, len(userIDs))
+
+	_, err := p.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, userID := range userIDs {
+			counts[i] = pipe.ZCount(ctx, connectionsKey(userID), "("+now, "+inf")
+			lastSeen[i] = pipe.Get(ctx, lastSeenKey(userID))
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	for i, userID := range userIDs {
+		if err := counts[i].Err(); err != nil {
+			return nil, fmt.Errorf("failed to get presence: %w", err)
+		}
+
+		status := PresenceStatus{Online: counts[i].Val() > 0}
+		if millis, err := lastSeen[i].Int64(); err == nil {
+			at := time.UnixMilli(millis).UTC()
+			status.LastSeenAt = &at
+		}
+		statuses[userID] = status
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"log"
+
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Search handles full-text search across posts, events and users
+// GET /api/v1/search?q=concert&type=event&page=1&limit=20
+func (h *Handler) Search(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	query := c.Query("q")
+	searchType := c.Query("type")
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	results, err := h.service.Search(c.Context(), query, searchType, userID, page, limit)
+	if err != nil {
+		switch err.Error() {
+		case "query too short":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Search query must be at least 2 characters")
+		case "query too long":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Search query must be at most 200 characters")
+		case "invalid search type":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Type must be post, event, user or all")
+		}
+		log.Printf("Search error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to search")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"results": results,
+		"query":   query,
+		"type":    searchType,
+		"page":    page,
+		"limit":   limit,
+	})
+}
@@ -0,0 +1,206 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// searchQuery parses the user's input with every configuration used by the
+// search vectors, so stemmed French and English words and raw names all match
+const searchQuery = `
+	WITH q AS (
+		SELECT websearch_to_tsquery('french', $1)
+		    || websearch_to_tsquery('english', $1)
+		    || websearch_to_tsquery('simple', $1) AS query
+	)`
+
+// headlineOptions wrap matches in <mark> tags and keep excerpts short
+const headlineOptions = `'StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "'`
+
+// escapeHTML escapes a text column in SQL so headlines only contain our own tags
+func escapeHTML(column string) string {
+	return fmt.Sprintf(`replace(replace(replace(coalesce(%s, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`, column)
+}
+
+// SearchPosts searches posts the current user can see, best matches first
+func (r *Repository) SearchPosts(ctx context.Context, text string, currentUserID uuid.UUID, limit, offset int) ([]models.SearchResult, error) {
+	query := searchQuery + `
+		SELECT p.id, p.user_id, p.content, p.likes_count, p.comments_count, p.visibility, p.created_at,
+		       u.id, u.username, u.first_name, u.last_name, u.avatar_url,
+		       ts_rank_cd(p.search_vector, q.query) AS rank,
+		       ts_headline('french', ` + escapeHTML("p.content") + `, q.query, ` + headlineOptions + `)
+		FROM posts p
+		CROSS JOIN q
+		JOIN users u ON p.user_id = u.id
+		WHERE p.search_vector @@ q.query
+		  AND p.is_deleted = false AND p.is_hidden = false AND p.is_held = false
+		  AND p.status = 'published'
+		  AND u.is_active = true
+		  AND (
+		      p.user_id = $2
+		      OR p.visibility = 'public'
+		      OR (p.visibility = 'followers' AND EXISTS(
+		          SELECT 1 FROM follows f WHERE f.follower_id = $2 AND f.following_id = p.user_id
+		      ))
+		  )
+		ORDER BY rank DESC, p.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, text, currentUserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var post models.Post
+		var author models.UserResponse
+		result := models.SearchResult{Type: models.SearchTypePost}
+
+		err := rows.Scan(
+			&post.ID,
+			&post.UserID,
+			&post.Content,
+			&post.LikesCount,
+			&post.CommentsCount,
+			&post.Visibility,
+			&post.CreatedAt,
+			&author.ID,
+			&author.Username,
+			&author.FirstName,
+			&author.LastName,
+			&author.AvatarURL,
+			&result.Rank,
+			&result.Highlight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post result: %w", err)
+		}
+
+		post.Author = &author
+		result.ID = post.ID
+		result.Post = &post
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// SearchEvents searches user-created events, best matches first
+func (r *Repository) SearchEvents(ctx context.Context, text string, limit, offset int) ([]models.SearchResult, error) {
+	query := searchQuery + `
+		SELECT e.id, e.title, e.start_date, e.end_date, e.location, e.city, e.category,
+		       e.image_url, e.is_free, e.going_count, e.interested_count, e.source, e.created_by, e.created_at,
+		       ts_rank_cd(e.search_vector, q.query) AS rank,
+		       ts_headline('french', ` + escapeHTML("e.description") + `, q.query, ` + headlineOptions + `)
+		FROM events e
+		CROSS JOIN q
+		WHERE e.search_vector @@ q.query
+		  AND e.source = 'user'
+		  AND e.is_deleted = false AND e.is_hidden = false AND e.is_held = false
+		ORDER BY rank DESC, e.start_date ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, text, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var event models.Event
+		result := models.SearchResult{Type: models.SearchTypeEvent}
+
+		err := rows.Scan(
+			&event.ID,
+			&event.Title,
+			&event.StartDate,
+			&event.EndDate,
+			&event.Location,
+			&event.City,
+			&event.Category,
+			&event.ImageURL,
+			&event.IsFree,
+			&event.GoingCount,
+			&event.InterestedCount,
+			&event.Source,
+			&event.CreatedBy,
+			&event.CreatedAt,
+			&result.Rank,
+			&result.Highlight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event result: %w", err)
+		}
+
+		result.ID = event.ID
+		result.Event = &event
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// SearchUsers searches active user profiles, best matches first
+func (r *Repository) SearchUsers(ctx context.Context, text string, limit, offset int) ([]models.SearchResult, error) {
+	query := searchQuery + `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.avatar_url, u.bio,
+		       ts_rank_cd(u.search_vector, q.query) AS rank,
+		       ts_headline('simple', ` + escapeHTML("u.first_name || ' ' || u.last_name || ' (@' || u.username || ') ' || coalesce(u.bio, '')") + `,
+		                   q.query, ` + headlineOptions + `)
+		FROM users u
+		CROSS JOIN q
+		WHERE u.search_vector @@ q.query
+		  AND u.is_active = true
+		ORDER BY rank DESC, u.username ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, text, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var user models.UserResponse
+		result := models.SearchResult{Type: models.SearchTypeUser}
+
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.AvatarURL,
+			&user.Bio,
+			&result.Rank,
+			&result.Highlight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user result: %w", err)
+		}
+
+		result.ID = user.ID
+		result.User = &user
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxMixedResults bounds how deep a search across all types can be paginated,
+// since each type has to be fetched up to the requested page before merging
+const maxMixedResults = 500
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search runs a full-text search over posts, events and users. searchType
+// restricts results to one type; when empty, all types are ranked together.
+func (s *Service) Search(ctx context.Context, text, searchType string, userID uuid.UUID, page, limit int) ([]models.SearchResult, error) {
+	text = strings.TrimSpace(text)
+	if len([]rune(text)) < 2 {
+		return nil, fmt.Errorf("query too short")
+	}
+	if len([]rune(text)) > 200 {
+		return nil, fmt.Errorf("query too long")
+	}
+
+	offset := (page - 1) * limit
+
+	switch searchType {
+	case models.SearchTypePost:
+		return s.repo.SearchPosts(ctx, text, userID, limit, offset)
+	case models.SearchTypeEvent:
+		return s.repo.SearchEvents(ctx, text, limit, offset)
+	case models.SearchTypeUser:
+		return s.repo.SearchUsers(ctx, text, limit, offset)
+	case "", "all":
+		return s.searchAll(ctx, text, userID, limit, offset)
+	default:
+		return nil, fmt.Errorf("invalid search type")
+	}
+}
+
+// searchAll merges the best matches of every type by rank
+func (s *Service) searchAll(ctx context.Context, text string, userID uuid.UUID, limit, offset int) ([]models.SearchResult, error) {
+	depth := offset + limit
+	if depth > maxMixedResults {
+		return []models.SearchResult{}, nil
+	}
+
+	posts, err := s.repo.SearchPosts(ctx, text, userID, depth, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.SearchEvents(ctx, text, depth, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.repo.SearchUsers(ctx, text, depth, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := append(append(posts, events...), users...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	if offset >= len(results) {
+		return []models.SearchResult{}, nil
+	}
+
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	return results[offset:end], nil
+}
@@ -0,0 +1,39 @@
+package trending
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetTopics handles retrieval of trending hashtags and keywords
+// GET /api/v1/trending/topics?city=rouen&limit=10
+func (h *Handler) GetTopics(c *fiber.Ctx) error {
+	city := strings.TrimSpace(c.Query("city"))
+	limit := c.QueryInt("limit", 10)
+
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	topics, err := h.service.GetTopics(c.Context(), city, limit)
+	if err != nil {
+		log.Printf("Get trending topics error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get trending topics")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"topics": topics,
+		"city":   city,
+	})
+}
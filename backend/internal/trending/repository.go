@@ -0,0 +1,138 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// recentPost is the slice of a post needed to score topics
+type recentPost struct {
+	UserID     uuid.UUID
+	Content    string
+	City       string
+	Engagement int
+	CreatedAt  time.Time
+}
+
+// GetRecentPosts retrieves published public posts created since the given time
+func (r *Repository) GetRecentPosts(ctx context.Context, since time.Time) ([]recentPost, error) {
+	query := `
+		SELECT p.user_id, p.content, LOWER(COALESCE(p.city, '')),
+		       p.likes_count + p.comments_count + p.repost_count, p.created_at
+		FROM posts p
+		WHERE p.created_at >= $1
+		  AND p.content <> ''
+		  AND p.visibility = 'public'
+		  AND p.status = 'published'
+		  AND p.is_deleted = false AND p.is_hidden = false AND p.is_held = false
+	`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []recentPost
+	for rows.Next() {
+		var post recentPost
+		if err := rows.Scan(&post.UserID, &post.Content, &post.City, &post.Engagement, &post.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+// ReplaceTopics swaps the stored trending topics for a freshly computed set.
+// When another instance is already writing its own set, this one is skipped.
+func (r *Repository) ReplaceTopics(ctx context.Context, topics []models.TrendingTopic) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('trending_topics'))`).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to lock trending topics: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM trending_topics`); err != nil {
+		return fmt.Errorf("failed to clear trending topics: %w", err)
+	}
+
+	query := `
+		INSERT INTO trending_topics (city, kind, topic, score, post_count, author_count, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for _, topic := range topics {
+		_, err := tx.Exec(ctx, query,
+			topic.City, topic.Kind, topic.Topic, topic.Score, topic.PostCount, topic.AuthorCount, topic.ComputedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save trending topic: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit trending topics: %w", err)
+	}
+
+	return nil
+}
+
+// GetTopics retrieves the top trending topics for a city, or across all
+// cities when city is empty
+func (r *Repository) GetTopics(ctx context.Context, city string, limit int) ([]models.TrendingTopic, error) {
+	query := `
+		SELECT city, kind, topic, score, post_count, author_count, computed_at
+		FROM trending_topics
+		WHERE city = LOWER($1)
+		ORDER BY score DESC, topic ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, city, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending topics: %w", err)
+	}
+	defer rows.Close()
+
+	topics := []models.TrendingTopic{}
+	for rows.Next() {
+		var topic models.TrendingTopic
+		err := rows.Scan(
+			&topic.City,
+			&topic.Kind,
+			&topic.Topic,
+			&topic.Score,
+			&topic.PostCount,
+			&topic.AuthorCount,
+			&topic.ComputedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trending topic: %w", err)
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
@@ -0,0 +1,165 @@
+package trending
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/google/uuid"
+)
+
+// minKeywordAuthors is how many people must use a plain keyword before it can
+// trend, so one chatty account can't push its own vocabulary
+const minKeywordAuthors = 2
+
+type Service struct {
+	repo   *Repository
+	config config.TrendingConfig
+}
+
+func NewService(repo *Repository, cfg *config.Config) *Service {
+	return &Service{
+		repo:   repo,
+		config: cfg.Trending,
+	}
+}
+
+// GetTopics returns the trending topics for a city, or across all cities when city is empty
+func (s *Service) GetTopics(ctx context.Context, city string, limit int) ([]models.TrendingTopic, error) {
+	return s.repo.GetTopics(ctx, city, limit)
+}
+
+// Run refreshes trending topics right away and then on every refresh interval
+// until ctx is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("Trending topics refresh error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// topicStats accumulates the score of a topic within one city
+type topicStats struct {
+	kind     string
+	topic    string
+	posts    int
+	byAuthor map[uuid.UUID]float64 // Best post weight per author
+}
+
+// Refresh recomputes trending topics from recent posts. Each post's weight
+// decays with age and grows with engagement; an author only counts once per
+// topic, with their best post.
+func (s *Service) Refresh(ctx context.Context) error {
+	// created_at holds UTC wall clock times
+	now := time.Now().UTC()
+	posts, err := s.repo.GetRecentPosts(ctx, now.Add(-s.config.Window))
+	if err != nil {
+		return err
+	}
+
+	// Topics per city; the empty city collects every post
+	cities := make(map[string]map[string]*topicStats)
+	add := func(city, kind, topic string, userID uuid.UUID, weight float64) {
+		topics, ok := cities[city]
+		if !ok {
+			topics = make(map[string]*topicStats)
+			cities[city] = topics
+		}
+
+		key := kind + ":" + topic
+		stats, ok := topics[key]
+		if !ok {
+			stats = &topicStats{kind: kind, topic: topic, byAuthor: make(map[uuid.UUID]float64)}
+			topics[key] = stats
+		}
+
+		stats.posts++
+		if weight > stats.byAuthor[userID] {
+			stats.byAuthor[userID] = weight
+		}
+	}
+
+	for _, post := range posts {
+		weight := s.postWeight(post, now)
+
+		scopes := []string{""}
+		if post.City != "" {
+			scopes = append(scopes, post.City)
+		}
+
+		for _, city := range scopes {
+			hashtags, keywords := extractTopics(post.Content, city)
+			for _, tag := range hashtags {
+				add(city, models.TopicKindHashtag, tag, post.UserID, weight)
+			}
+			for _, word := range keywords {
+				add(city, models.TopicKindKeyword, word, post.UserID, weight)
+			}
+		}
+	}
+
+	var result []models.TrendingTopic
+	for city, topics := range cities {
+		var ranked []models.TrendingTopic
+		for _, stats := range topics {
+			if stats.kind == models.TopicKindKeyword && len(stats.byAuthor) < minKeywordAuthors {
+				continue
+			}
+
+			score := 0.0
+			for _, weight := range stats.byAuthor {
+				score += weight
+			}
+
+			ranked = append(ranked, models.TrendingTopic{
+				City:        city,
+				Kind:        stats.kind,
+				Topic:       stats.topic,
+				Score:       math.Round(score*1000) / 1000,
+				PostCount:   stats.posts,
+				AuthorCount: len(stats.byAuthor),
+				ComputedAt:  now,
+			})
+		}
+
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].Score != ranked[j].Score {
+				return ranked[i].Score > ranked[j].Score
+			}
+			return ranked[i].Topic < ranked[j].Topic
+		})
+
+		if len(ranked) > s.config.Limit {
+			ranked = ranked[:s.config.Limit]
+		}
+		result = append(result, ranked...)
+	}
+
+	return s.repo.ReplaceTopics(ctx, result)
+}
+
+// postWeight halves a post's weight every half-life and boosts it logarithmically
+// with likes, comments and reposts
+func (s *Service) postWeight(post recentPost, now time.Time) float64 {
+	age := now.Sub(post.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	decay := math.Pow(0.5, age.Hours()/s.config.HalfLife.Hours())
+	return decay * (1 + math.Log1p(float64(post.Engagement)))
+}
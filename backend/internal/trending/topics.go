@@ -0,0 +1,72 @@
+package trending
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]{2,50})`)
+	linkPattern    = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	mentionPattern = regexp.MustCompile(`@[\w-]+`)
+)
+
+// stopwords are common French and English words that never make a topic
+var stopwords = toSet(
+	// French
+	"alors", "aussi", "autre", "avant", "avec", "avoir", "bien", "cela", "celle", "celui",
+	"cette", "chez", "comme", "comment", "dans", "depuis", "donc", "elle", "elles", "encore",
+	"entre", "était", "étais", "être", "fait", "faire", "faut", "leur", "leurs", "mais",
+	"même", "merci", "moins", "notre", "nous", "parce", "pour", "pourquoi", "quand", "quel",
+	"quelle", "quelque", "sans", "sont", "sous", "suis", "très", "tout", "toute", "tous",
+	"toutes", "trop", "vers", "voilà", "votre", "vous", "aujourd", "demain", "hier", "ceux",
+	"ici", "peut", "plus", "rien", "soir", "matin", "jour", "fois", "juste",
+	// English
+	"about", "after", "again", "also", "because", "been", "before", "being", "could", "does",
+	"doing", "down", "each", "from", "have", "having", "here", "just", "like", "more",
+	"most", "much", "only", "other", "over", "really", "same", "should", "some", "such",
+	"than", "that", "their", "them", "then", "there", "these", "they", "this", "those",
+	"through", "today", "tomorrow", "tonight", "very", "want", "were", "what", "when", "where",
+	"which", "while", "will", "with", "would", "your", "yours", "thanks", "going", "into",
+)
+
+// extractTopics returns the distinct hashtags and keywords of a post
+func extractTopics(content, city string) (hashtags, keywords []string) {
+	content = linkPattern.ReplaceAllString(content, " ")
+	content = mentionPattern.ReplaceAllString(content, " ")
+
+	seenTags := make(map[string]bool)
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		tag := strings.ToLower(match[1])
+		if !seenTags[tag] {
+			seenTags[tag] = true
+			hashtags = append(hashtags, tag)
+		}
+	}
+
+	// Keywords come from the text outside hashtags
+	text := hashtagPattern.ReplaceAllString(content, " ")
+	seenWords := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	for _, word := range words {
+		word = strings.Trim(word, "-")
+		if len([]rune(word)) < 4 || len(word) > 100 || stopwords[word] || word == city || seenWords[word] {
+			continue
+		}
+		seenWords[word] = true
+		keywords = append(keywords, word)
+	}
+
+	return hashtags, keywords
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
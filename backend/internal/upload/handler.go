@@ -2,6 +2,7 @@ package upload
 
 import (
 	"fmt"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Aolakije/City-Buzz/internal/models"
 	"github.com/Aolakije/City-Buzz/pkg/config"
 	"github.com/Aolakije/City-Buzz/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -16,10 +18,11 @@ import (
 )
 
 type Handler struct {
+	repo   *Repository
 	config *config.Config
 }
 
-func NewHandler(cfg *config.Config) *Handler {
+func NewHandler(repo *Repository, cfg *config.Config) *Handler {
 	// Create upload directory if it doesn't exist
 	uploadDir := "./uploads"
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
@@ -27,6 +30,7 @@ func NewHandler(cfg *config.Config) *Handler {
 	}
 
 	return &Handler{
+		repo:   repo,
 		config: cfg,
 	}
 }
@@ -44,14 +48,65 @@ func (h *Handler) UploadEventImage(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "No image file provided")
 	}
 
+	imageURL, filename, saveErr := h.saveImage(c, file)
+	if saveErr != nil {
+		return utils.ErrorResponse(c, saveErr.Code, saveErr.Message)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Image uploaded successfully", fiber.Map{
+		"url":      imageURL,
+		"filename": filename,
+		"size":     file.Size,
+	})
+}
+
+// UploadChatAttachment handles upload of an image to send in a conversation.
+// The upload is recorded so that only its owner can attach it to a message.
+// POST /api/v1/upload/chat-attachment
+func (h *Handler) UploadChatAttachment(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "No file provided")
+	}
+
+	fileURL, filename, saveErr := h.saveImage(c, file)
+	if saveErr != nil {
+		return utils.ErrorResponse(c, saveErr.Code, saveErr.Message)
+	}
+
+	upload := &models.Upload{
+		UserID:      userID,
+		URL:         fileURL,
+		Filename:    filename,
+		ContentType: imageContentType(file),
+		Size:        file.Size,
+	}
+	if err := h.repo.Create(c.Context(), upload); err != nil {
+		log.Printf("Save upload error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to save file")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, "File uploaded successfully", fiber.Map{
+		"upload": upload,
+	})
+}
+
+// saveImage validates an uploaded image and stores it under ./uploads/YYYY/MM.
+// It returns its public URL and file name.
+func (h *Handler) saveImage(c *fiber.Ctx, file *multipart.FileHeader) (string, string, *fiber.Error) {
 	// Validate file type
 	if !isValidImageType(file) {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid file type. Only JPEG, PNG, GIF, and WebP are allowed")
+		return "", "", fiber.NewError(fiber.StatusBadRequest, "Invalid file type. Only JPEG, PNG, GIF, and WebP are allowed")
 	}
 
 	// Validate file size (max 10MB)
 	if file.Size > 10*1024*1024 {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File size exceeds 10MB limit")
+		return "", "", fiber.NewError(fiber.StatusBadRequest, "File size exceeds 10MB limit")
 	}
 
 	// Generate unique filename
@@ -62,7 +117,7 @@ func (h *Handler) UploadEventImage(c *fiber.Ctx) error {
 	now := time.Now()
 	subDir := filepath.Join("./uploads", fmt.Sprintf("%d/%02d", now.Year(), now.Month()))
 	if err := os.MkdirAll(subDir, 0755); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create upload directory")
+		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Failed to create upload directory")
 	}
 
 	// Full file path
@@ -70,7 +125,7 @@ func (h *Handler) UploadEventImage(c *fiber.Ctx) error {
 
 	// Save file
 	if err := c.SaveFile(file, filePath); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to save image")
+		return "", "", fiber.NewError(fiber.StatusInternalServerError, "Failed to save image")
 	}
 
 	// Generate URL - use backend URL (port 8080)
@@ -80,13 +135,7 @@ func (h *Handler) UploadEventImage(c *fiber.Ctx) error {
 		baseURL = strings.Replace(h.config.CORS.FrontendURL, "5173", "8080", 1)
 	}
 
-	imageURL := fmt.Sprintf("%s/uploads/%d/%02d/%s", baseURL, now.Year(), now.Month(), filename)
-
-	return utils.SuccessResponse(c, fiber.StatusOK, "Image uploaded successfully", fiber.Map{
-		"url":      imageURL,
-		"filename": filename,
-		"size":     file.Size,
-	})
+	return fmt.Sprintf("%s/uploads/%d/%02d/%s", baseURL, now.Year(), now.Month(), filename), filename, nil
 }
 
 func isValidImageType(file *multipart.FileHeader) bool {
@@ -125,3 +174,18 @@ func isValidImageType(file *multipart.FileHeader) bool {
 
 	return false
 }
+
+// imageContentType returns the MIME type of a validated image from its extension
+func imageContentType(file *multipart.FileHeader) string {
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,34 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// Create records an uploaded file
+func (r *Repository) Create(ctx context.Context, upload *models.Upload) error {
+	query := `
+		INSERT INTO uploads (user_id, url, filename, content_type, size)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, upload.UserID, upload.URL, upload.Filename, upload.ContentType, upload.Size).
+		Scan(&upload.ID, &upload.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package user
+
+import (
+	"log"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// FollowUser handles following a user
+// POST /api/v1/users/:username/follow
+func (h *Handler) FollowUser(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	username := c.Params("username")
+
+	if err := h.service.FollowUser(c.Context(), userID, username); err != nil {
+		switch err.Error() {
+		case "user not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		case "you cannot follow yourself":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot follow yourself")
+		case "you cannot follow this user":
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot follow this user")
+		}
+		log.Printf("Follow user error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to follow user")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "User followed", nil)
+}
+
+// UnfollowUser handles unfollowing a user
+// DELETE /api/v1/users/:username/follow
+func (h *Handler) UnfollowUser(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	username := c.Params("username")
+
+	if err := h.service.UnfollowUser(c.Context(), userID, username); err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Unfollow user error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unfollow user")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "User unfollowed", nil)
+}
+
+// GetFollowers handles follower list retrieval
+// GET /api/v1/users/:username/followers
+func (h *Handler) GetFollowers(c *fiber.Ctx) error {
+	users, err := h.service.GetFollowers(c.Context(), c.Params("username"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Get followers error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get followers")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"users": users,
+	})
+}
+
+// GetFollowing handles followed accounts retrieval
+// GET /api/v1/users/:username/following
+func (h *Handler) GetFollowing(c *fiber.Ctx) error {
+	users, err := h.service.GetFollowing(c.Context(), c.Params("username"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Get following error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get followed users")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"users": users,
+	})
+}
+
+// BlockUser handles blocking a user
+// POST /api/v1/users/:username/block
+func (h *Handler) BlockUser(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.service.BlockUser(c.Context(), userID, c.Params("username")); err != nil {
+		switch err.Error() {
+		case "user not found":
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		case "you cannot block yourself":
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "You cannot block yourself")
+		}
+		log.Printf("Block user error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to block user")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "User blocked", nil)
+}
+
+// UnblockUser handles unblocking a user
+// DELETE /api/v1/users/:username/block
+func (h *Handler) UnblockUser(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := h.service.UnblockUser(c.Context(), userID, c.Params("username")); err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Unblock user error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unblock user")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "User unblocked", nil)
+}
+
+// GetBlockedUsers handles retrieval of the accounts the user blocked
+// GET /api/v1/users/me/blocks
+func (h *Handler) GetBlockedUsers(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	users, err := h.service.GetBlockedUsers(c.Context(), userID)
+	if err != nil {
+		log.Printf("Get blocked users error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get blocked users")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", fiber.Map{
+		"users": users,
+	})
+}
+
+// GetPrivacy handles retrieval of the user's privacy settings
+// GET /api/v1/users/me/privacy
+func (h *Handler) GetPrivacy(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	settings, err := h.service.GetPrivacy(c.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Get privacy settings error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get privacy settings")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "", settings)
+}
+
+// UpdatePrivacy handles changes to the user's privacy settings
+// PUT /api/v1/users/me/privacy
+func (h *Handler) UpdatePrivacy(c *fiber.Ctx) error {
+	userID, err := utils.ParseUUID(c.Locals("userID").(string))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req models.PrivacySettings
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	settings, err := h.service.UpdatePrivacy(c.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found")
+		}
+		log.Printf("Update privacy settings error: %v", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update privacy settings")
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, "Privacy settings updated", settings)
+}
@@ -0,0 +1,247 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) *Repository {
+	return &Repository{db: db}
+}
+
+// GetUserIDByUsername looks up an active user's ID by username
+func (r *Repository) GetUserIDByUsername(ctx context.Context, username string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	query := `SELECT id FROM users WHERE username = $1 AND is_active = true`
+
+	err := r.db.QueryRow(ctx, query, username).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, fmt.Errorf("user not found")
+		}
+		return uuid.Nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return userID, nil
+}
+
+// Follow makes follower follow the given user. Following twice is a no-op; it
+// reports whether the follow is new.
+func (r *Repository) Follow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO follows (follower_id, following_id)
+		VALUES ($1, $2)
+		ON CONFLICT (follower_id, following_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, followerID, followingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// Unfollow removes a follow relationship. Unfollowing a user that isn't followed is a no-op.
+func (r *Repository) Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND following_id = $2`
+
+	if _, err := r.db.Exec(ctx, query, followerID, followingID); err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+// GetFollowers retrieves the users following the given user
+func (r *Repository) GetFollowers(ctx context.Context, userID uuid.UUID) ([]models.UserResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM follows f
+		JOIN users u ON f.follower_id = u.id
+		WHERE f.following_id = $1 AND u.is_active = true
+		ORDER BY f.created_at DESC
+	`
+
+	return r.queryUsers(ctx, query, userID)
+}
+
+// GetFollowing retrieves the users the given user follows
+func (r *Repository) GetFollowing(ctx context.Context, userID uuid.UUID) ([]models.UserResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM follows f
+		JOIN users u ON f.following_id = u.id
+		WHERE f.follower_id = $1 AND u.is_active = true
+		ORDER BY f.created_at DESC
+	`
+
+	return r.queryUsers(ctx, query, userID)
+}
+
+// queryUsers runs a user listing query and scans the results
+func (r *Repository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]models.UserResponse, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.UserResponse{}
+	for rows.Next() {
+		var user models.UserResponse
+		if err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.AvatarURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// Block makes blocker block the given user, removing the follows between them.
+// Blocking twice is a no-op.
+func (r *Repository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO user_blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	unfollowQuery := `
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND following_id = $2) OR (follower_id = $2 AND following_id = $1)
+	`
+	if _, err := tx.Exec(ctx, unfollowQuery, blockerID, blockedID); err != nil {
+		return fmt.Errorf("failed to remove follows: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit block: %w", err)
+	}
+
+	return nil
+}
+
+// Unblock removes a block. Unblocking a user that isn't blocked is a no-op.
+func (r *Repository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+
+	if _, err := r.db.Exec(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+
+	return nil
+}
+
+// GetBlocked retrieves the users the given user blocked
+func (r *Repository) GetBlocked(ctx context.Context, userID uuid.UUID) ([]models.UserResponse, error) {
+	query := `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.avatar_url
+		FROM user_blocks b
+		JOIN users u ON b.blocked_id = u.id
+		WHERE b.blocker_id = $1 AND u.is_active = true
+		ORDER BY b.created_at DESC
+	`
+
+	return r.queryUsers(ctx, query, userID)
+}
+
+// IsBlocked reports whether either user blocked the other
+func (r *Repository) IsBlocked(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+
+	var blocked bool
+	if err := r.db.QueryRow(ctx, query, userID, otherID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check blocks: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// GetPrivacy retrieves a user's privacy settings
+func (r *Repository) GetPrivacy(ctx context.Context, userID uuid.UUID) (*models.PrivacySettings, error) {
+	query := `SELECT message_privacy FROM users WHERE id = $1`
+
+	var settings models.PrivacySettings
+	err := r.db.QueryRow(ctx, query, userID).Scan(&settings.MessagePrivacy)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdatePrivacy saves a user's privacy settings
+func (r *Repository) UpdatePrivacy(ctx context.Context, userID uuid.UUID, settings *models.PrivacySettings) error {
+	query := `UPDATE users SET message_privacy = $2 WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, userID, settings.MessagePrivacy)
+	if err != nil {
+		return fmt.Errorf("failed to update privacy settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// messagePermission gathers what decides whether a user can message another
+type messagePermission struct {
+	IsActive       bool
+	MessagePrivacy string
+	FollowsSender  bool // The recipient follows the sender
+	Blocked        bool // Either user blocked the other
+}
+
+// GetMessagePermission retrieves what decides whether sender can message recipient
+func (r *Repository) GetMessagePermission(ctx context.Context, senderID, recipientID uuid.UUID) (*messagePermission, error) {
+	query := `
+		SELECT u.is_active, u.message_privacy,
+		       EXISTS (SELECT 1 FROM follows WHERE follower_id = u.id AND following_id = $1),
+		       EXISTS (
+		           SELECT 1 FROM user_blocks
+		           WHERE (blocker_id = u.id AND blocked_id = $1) OR (blocker_id = $1 AND blocked_id = u.id)
+		       )
+		FROM users u
+		WHERE u.id = $2
+	`
+
+	var p messagePermission
+	err := r.db.QueryRow(ctx, query, senderID, recipientID).Scan(&p.IsActive, &p.MessagePrivacy, &p.FollowsSender, &p.Blocked)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to check message permission: %w", err)
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,157 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aolakije/City-Buzz/internal/models"
+	"github.com/Aolakije/City-Buzz/internal/notification"
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo          *Repository
+	notifications *notification.Service
+}
+
+func NewService(repo *Repository, notifications *notification.Service) *Service {
+	return &Service{
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// GetUserIDByUsername looks up an active user's ID by username
+func (s *Service) GetUserIDByUsername(ctx context.Context, username string) (uuid.UUID, error) {
+	return s.repo.GetUserIDByUsername(ctx, username)
+}
+
+// FollowUser makes the user follow the account with the given username
+func (s *Service) FollowUser(ctx context.Context, followerID uuid.UUID, username string) error {
+	followingID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if followingID == followerID {
+		return fmt.Errorf("you cannot follow yourself")
+	}
+
+	blocked, err := s.repo.IsBlocked(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return fmt.Errorf("you cannot follow this user")
+	}
+
+	followed, err := s.repo.Follow(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	if followed {
+		s.notifications.Notify(ctx, followingID, followerID, models.NotificationFollow, notification.UserTarget(followingID))
+	}
+
+	return nil
+}
+
+// UnfollowUser makes the user stop following the account with the given username
+func (s *Service) UnfollowUser(ctx context.Context, followerID uuid.UUID, username string) error {
+	followingID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Unfollow(ctx, followerID, followingID)
+}
+
+// GetFollowers retrieves the followers of the account with the given username
+func (s *Service) GetFollowers(ctx context.Context, username string) ([]models.UserResponse, error) {
+	userID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetFollowers(ctx, userID)
+}
+
+// GetFollowing retrieves the accounts followed by the given username
+func (s *Service) GetFollowing(ctx context.Context, username string) ([]models.UserResponse, error) {
+	userID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetFollowing(ctx, userID)
+}
+
+// BlockUser makes the user block the account with the given username
+func (s *Service) BlockUser(ctx context.Context, blockerID uuid.UUID, username string) error {
+	blockedID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if blockedID == blockerID {
+		return fmt.Errorf("you cannot block yourself")
+	}
+
+	return s.repo.Block(ctx, blockerID, blockedID)
+}
+
+// UnblockUser makes the user unblock the account with the given username
+func (s *Service) UnblockUser(ctx context.Context, blockerID uuid.UUID, username string) error {
+	blockedID, err := s.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Unblock(ctx, blockerID, blockedID)
+}
+
+// GetBlockedUsers retrieves the accounts the user blocked
+func (s *Service) GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]models.UserResponse, error) {
+	return s.repo.GetBlocked(ctx, userID)
+}
+
+// GetPrivacy retrieves the user's privacy settings
+func (s *Service) GetPrivacy(ctx context.Context, userID uuid.UUID) (*models.PrivacySettings, error) {
+	return s.repo.GetPrivacy(ctx, userID)
+}
+
+// UpdatePrivacy changes the user's privacy settings
+func (s *Service) UpdatePrivacy(ctx context.Context, userID uuid.UUID, settings *models.PrivacySettings) (*models.PrivacySettings, error) {
+	if err := s.repo.UpdatePrivacy(ctx, userID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// CanMessage checks that sender may send direct messages to recipient: the
+// recipient's account is active, neither blocked the other and the recipient's
+// message privacy lets the sender in
+func (s *Service) CanMessage(ctx context.Context, senderID, recipientID uuid.UUID) error {
+	permission, err := s.repo.GetMessagePermission(ctx, senderID, recipientID)
+	if err != nil {
+		return err
+	}
+
+	if !permission.IsActive {
+		return fmt.Errorf("user not found")
+	}
+	if permission.Blocked {
+		return fmt.Errorf("you cannot message this user")
+	}
+
+	switch permission.MessagePrivacy {
+	case models.MessagePrivacyEveryone:
+		return nil
+	case models.MessagePrivacyFollowing:
+		if permission.FollowsSender {
+			return nil
+		}
+	}
+	return fmt.Errorf("you cannot message this user")
+}
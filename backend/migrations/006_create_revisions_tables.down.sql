@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_comment_revisions_comment_id;
+DROP INDEX IF EXISTS idx_post_revisions_post_id;
+
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS post_revisions;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS edited_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
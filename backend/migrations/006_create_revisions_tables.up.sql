@@ -0,0 +1,29 @@
+-- User roles (moderators can review edit history and, later, moderate content)
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Edit markers
+ALTER TABLE posts ADD COLUMN edited_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMP;
+
+-- Post revisions (previous versions of a post's content)
+CREATE TABLE post_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Comment revisions (previous versions of a comment's content)
+CREATE TABLE comment_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Indexes for performance
+CREATE INDEX idx_post_revisions_post_id ON post_revisions(post_id, created_at DESC);
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions(comment_id, created_at DESC);
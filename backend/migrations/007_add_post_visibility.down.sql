@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_visibility;
+ALTER TABLE posts DROP COLUMN IF EXISTS visibility;
+
+DROP INDEX IF EXISTS idx_follows_following_id;
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,16 @@
+-- Follows table (who follows whom)
+CREATE TABLE follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    following_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (follower_id, following_id),
+    CONSTRAINT check_not_self_follow CHECK (follower_id <> following_id)
+);
+
+CREATE INDEX idx_follows_following_id ON follows(following_id);
+
+-- Post visibility: public, followers-only or private
+ALTER TABLE posts ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'followers', 'private'));
+
+CREATE INDEX idx_posts_visibility ON posts(visibility);
@@ -0,0 +1,17 @@
+DROP TRIGGER IF EXISTS post_repost_removed ON posts;
+DROP TRIGGER IF EXISTS post_repost_deleted_changed ON posts;
+DROP TRIGGER IF EXISTS post_repost_added ON posts;
+
+DROP FUNCTION IF EXISTS decrement_post_reposts();
+DROP FUNCTION IF EXISTS handle_post_repost_deleted_change();
+DROP FUNCTION IF EXISTS increment_post_reposts();
+
+DROP INDEX IF EXISTS idx_posts_unique_plain_repost;
+DROP INDEX IF EXISTS idx_posts_repost_of_id;
+
+DELETE FROM posts WHERE content = '';
+ALTER TABLE posts DROP CONSTRAINT posts_content_check;
+ALTER TABLE posts ADD CONSTRAINT posts_content_check CHECK (length(content) >= 1 AND length(content) <= 5000);
+
+ALTER TABLE posts DROP COLUMN IF EXISTS repost_count;
+ALTER TABLE posts DROP COLUMN IF EXISTS repost_of_id;
@@ -0,0 +1,58 @@
+-- Reposts and quote posts: a post may reference the post it re-shares.
+-- Plain reposts have empty content, quote posts carry the user's commentary.
+ALTER TABLE posts ADD COLUMN repost_of_id UUID REFERENCES posts(id) ON DELETE SET NULL;
+ALTER TABLE posts ADD COLUMN repost_count INT DEFAULT 0 CHECK (repost_count >= 0);
+
+ALTER TABLE posts DROP CONSTRAINT posts_content_check;
+ALTER TABLE posts ADD CONSTRAINT posts_content_check CHECK (
+    length(content) <= 5000 AND (length(content) >= 1 OR repost_of_id IS NOT NULL)
+);
+
+CREATE INDEX idx_posts_repost_of_id ON posts(repost_of_id) WHERE repost_of_id IS NOT NULL;
+
+-- A user can plainly repost a given post only once
+CREATE UNIQUE INDEX idx_posts_unique_plain_repost ON posts(user_id, repost_of_id)
+    WHERE repost_of_id IS NOT NULL AND content = '' AND is_deleted = false;
+
+-- Function to increment post repost_count
+CREATE OR REPLACE FUNCTION increment_post_reposts()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET repost_count = repost_count + 1 WHERE id = NEW.repost_of_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_repost_added AFTER INSERT ON posts
+    FOR EACH ROW WHEN (NEW.repost_of_id IS NOT NULL AND NEW.is_deleted = false)
+    EXECUTE FUNCTION increment_post_reposts();
+
+-- Function to keep repost_count in sync when a repost is soft deleted or restored
+CREATE OR REPLACE FUNCTION handle_post_repost_deleted_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_deleted THEN
+        UPDATE posts SET repost_count = repost_count - 1 WHERE id = NEW.repost_of_id;
+    ELSE
+        UPDATE posts SET repost_count = repost_count + 1 WHERE id = NEW.repost_of_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_repost_deleted_changed AFTER UPDATE OF is_deleted ON posts
+    FOR EACH ROW WHEN (NEW.repost_of_id IS NOT NULL AND OLD.is_deleted IS DISTINCT FROM NEW.is_deleted)
+    EXECUTE FUNCTION handle_post_repost_deleted_change();
+
+-- Function to decrement post repost_count when a live repost is removed
+CREATE OR REPLACE FUNCTION decrement_post_reposts()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET repost_count = repost_count - 1 WHERE id = OLD.repost_of_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_repost_removed AFTER DELETE ON posts
+    FOR EACH ROW WHEN (OLD.repost_of_id IS NOT NULL AND OLD.is_deleted = false)
+    EXECUTE FUNCTION decrement_post_reposts();
@@ -0,0 +1,21 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS poll_vote_removed ON poll_votes;
+DROP TRIGGER IF EXISTS poll_vote_added ON poll_votes;
+DROP TRIGGER IF EXISTS poll_voter_removed ON poll_voters;
+DROP TRIGGER IF EXISTS poll_voter_added ON poll_voters;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS decrement_poll_option_votes();
+DROP FUNCTION IF EXISTS increment_poll_option_votes();
+DROP FUNCTION IF EXISTS decrement_poll_voters();
+DROP FUNCTION IF EXISTS increment_poll_voters();
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_poll_votes_poll_id_user_id;
+DROP INDEX IF EXISTS idx_poll_options_poll_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_voters;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
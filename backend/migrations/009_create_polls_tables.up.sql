@@ -0,0 +1,89 @@
+-- Polls attached to posts
+CREATE TABLE polls (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    allows_multiple BOOLEAN NOT NULL DEFAULT false,
+    closes_at TIMESTAMP NOT NULL,
+    voters_count INT DEFAULT 0 CHECK (voters_count >= 0),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Poll options (2 to 6 per poll, enforced by the API)
+CREATE TABLE poll_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    text VARCHAR(100) NOT NULL CHECK (length(text) >= 1),
+    votes_count INT DEFAULT 0 CHECK (votes_count >= 0),
+    UNIQUE(poll_id, position)
+);
+
+-- One ballot per user and poll
+CREATE TABLE poll_voters (
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (poll_id, user_id)
+);
+
+-- Options chosen on each ballot
+CREATE TABLE poll_votes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    poll_id UUID NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id UUID NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(option_id, user_id)
+);
+
+-- Indexes for performance
+CREATE INDEX idx_poll_options_poll_id ON poll_options(poll_id);
+CREATE INDEX idx_poll_votes_poll_id_user_id ON poll_votes(poll_id, user_id);
+
+-- Function to increment poll voters_count
+CREATE OR REPLACE FUNCTION increment_poll_voters()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE polls SET voters_count = voters_count + 1 WHERE id = NEW.poll_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER poll_voter_added AFTER INSERT ON poll_voters
+    FOR EACH ROW EXECUTE FUNCTION increment_poll_voters();
+
+-- Function to decrement poll voters_count
+CREATE OR REPLACE FUNCTION decrement_poll_voters()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE polls SET voters_count = voters_count - 1 WHERE id = OLD.poll_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER poll_voter_removed AFTER DELETE ON poll_voters
+    FOR EACH ROW EXECUTE FUNCTION decrement_poll_voters();
+
+-- Function to increment poll option votes_count
+CREATE OR REPLACE FUNCTION increment_poll_option_votes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE poll_options SET votes_count = votes_count + 1 WHERE id = NEW.option_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER poll_vote_added AFTER INSERT ON poll_votes
+    FOR EACH ROW EXECUTE FUNCTION increment_poll_option_votes();
+
+-- Function to decrement poll option votes_count
+CREATE OR REPLACE FUNCTION decrement_poll_option_votes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE poll_options SET votes_count = votes_count - 1 WHERE id = OLD.option_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER poll_vote_removed AFTER DELETE ON poll_votes
+    FOR EACH ROW EXECUTE FUNCTION decrement_poll_option_votes();
@@ -0,0 +1,92 @@
+-- Recreate the likes tables from "like" reactions
+CREATE TABLE post_likes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(post_id, user_id)
+);
+
+CREATE TABLE comment_likes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(comment_id, user_id)
+);
+
+INSERT INTO post_likes (post_id, user_id, created_at)
+SELECT post_id, user_id, created_at FROM post_reactions WHERE reaction = 'like';
+
+INSERT INTO comment_likes (comment_id, user_id, created_at)
+SELECT comment_id, user_id, created_at FROM comment_reactions WHERE reaction = 'like';
+
+CREATE INDEX idx_post_likes_post_id ON post_likes(post_id);
+CREATE INDEX idx_post_likes_user_id ON post_likes(user_id);
+
+CREATE INDEX idx_comment_likes_comment_id ON comment_likes(comment_id);
+CREATE INDEX idx_comment_likes_user_id ON comment_likes(user_id);
+
+CREATE OR REPLACE FUNCTION increment_post_likes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET likes_count = likes_count + 1 WHERE id = NEW.post_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_like_added AFTER INSERT ON post_likes
+    FOR EACH ROW EXECUTE FUNCTION increment_post_likes();
+
+CREATE OR REPLACE FUNCTION decrement_post_likes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET likes_count = likes_count - 1 WHERE id = OLD.post_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_like_removed AFTER DELETE ON post_likes
+    FOR EACH ROW EXECUTE FUNCTION decrement_post_likes();
+
+CREATE OR REPLACE FUNCTION increment_comment_likes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE comments SET likes_count = likes_count + 1 WHERE id = NEW.comment_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comment_like_added AFTER INSERT ON comment_likes
+    FOR EACH ROW EXECUTE FUNCTION increment_comment_likes();
+
+CREATE OR REPLACE FUNCTION decrement_comment_likes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE comments SET likes_count = likes_count - 1 WHERE id = OLD.comment_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comment_like_removed AFTER DELETE ON comment_likes
+    FOR EACH ROW EXECUTE FUNCTION decrement_comment_likes();
+
+-- Drop reactions
+DROP TRIGGER IF EXISTS comment_reaction_changed ON comment_reactions;
+DROP TRIGGER IF EXISTS comment_reaction_removed ON comment_reactions;
+DROP TRIGGER IF EXISTS comment_reaction_added ON comment_reactions;
+DROP TRIGGER IF EXISTS post_reaction_changed ON post_reactions;
+DROP TRIGGER IF EXISTS post_reaction_removed ON post_reactions;
+DROP TRIGGER IF EXISTS post_reaction_added ON post_reactions;
+DROP TRIGGER IF EXISTS update_comment_reactions_updated_at ON comment_reactions;
+DROP TRIGGER IF EXISTS update_post_reactions_updated_at ON post_reactions;
+
+DROP FUNCTION IF EXISTS handle_comment_reaction_change();
+DROP FUNCTION IF EXISTS handle_post_reaction_change();
+DROP FUNCTION IF EXISTS adjust_reaction_count(JSONB, VARCHAR, INT);
+
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS post_reactions;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS reaction_counts;
+ALTER TABLE posts DROP COLUMN IF EXISTS reaction_counts;
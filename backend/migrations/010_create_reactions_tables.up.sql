@@ -0,0 +1,146 @@
+-- Emoji reactions replace the binary likes on posts and comments.
+-- The allowed reaction types are configured in the API (REACTION_TYPES).
+
+-- Per-type reaction counts; likes_count keeps counting the "like" reaction
+ALTER TABLE posts ADD COLUMN reaction_counts JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE comments ADD COLUMN reaction_counts JSONB NOT NULL DEFAULT '{}';
+
+-- Post reactions table (one reaction per user and post)
+CREATE TABLE post_reactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(post_id, user_id)
+);
+
+-- Comment reactions table (one reaction per user and comment)
+CREATE TABLE comment_reactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(comment_id, user_id)
+);
+
+-- Convert existing likes into "like" reactions (before the counting triggers exist,
+-- since likes_count already accounts for them)
+INSERT INTO post_reactions (post_id, user_id, reaction, created_at, updated_at)
+SELECT post_id, user_id, 'like', created_at, created_at FROM post_likes;
+
+INSERT INTO comment_reactions (comment_id, user_id, reaction, created_at, updated_at)
+SELECT comment_id, user_id, 'like', created_at, created_at FROM comment_likes;
+
+UPDATE posts SET reaction_counts = jsonb_build_object('like', likes_count) WHERE likes_count > 0;
+UPDATE comments SET reaction_counts = jsonb_build_object('like', likes_count) WHERE likes_count > 0;
+
+-- Drop the old likes tables and their triggers
+DROP TRIGGER IF EXISTS comment_like_removed ON comment_likes;
+DROP TRIGGER IF EXISTS comment_like_added ON comment_likes;
+DROP TRIGGER IF EXISTS post_like_removed ON post_likes;
+DROP TRIGGER IF EXISTS post_like_added ON post_likes;
+
+DROP FUNCTION IF EXISTS decrement_comment_likes();
+DROP FUNCTION IF EXISTS increment_comment_likes();
+DROP FUNCTION IF EXISTS decrement_post_likes();
+DROP FUNCTION IF EXISTS increment_post_likes();
+
+DROP TABLE IF EXISTS comment_likes;
+DROP TABLE IF EXISTS post_likes;
+
+-- Indexes for performance
+CREATE INDEX idx_post_reactions_post_id ON post_reactions(post_id);
+CREATE INDEX idx_post_reactions_user_id ON post_reactions(user_id);
+
+CREATE INDEX idx_comment_reactions_comment_id ON comment_reactions(comment_id);
+CREATE INDEX idx_comment_reactions_user_id ON comment_reactions(user_id);
+
+-- Trigger to update post_reactions.updated_at
+CREATE TRIGGER update_post_reactions_updated_at BEFORE UPDATE ON post_reactions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Trigger to update comment_reactions.updated_at
+CREATE TRIGGER update_comment_reactions_updated_at BEFORE UPDATE ON comment_reactions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Helper to add delta to one reaction type in a counts object
+CREATE OR REPLACE FUNCTION adjust_reaction_count(counts JSONB, reaction VARCHAR, delta INT)
+RETURNS JSONB AS $$
+BEGIN
+    RETURN jsonb_set(
+        counts,
+        ARRAY[reaction],
+        to_jsonb(GREATEST(COALESCE((counts->>reaction)::INT, 0) + delta, 0))
+    );
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Function to keep post reaction counts in sync
+CREATE OR REPLACE FUNCTION handle_post_reaction_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE posts SET
+            reaction_counts = adjust_reaction_count(reaction_counts, OLD.reaction, -1),
+            likes_count = likes_count - CASE WHEN OLD.reaction = 'like' THEN 1 ELSE 0 END
+        WHERE id = OLD.post_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE posts SET
+            reaction_counts = adjust_reaction_count(reaction_counts, NEW.reaction, 1),
+            likes_count = likes_count + CASE WHEN NEW.reaction = 'like' THEN 1 ELSE 0 END
+        WHERE id = NEW.post_id;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_reaction_added AFTER INSERT ON post_reactions
+    FOR EACH ROW EXECUTE FUNCTION handle_post_reaction_change();
+
+CREATE TRIGGER post_reaction_removed AFTER DELETE ON post_reactions
+    FOR EACH ROW EXECUTE FUNCTION handle_post_reaction_change();
+
+CREATE TRIGGER post_reaction_changed AFTER UPDATE ON post_reactions
+    FOR EACH ROW WHEN (OLD.reaction IS DISTINCT FROM NEW.reaction)
+    EXECUTE FUNCTION handle_post_reaction_change();
+
+-- Function to keep comment reaction counts in sync
+CREATE OR REPLACE FUNCTION handle_comment_reaction_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE comments SET
+            reaction_counts = adjust_reaction_count(reaction_counts, OLD.reaction, -1),
+            likes_count = likes_count - CASE WHEN OLD.reaction = 'like' THEN 1 ELSE 0 END
+        WHERE id = OLD.comment_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE comments SET
+            reaction_counts = adjust_reaction_count(reaction_counts, NEW.reaction, 1),
+            likes_count = likes_count + CASE WHEN NEW.reaction = 'like' THEN 1 ELSE 0 END
+        WHERE id = NEW.comment_id;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comment_reaction_added AFTER INSERT ON comment_reactions
+    FOR EACH ROW EXECUTE FUNCTION handle_comment_reaction_change();
+
+CREATE TRIGGER comment_reaction_removed AFTER DELETE ON comment_reactions
+    FOR EACH ROW EXECUTE FUNCTION handle_comment_reaction_change();
+
+CREATE TRIGGER comment_reaction_changed AFTER UPDATE ON comment_reactions
+    FOR EACH ROW WHEN (OLD.reaction IS DISTINCT FROM NEW.reaction)
+    EXECUTE FUNCTION handle_comment_reaction_change();
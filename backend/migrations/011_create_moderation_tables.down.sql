@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS moderation_actions;
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE events DROP COLUMN IF EXISTS is_hidden;
+ALTER TABLE comments DROP COLUMN IF EXISTS is_hidden;
+ALTER TABLE posts DROP COLUMN IF EXISTS is_hidden;
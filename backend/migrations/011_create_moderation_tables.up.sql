@@ -0,0 +1,39 @@
+-- Content hidden by moderators stays in the database but is no longer shown
+ALTER TABLE posts ADD COLUMN is_hidden BOOLEAN DEFAULT false;
+ALTER TABLE comments ADD COLUMN is_hidden BOOLEAN DEFAULT false;
+ALTER TABLE events ADD COLUMN is_hidden BOOLEAN DEFAULT false;
+
+-- Reports filed by users against posts, comments, events or other users
+CREATE TABLE reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('post', 'comment', 'event', 'user')),
+    target_id UUID NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate_speech', 'violence', 'nudity', 'misinformation', 'other')),
+    details TEXT CHECK (details IS NULL OR length(details) <= 1000),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed', 'actioned')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- A user can only have one open report per target
+CREATE UNIQUE INDEX idx_reports_pending_unique ON reports(reporter_id, target_type, target_id)
+    WHERE status = 'pending';
+CREATE INDEX idx_reports_status ON reports(status, created_at);
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+
+-- Audit trail of moderator decisions
+CREATE TABLE moderation_actions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    moderator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    report_id UUID REFERENCES reports(id) ON DELETE SET NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('post', 'comment', 'event', 'user')),
+    target_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('dismiss', 'hide', 'delete', 'suspend')),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_actions_created_at ON moderation_actions(created_at DESC);
+CREATE INDEX idx_moderation_actions_target ON moderation_actions(target_type, target_id);
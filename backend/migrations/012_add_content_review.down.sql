@@ -0,0 +1,37 @@
+DELETE FROM moderation_actions WHERE action IN ('approve', 'reject');
+ALTER TABLE moderation_actions DROP CONSTRAINT moderation_actions_action_check;
+ALTER TABLE moderation_actions ADD CONSTRAINT moderation_actions_action_check
+    CHECK (action IN ('dismiss', 'hide', 'delete', 'suspend'));
+
+DROP TRIGGER IF EXISTS post_comment_reviewed ON comments;
+DROP FUNCTION IF EXISTS update_post_comments_on_review();
+
+-- Restore the comment counting triggers from 002
+CREATE OR REPLACE FUNCTION increment_post_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET comments_count = comments_count + 1 WHERE id = NEW.post_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION decrement_post_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE posts SET comments_count = comments_count - 1 WHERE id = OLD.post_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_comments_user_id_created_at;
+DROP INDEX IF EXISTS idx_posts_user_id_created_at;
+DROP INDEX IF EXISTS idx_events_is_held;
+DROP INDEX IF EXISTS idx_comments_is_held;
+DROP INDEX IF EXISTS idx_posts_is_held;
+
+ALTER TABLE events DROP COLUMN IF EXISTS held_reasons;
+ALTER TABLE events DROP COLUMN IF EXISTS is_held;
+ALTER TABLE comments DROP COLUMN IF EXISTS held_reasons;
+ALTER TABLE comments DROP COLUMN IF EXISTS is_held;
+ALTER TABLE posts DROP COLUMN IF EXISTS held_reasons;
+ALTER TABLE posts DROP COLUMN IF EXISTS is_held;
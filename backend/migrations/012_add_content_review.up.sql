@@ -0,0 +1,59 @@
+-- Content flagged by the automatic filters is held for review instead of being
+-- published. held_reasons lists the checks that flagged it.
+ALTER TABLE posts ADD COLUMN is_held BOOLEAN DEFAULT false;
+ALTER TABLE posts ADD COLUMN held_reasons TEXT[];
+ALTER TABLE comments ADD COLUMN is_held BOOLEAN DEFAULT false;
+ALTER TABLE comments ADD COLUMN held_reasons TEXT[];
+ALTER TABLE events ADD COLUMN is_held BOOLEAN DEFAULT false;
+ALTER TABLE events ADD COLUMN held_reasons TEXT[];
+
+CREATE INDEX idx_posts_is_held ON posts(created_at) WHERE is_held = true;
+CREATE INDEX idx_comments_is_held ON comments(created_at) WHERE is_held = true;
+CREATE INDEX idx_events_is_held ON events(created_at) WHERE is_held = true;
+
+-- Indexes backing the repeated-content and rate checks
+CREATE INDEX idx_posts_user_id_created_at ON posts(user_id, created_at DESC);
+CREATE INDEX idx_comments_user_id_created_at ON comments(user_id, created_at DESC);
+
+-- Held comments stay out of the post's public comments_count until approved
+CREATE OR REPLACE FUNCTION increment_post_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_held = false THEN
+        UPDATE posts SET comments_count = comments_count + 1 WHERE id = NEW.post_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION decrement_post_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.is_held = false THEN
+        UPDATE posts SET comments_count = comments_count - 1 WHERE id = OLD.post_id;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Approving a held comment counts it; a flagged edit uncounts it again.
+-- Rejected comments are soft-deleted while still held, so they never count.
+CREATE OR REPLACE FUNCTION update_post_comments_on_review()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.is_held = true AND NEW.is_held = false THEN
+        UPDATE posts SET comments_count = comments_count + 1 WHERE id = NEW.post_id;
+    ELSIF OLD.is_held = false AND NEW.is_held = true THEN
+        UPDATE posts SET comments_count = comments_count - 1 WHERE id = NEW.post_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_comment_reviewed AFTER UPDATE OF is_held ON comments
+    FOR EACH ROW EXECUTE FUNCTION update_post_comments_on_review();
+
+-- Moderators approve or reject held content
+ALTER TABLE moderation_actions DROP CONSTRAINT moderation_actions_action_check;
+ALTER TABLE moderation_actions ADD CONSTRAINT moderation_actions_action_check
+    CHECK (action IN ('dismiss', 'hide', 'delete', 'suspend', 'approve', 'reject'));
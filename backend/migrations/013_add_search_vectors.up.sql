@@ -0,0 +1,27 @@
+-- Full-text search over posts, user-created events and user profiles.
+-- Text is indexed with both the French and English configurations so that
+-- stemming works whichever language a user writes in.
+
+ALTER TABLE posts ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('french', coalesce(content, '')) ||
+    to_tsvector('english', coalesce(content, ''))
+) STORED;
+
+ALTER TABLE events ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('french', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(city, '') || ' ' || coalesce(location, '')), 'B') ||
+    setweight(to_tsvector('french', coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+
+-- Names are indexed without stemming
+ALTER TABLE users ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(username, '') || ' ' || coalesce(first_name, '') || ' ' || coalesce(last_name, '')), 'A') ||
+    setweight(to_tsvector('french', coalesce(bio, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(bio, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_posts_search_vector ON posts USING GIN (search_vector);
+CREATE INDEX idx_events_search_vector ON events USING GIN (search_vector) WHERE source = 'user';
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
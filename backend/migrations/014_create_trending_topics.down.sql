@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS trending_topics;
+
+DROP INDEX IF EXISTS idx_posts_city_created_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS city;
@@ -0,0 +1,18 @@
+-- Posts can be tagged with the city they are about, used to compute trends per city
+ALTER TABLE posts ADD COLUMN city VARCHAR(100);
+CREATE INDEX idx_posts_city_created_at ON posts(LOWER(city), created_at DESC) WHERE city IS NOT NULL;
+
+-- Trending hashtags and keywords, recomputed periodically by a background job.
+-- city is stored lowercased; the empty string holds trends across all cities.
+CREATE TABLE trending_topics (
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('hashtag', 'keyword')),
+    topic VARCHAR(100) NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    post_count INT NOT NULL,
+    author_count INT NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (city, kind, topic)
+);
+
+CREATE INDEX idx_trending_topics_city_score ON trending_topics(city, score DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS link_previews;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS link_url;
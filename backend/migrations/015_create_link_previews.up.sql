@@ -0,0 +1,14 @@
+-- First URL found in a post, used to attach a link preview
+ALTER TABLE posts ADD COLUMN link_url TEXT;
+
+-- Cached OpenGraph / Twitter Card metadata, shared by every post linking to the same URL
+CREATE TABLE link_previews (
+    url TEXT PRIMARY KEY,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('ok', 'failed')),
+    title VARCHAR(300),
+    description VARCHAR(1000),
+    image_url TEXT,
+    site_name VARCHAR(200),
+    fetched_at TIMESTAMP NOT NULL DEFAULT NOW(), -- Last successful fetch, or first failure
+    last_attempt_at TIMESTAMP NOT NULL DEFAULT NOW() -- Last fetch, successful or not, for backing off
+);
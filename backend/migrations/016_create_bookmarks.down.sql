@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS saved_articles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    article_url TEXT NOT NULL,
+    article_title TEXT NOT NULL,
+    article_image TEXT NOT NULL,
+    article_source TEXT NOT NULL,
+    saved_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, article_url)
+);
+
+CREATE INDEX idx_saved_articles_user_id ON saved_articles(user_id);
+CREATE INDEX idx_saved_articles_saved_at ON saved_articles(saved_at DESC);
+
+INSERT INTO saved_articles (id, user_id, article_url, article_title, article_image, article_source, saved_at)
+SELECT id, user_id, article_url, article_title, article_image, article_source, saved_at
+FROM bookmarks
+WHERE item_type = 'article';
+
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_collections;
@@ -0,0 +1,46 @@
+-- Folders users can sort their bookmarks into
+CREATE TABLE bookmark_collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, name)
+);
+
+CREATE INDEX idx_bookmark_collections_user_id ON bookmark_collections(user_id);
+
+CREATE TRIGGER update_bookmark_collections_updated_at BEFORE UPDATE ON bookmark_collections
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Saved posts, events and news articles; replaces saved_articles
+CREATE TABLE bookmarks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_type VARCHAR(10) NOT NULL CHECK (item_type IN ('post', 'event', 'article')),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE,
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    article_url TEXT,
+    article_title TEXT,
+    article_image TEXT,
+    article_source TEXT,
+    collection_id UUID REFERENCES bookmark_collections(id) ON DELETE SET NULL,
+    saved_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (
+        (item_type = 'post' AND post_id IS NOT NULL AND event_id IS NULL AND article_url IS NULL) OR
+        (item_type = 'event' AND event_id IS NOT NULL AND post_id IS NULL AND article_url IS NULL) OR
+        (item_type = 'article' AND article_url IS NOT NULL AND post_id IS NULL AND event_id IS NULL)
+    )
+);
+
+CREATE UNIQUE INDEX idx_bookmarks_user_post ON bookmarks(user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_bookmarks_user_event ON bookmarks(user_id, event_id) WHERE event_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_bookmarks_user_article ON bookmarks(user_id, article_url) WHERE article_url IS NOT NULL;
+CREATE INDEX idx_bookmarks_user_saved_at ON bookmarks(user_id, saved_at DESC);
+CREATE INDEX idx_bookmarks_collection_id ON bookmarks(collection_id);
+
+INSERT INTO bookmarks (id, user_id, item_type, article_url, article_title, article_image, article_source, saved_at)
+SELECT id, user_id, 'article', article_url, article_title, article_image, article_source, saved_at
+FROM saved_articles;
+
+DROP TABLE saved_articles;
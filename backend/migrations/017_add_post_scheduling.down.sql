@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_posts_user_unpublished;
+DROP INDEX IF EXISTS idx_posts_scheduled_at;
+
+-- Unpublished posts have no place once scheduling is gone
+DELETE FROM posts WHERE status <> 'published';
+
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_scheduled_at_check,
+    DROP COLUMN IF EXISTS scheduled_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+-- Posts can be kept as drafts or scheduled to be published later
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'scheduled', 'published')),
+    ADD COLUMN scheduled_at TIMESTAMP,
+    ADD CONSTRAINT posts_scheduled_at_check CHECK (status <> 'scheduled' OR scheduled_at IS NOT NULL);
+
+-- Used by the scheduler to find posts that are due
+CREATE INDEX idx_posts_scheduled_at ON posts(scheduled_at) WHERE status = 'scheduled';
+
+-- Used to list a user's drafts and scheduled posts
+CREATE INDEX idx_posts_user_unpublished ON posts(user_id, updated_at DESC) WHERE status <> 'published';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_event_id_created_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS event_pinned_at,
+    DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,6 @@
+-- Posts can be attached to an event, forming its discussion thread
+ALTER TABLE posts
+    ADD COLUMN event_id UUID REFERENCES events(id) ON DELETE SET NULL,
+    ADD COLUMN event_pinned_at TIMESTAMP;
+
+CREATE INDEX idx_posts_event_id_created_at ON posts(event_id, created_at DESC) WHERE event_id IS NOT NULL;
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_events_coordinates;
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_coordinates_check,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
+
+DROP INDEX IF EXISTS idx_posts_coordinates;
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_coordinates_check,
+    DROP COLUMN IF EXISTS place_name,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
+
+DROP FUNCTION IF EXISTS geo_distance_km(DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION);
@@ -0,0 +1,35 @@
+-- Great-circle distance in kilometres between two points (haversine), shared by
+-- every geo query
+CREATE OR REPLACE FUNCTION geo_distance_km(lat1 DOUBLE PRECISION, lon1 DOUBLE PRECISION,
+                                           lat2 DOUBLE PRECISION, lon2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION AS $$
+    SELECT 2 * 6371.0 * ASIN(SQRT(LEAST(1.0,
+        POWER(SIN(RADIANS(lat2 - lat1) / 2), 2) +
+        COS(RADIANS(lat1)) * COS(RADIANS(lat2)) * POWER(SIN(RADIANS(lon2 - lon1) / 2), 2)
+    )))
+$$ LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE;
+
+-- Optional location of a post
+ALTER TABLE posts
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD COLUMN place_name VARCHAR(200),
+    ADD CONSTRAINT posts_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL) OR
+        (latitude IS NOT NULL AND longitude IS NOT NULL AND
+         latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+    );
+
+CREATE INDEX idx_posts_coordinates ON posts(latitude, longitude) WHERE latitude IS NOT NULL;
+
+-- Coordinates of an event's venue
+ALTER TABLE events
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT events_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL) OR
+        (latitude IS NOT NULL AND longitude IS NOT NULL AND
+         latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+    );
+
+CREATE INDEX idx_events_coordinates ON events(latitude, longitude) WHERE latitude IS NOT NULL;
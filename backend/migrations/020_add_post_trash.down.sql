@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS post_deleted_changed ON posts;
+DROP FUNCTION IF EXISTS handle_post_deleted_change();
+
+DROP INDEX IF EXISTS idx_posts_user_trash;
+DROP INDEX IF EXISTS idx_posts_deleted_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS deleted_by,
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,33 @@
+-- Deleted posts stay in their author's trash for a while before being purged.
+-- deleted_by is set when the author deletes the post themselves; posts removed
+-- by moderators or along with the post they repost are not restorable.
+ALTER TABLE posts
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Posts deleted before the trash existed expire from their last update
+UPDATE posts SET deleted_at = updated_at WHERE is_deleted = true;
+
+-- Used to list a user's trash and by the purge job
+CREATE INDEX idx_posts_deleted_at ON posts(deleted_at) WHERE is_deleted = true;
+CREATE INDEX idx_posts_user_trash ON posts(deleted_by, deleted_at DESC) WHERE is_deleted = true;
+
+-- Function to stamp the deletion time, unpin deleted posts from event threads
+-- and reset the deletion details when a post is restored
+CREATE OR REPLACE FUNCTION handle_post_deleted_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_deleted THEN
+        NEW.deleted_at = NOW();
+        NEW.event_pinned_at = NULL;
+    ELSE
+        NEW.deleted_at = NULL;
+        NEW.deleted_by = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_deleted_changed BEFORE UPDATE OF is_deleted ON posts
+    FOR EACH ROW WHEN (OLD.is_deleted IS DISTINCT FROM NEW.is_deleted)
+    EXECUTE FUNCTION handle_post_deleted_change();
@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION handle_post_deleted_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_deleted THEN
+        NEW.deleted_at = NOW();
+        NEW.event_pinned_at = NULL;
+    ELSE
+        NEW.deleted_at = NULL;
+        NEW.deleted_by = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_posts_user_profile;
+ALTER TABLE posts DROP COLUMN IF EXISTS profile_pinned_at;
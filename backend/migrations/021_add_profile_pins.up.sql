@@ -0,0 +1,22 @@
+-- Users can pin a few of their posts to the top of their profile
+ALTER TABLE posts ADD COLUMN profile_pinned_at TIMESTAMP;
+
+-- Used to list a user's posts on their profile, pinned posts first
+CREATE INDEX idx_posts_user_profile ON posts(user_id, profile_pinned_at DESC NULLS LAST, created_at DESC)
+    WHERE is_deleted = false;
+
+-- Deleted posts are also unpinned from their author's profile
+CREATE OR REPLACE FUNCTION handle_post_deleted_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_deleted THEN
+        NEW.deleted_at = NOW();
+        NEW.event_pinned_at = NULL;
+        NEW.profile_pinned_at = NULL;
+    ELSE
+        NEW.deleted_at = NULL;
+        NEW.deleted_by = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,31 @@
+-- In-app notifications. While a notification is unread, further actions of the
+-- same type on the same target are grouped into it ("Marie and 3 others liked
+-- your post"); the users behind them are kept in notification_actors.
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN (
+        'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled'
+    )),
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('post', 'comment', 'event', 'user')),
+    target_id UUID NOT NULL,
+    post_id UUID, -- Post of a comment target
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    -- Time of the latest grouped action, deliberately not maintained by a trigger
+    -- so that marking a notification as read does not reorder the list
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_notifications_unread_group ON notifications(user_id, type, target_type, target_id)
+    WHERE read_at IS NULL;
+CREATE INDEX idx_notifications_user_updated ON notifications(user_id, updated_at DESC, id DESC);
+
+CREATE TABLE notification_actors (
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (notification_id, actor_id)
+);
+
+CREATE INDEX idx_notification_actors_actor_id ON notification_actors(actor_id);
@@ -0,0 +1,31 @@
+-- Per-type delivery channels. Types without a row use the defaults defined in
+-- the application; turning every channel off silences a type.
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN (
+        'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled'
+    )),
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    push BOOLEAN NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, type)
+);
+
+CREATE TRIGGER update_notification_preferences_updated_at BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Email digest frequency, and when the last digest was sent
+CREATE TABLE notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    digest VARCHAR(10) NOT NULL DEFAULT 'none' CHECK (digest IN ('none', 'daily', 'weekly')),
+    digest_sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_settings_digest ON notification_settings(digest, digest_sent_at)
+    WHERE digest <> 'none';
+
+CREATE TRIGGER update_notification_settings_updated_at BEFORE UPDATE ON notification_settings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS event_reminders;
+
+DELETE FROM notification_preferences WHERE type = 'event_reminder';
+ALTER TABLE notification_preferences DROP CONSTRAINT notification_preferences_type_check;
+ALTER TABLE notification_preferences ADD CONSTRAINT notification_preferences_type_check CHECK (type IN (
+    'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled'
+));
+
+DELETE FROM notifications WHERE type = 'event_reminder';
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled'
+));
@@ -0,0 +1,23 @@
+-- Reminders sent before events start
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled', 'event_reminder'
+));
+
+ALTER TABLE notification_preferences DROP CONSTRAINT notification_preferences_type_check;
+ALTER TABLE notification_preferences ADD CONSTRAINT notification_preferences_type_check CHECK (type IN (
+    'like', 'comment', 'reply', 'mention', 'follow', 'rsvp', 'event_updated', 'event_cancelled', 'event_reminder'
+));
+
+-- Reminders already sent, so none is sent twice. The start date is part of the
+-- key: attendees of a rescheduled event are reminded of the new date.
+CREATE TABLE event_reminders (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offset_seconds INT NOT NULL, -- How long before the start the reminder was due
+    start_date TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id, offset_seconds, start_date)
+);
+
+CREATE INDEX idx_event_reminders_user_id ON event_reminders(user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS message_privacy;
+DROP TABLE IF EXISTS user_blocks;
@@ -0,0 +1,15 @@
+-- Blocked users can neither message nor follow the user who blocked them
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT check_not_self_block CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks(blocked_id);
+
+-- Who can send direct messages to a user: everyone, only the users they
+-- follow, or nobody
+ALTER TABLE users ADD COLUMN message_privacy VARCHAR(10) NOT NULL DEFAULT 'everyone'
+    CHECK (message_privacy IN ('everyone', 'following', 'nobody'));
@@ -0,0 +1,12 @@
+DELETE FROM conversations WHERE type = 'group';
+
+ALTER TABLE messages DROP COLUMN IF EXISTS subject_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
+ALTER TABLE conversation_members DROP COLUMN IF EXISTS role;
+ALTER TABLE conversations DROP COLUMN IF EXISTS event_id;
+ALTER TABLE conversations DROP COLUMN IF EXISTS created_by;
+ALTER TABLE conversations DROP COLUMN IF EXISTS avatar_url;
+ALTER TABLE conversations DROP COLUMN IF EXISTS name;
+
+ALTER TABLE conversations DROP CONSTRAINT conversations_type_check;
+ALTER TABLE conversations ADD CONSTRAINT conversations_type_check CHECK (type IN ('direct'));
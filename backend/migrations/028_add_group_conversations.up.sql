@@ -0,0 +1,21 @@
+-- Group conversations, optionally attached to an event whose attendees join
+-- and leave the group with their RSVP
+ALTER TABLE conversations DROP CONSTRAINT conversations_type_check;
+ALTER TABLE conversations ADD CONSTRAINT conversations_type_check CHECK (type IN ('direct', 'group'));
+
+ALTER TABLE conversations ADD COLUMN name VARCHAR(100);
+ALTER TABLE conversations ADD COLUMN avatar_url TEXT;
+ALTER TABLE conversations ADD COLUMN created_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE conversations ADD COLUMN event_id UUID UNIQUE REFERENCES events(id) ON DELETE SET NULL;
+
+ALTER TABLE conversation_members ADD COLUMN role VARCHAR(10) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('admin', 'member'));
+
+-- System messages record membership changes in the history. The sender is the
+-- user who made the change and the subject the member it is about; content
+-- carries the new name of a renamed group or the new role of a member.
+ALTER TABLE messages ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'text' CHECK (kind IN (
+    'text', 'group_created', 'group_updated', 'member_added', 'member_joined',
+    'member_removed', 'member_left', 'role_changed'
+));
+ALTER TABLE messages ADD COLUMN subject_id UUID REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,11 @@
+-- When each member received and read each message sent by someone else.
+-- Messages a member had already read before receipts existed have none.
+CREATE TABLE message_receipts (
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP,
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX idx_message_receipts_user_id ON message_receipts(user_id);
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +19,16 @@ type Config struct {
 	Cookie     CookieConfig
 	NewsAPI    NewsAPI
 	OpenAgenda OpenAgendaConfig
+	Reactions  ReactionsConfig
+	Filter     ContentFilterConfig
+	Trending   TrendingConfig
+	Previews   LinkPreviewConfig
+	Scheduling SchedulingConfig
+	Trash      TrashConfig
+	Mail       MailConfig
+	Digest     DigestConfig
+	Reminders  ReminderConfig
+	Push       PushConfig
 }
 
 type ServerConfig struct {
@@ -69,6 +81,90 @@ type OpenAgendaConfig struct {
 	BaseURL   string
 }
 
+// ReactionsConfig lists the reaction types users can leave on posts and comments
+type ReactionsConfig struct {
+	Types []string
+}
+
+// ContentFilterConfig tunes the automatic spam and profanity checks run on new content
+type ContentFilterConfig struct {
+	Enabled              bool
+	BlockedWords         []string // Added to the built-in French and English lists
+	MaxLinks             int
+	RepeatWindow         time.Duration
+	MaxRepeats           int
+	NewAccountAge        time.Duration
+	NewAccountMaxPerHour int
+}
+
+// TrendingConfig controls how trending topics are computed from recent posts
+type TrendingConfig struct {
+	Window          time.Duration // How far back posts are considered
+	HalfLife        time.Duration // Age at which a post counts for half as much
+	RefreshInterval time.Duration
+	Limit           int // Topics kept per city
+}
+
+// LinkPreviewConfig limits how link previews are fetched and how long they are cached
+type LinkPreviewConfig struct {
+	Enabled      bool
+	Timeout      time.Duration
+	MaxBytes     int64 // Bytes of each page read at most
+	CacheTTL     time.Duration
+	RetryAfter   time.Duration // Wait before retrying a failed fetch
+	Workers      int           // Concurrent fetches
+	QueueSize    int           // URLs waiting for a worker; more are dropped
+	AllowPrivate bool          // Allow fetching private addresses, for local development only
+}
+
+// SchedulingConfig controls the background publication of scheduled posts
+type SchedulingConfig struct {
+	Interval time.Duration // How often due posts are looked for
+}
+
+// TrashConfig controls how long deleted posts can be restored before being purged
+type TrashConfig struct {
+	Retention      time.Duration
+	PurgeInterval  time.Duration
+	PurgeBatchSize int // Posts permanently deleted per transaction
+}
+
+// MailConfig selects how emails are sent
+type MailConfig struct {
+	Driver       string // "smtp", or "file" to write emails to Dir
+	From         string
+	Dir          string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// DigestConfig controls when the daily and weekly email digests are sent
+type DigestConfig struct {
+	Interval  time.Duration // How often due digests are looked for
+	Hour      int           // Hour of the day, in server time, from which digests are sent
+	BatchSize int           // Digests sent per round at most
+}
+
+// ReminderConfig controls the reminders sent to users going to an event
+type ReminderConfig struct {
+	Offsets   []time.Duration // How long before the start of events reminders are sent
+	Interval  time.Duration   // How often due reminders are looked for
+	BatchSize int             // Reminders sent per round at most
+}
+
+// PushConfig identifies the server to Web Push services. Push is disabled
+// without a VAPID private key.
+type PushConfig struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	Subject         string        // Contact of the server operator, a mailto: or https: URL
+	TTL             time.Duration // How long push services keep undelivered messages
+	Timeout         time.Duration
+	AllowInsecure   bool // Allow http and private endpoints, for the local stand-in only
+}
+
 func Load() (*Config, error) {
 	godotenv.Load()
 
@@ -78,6 +174,90 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_EXPIRY format: %w", err)
 	}
 
+	repeatWindow, err := time.ParseDuration(getEnv("FILTER_REPEAT_WINDOW", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FILTER_REPEAT_WINDOW format: %w", err)
+	}
+
+	newAccountAge, err := time.ParseDuration(getEnv("FILTER_NEW_ACCOUNT_AGE", "72h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FILTER_NEW_ACCOUNT_AGE format: %w", err)
+	}
+
+	trendingWindow, err := time.ParseDuration(getEnv("TRENDING_WINDOW", "48h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRENDING_WINDOW format: %w", err)
+	}
+
+	trendingHalfLife, err := time.ParseDuration(getEnv("TRENDING_HALF_LIFE", "6h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRENDING_HALF_LIFE format: %w", err)
+	}
+
+	trendingRefresh, err := time.ParseDuration(getEnv("TRENDING_REFRESH_INTERVAL", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRENDING_REFRESH_INTERVAL format: %w", err)
+	}
+
+	previewTimeout, err := time.ParseDuration(getEnv("LINK_PREVIEW_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LINK_PREVIEW_TIMEOUT format: %w", err)
+	}
+
+	previewCacheTTL, err := time.ParseDuration(getEnv("LINK_PREVIEW_CACHE_TTL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LINK_PREVIEW_CACHE_TTL format: %w", err)
+	}
+
+	previewRetryAfter, err := time.ParseDuration(getEnv("LINK_PREVIEW_RETRY_AFTER", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LINK_PREVIEW_RETRY_AFTER format: %w", err)
+	}
+
+	schedulingInterval, err := time.ParseDuration(getEnv("SCHEDULED_POSTS_INTERVAL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCHEDULED_POSTS_INTERVAL format: %w", err)
+	}
+
+	trashRetention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRASH_RETENTION format: %w", err)
+	}
+
+	trashPurgeInterval, err := time.ParseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRASH_PURGE_INTERVAL format: %w", err)
+	}
+
+	digestInterval, err := time.ParseDuration(getEnv("DIGEST_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DIGEST_INTERVAL format: %w", err)
+	}
+
+	pushTTL, err := time.ParseDuration(getEnv("PUSH_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUSH_TTL format: %w", err)
+	}
+
+	pushTimeout, err := time.ParseDuration(getEnv("PUSH_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUSH_TIMEOUT format: %w", err)
+	}
+
+	var reminderOffsets []time.Duration
+	for _, value := range getEnvList("EVENT_REMINDER_OFFSETS", "24h,2h") {
+		offset, err := time.ParseDuration(value)
+		if err != nil || offset <= 0 {
+			return nil, fmt.Errorf("invalid EVENT_REMINDER_OFFSETS format: %q", value)
+		}
+		reminderOffsets = append(reminderOffsets, offset)
+	}
+
+	reminderInterval, err := time.ParseDuration(getEnv("EVENT_REMINDER_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_REMINDER_INTERVAL format: %w", err)
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port: getEnv("PORT", "8080"),
@@ -118,6 +298,69 @@ func Load() (*Config, error) {
 			AgendaUID: getEnv("OPENAGENDA_AGENDA_UID", ""),
 			BaseURL:   getEnv("OPENAGENDA_BASE_URL", "https://api.openagenda.com/v2"), // Default OpenAgenda API
 		},
+		Reactions: ReactionsConfig{
+			Types: getEnvList("REACTION_TYPES", "like,love,laugh,wow,sad,angry"),
+		},
+		Filter: ContentFilterConfig{
+			Enabled:              getEnv("FILTER_ENABLED", "true") == "true",
+			BlockedWords:         getEnvList("FILTER_BLOCKED_WORDS", ""),
+			MaxLinks:             getEnvInt("FILTER_MAX_LINKS", 3),
+			RepeatWindow:         repeatWindow,
+			MaxRepeats:           getEnvInt("FILTER_MAX_REPEATS", 2),
+			NewAccountAge:        newAccountAge,
+			NewAccountMaxPerHour: getEnvInt("FILTER_NEW_ACCOUNT_MAX_PER_HOUR", 10),
+		},
+		Trending: TrendingConfig{
+			Window:          trendingWindow,
+			HalfLife:        trendingHalfLife,
+			RefreshInterval: trendingRefresh,
+			Limit:           getEnvInt("TRENDING_LIMIT", 20),
+		},
+		Previews: LinkPreviewConfig{
+			Enabled:      getEnv("LINK_PREVIEW_ENABLED", "true") == "true",
+			Timeout:      previewTimeout,
+			MaxBytes:     int64(getEnvInt("LINK_PREVIEW_MAX_BYTES", 512*1024)),
+			CacheTTL:     previewCacheTTL,
+			RetryAfter:   previewRetryAfter,
+			Workers:      getEnvInt("LINK_PREVIEW_WORKERS", 4),
+			QueueSize:    getEnvInt("LINK_PREVIEW_QUEUE_SIZE", 100),
+			AllowPrivate: getEnv("LINK_PREVIEW_ALLOW_PRIVATE", "false") == "true",
+		},
+		Scheduling: SchedulingConfig{
+			Interval: schedulingInterval,
+		},
+		Trash: TrashConfig{
+			Retention:      trashRetention,
+			PurgeInterval:  trashPurgeInterval,
+			PurgeBatchSize: getEnvInt("TRASH_PURGE_BATCH_SIZE", 500),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "file"),
+			From:         getEnv("MAIL_FROM", "City-Buzz <no-reply@city-buzz.local>"),
+			Dir:          getEnv("MAIL_DIR", "./mail"),
+			SMTPHost:     getEnv("MAIL_SMTP_HOST", ""),
+			SMTPPort:     getEnv("MAIL_SMTP_PORT", "587"),
+			SMTPUsername: getEnv("MAIL_SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("MAIL_SMTP_PASSWORD", ""),
+		},
+		Digest: DigestConfig{
+			Interval:  digestInterval,
+			Hour:      getEnvInt("DIGEST_HOUR", 8),
+			BatchSize: getEnvInt("DIGEST_BATCH_SIZE", 100),
+		},
+		Reminders: ReminderConfig{
+			Offsets:   reminderOffsets,
+			Interval:  reminderInterval,
+			BatchSize: getEnvInt("EVENT_REMINDER_BATCH_SIZE", 500),
+		},
+		Push: PushConfig{
+			VAPIDPublicKey:  getEnv("VAPID_PUBLIC_KEY", ""),
+			VAPIDPrivateKey: getEnv("VAPID_PRIVATE_KEY", ""),
+			Subject:         getEnv("VAPID_SUBJECT", "mailto:admin@city-buzz.local"),
+			TTL:             pushTTL,
+			Timeout:         pushTimeout,
+			AllowInsecure:   getEnv("PUSH_ALLOW_INSECURE", "false") == "true",
+		},
 	}
 
 	return config, nil
@@ -131,6 +374,26 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getEnvInt reads an integer, falling back to the default when unset or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList reads a comma-separated list, skipping empty entries
+func getEnvList(key, defaultValue string) []string {
+	values := []string{}
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
@@ -7,21 +7,16 @@ import (
 
 	"github.com/Aolakije/City-Buzz/pkg/config"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func NewPostgresDB(cfg *config.Config) (*pgxpool.Pool, error) {
-	dsn := cfg.GetDSN()
-
-	poolConfig, err := pgxpool.ParseConfig(dsn)
+	poolConfig, err := newPoolConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse database config: %w", err)
+		return nil, err
 	}
 
-	// Connection pool settings
-	poolConfig.MaxConns = 25
-	poolConfig.MinConns = 5
-
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
@@ -36,6 +31,28 @@ func NewPostgresDB(cfg *config.Config) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
+// newPoolConfig builds the connection pool settings. TIMESTAMP columns hold UTC
+// wall clock times: time.Time parameters are converted to UTC before being
+// sent, and sessions are pinned to UTC so NOW() agrees whatever the server's
+// time zone.
+func newPoolConfig(cfg *config.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
+	}
+
+	// Connection pool settings
+	poolConfig.MaxConns = 25
+	poolConfig.MinConns = 5
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		registerUTCTimestamps(conn.TypeMap())
+		return nil
+	}
+
+	return poolConfig, nil
+}
+
 func ClosePostgresDB(pool *pgxpool.Pool) {
 	if pool != nil {
 		pool.Close()
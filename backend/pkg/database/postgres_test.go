@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestNewPoolConfigPinsSessionsToUTC(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "citybuzz",
+		Password: "secret",
+		DBName:   "citybuzz",
+		SSLMode:  "disable",
+	}}
+
+	poolConfig, err := newPoolConfig(cfg)
+	if err != nil {
+		t.Fatalf("newPoolConfig: %v", err)
+	}
+
+	if got := poolConfig.ConnConfig.RuntimeParams["timezone"]; got != "UTC" {
+		t.Errorf("timezone = %q, want UTC", got)
+	}
+}
+
+func TestUTCTimestampCodecEncodesUTCWallClock(t *testing.T) {
+	m := pgtype.NewMap()
+	registerUTCTimestamps(m)
+
+	paris := time.FixedZone("Europe/Paris", 2*60*60)
+	at := time.Date(2026, 10, 19, 10, 0, 0, 0, paris)
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{name: "time", value: at},
+		{name: "pointer", value: &at},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := m.Encode(pgtype.TimestampOID, pgtype.TextFormatCode, tt.value, nil)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got, want := string(buf), "2026-10-19 08:00:00"; got != want {
+				t.Errorf("encoded %q, want %q", got, want)
+			}
+		})
+	}
+
+	// Binary values read back hold the same instant
+	buf, err := m.Encode(pgtype.TimestampOID, pgtype.BinaryFormatCode, at, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var got time.Time
+	if err := m.Scan(pgtype.TimestampOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !got.Equal(at) {
+		t.Errorf("read back %v, want %v", got, at)
+	}
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// utcTimestampCodec writes time.Time parameters into TIMESTAMP values as UTC
+// wall clock times. pgx otherwise keeps the wall clock of whatever zone the
+// time carries, usually the host's local one, and drops the zone.
+type utcTimestampCodec struct {
+	pgtype.TimestampCodec
+}
+
+func (c utcTimestampCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(time.Time); ok {
+		return &encodePlanUTCTimestamp{next: c.TimestampCodec.PlanEncode(m, oid, format, pgtype.Timestamp{})}
+	}
+	return c.TimestampCodec.PlanEncode(m, oid, format, value)
+}
+
+type encodePlanUTCTimestamp struct {
+	next pgtype.EncodePlan
+}
+
+func (p *encodePlanUTCTimestamp) Encode(value any, buf []byte) ([]byte, error) {
+	return p.next.Encode(pgtype.Timestamp{Time: value.(time.Time).UTC(), Valid: true}, buf)
+}
+
+// registerUTCTimestamps makes a connection's TIMESTAMP and TIMESTAMP[]
+// parameters use utcTimestampCodec
+func registerUTCTimestamps(m *pgtype.Map) {
+	timestamp := &pgtype.Type{Name: "timestamp", OID: pgtype.TimestampOID, Codec: utcTimestampCodec{}}
+	m.RegisterType(timestamp)
+	m.RegisterType(&pgtype.Type{Name: "_timestamp", OID: pgtype.TimestampArrayOID, Codec: &pgtype.ArrayCodec{ElementType: timestamp}})
+}
@@ -0,0 +1,66 @@
+// Package netguard keeps outgoing requests to user-supplied URLs away from
+// internal networks.
+package netguard
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// GuardPublicAddress refuses to connect to non-public IP addresses. It is meant
+// as a net.Dialer Control function: the check runs on the address actually
+// dialed, so it also covers redirects and DNS rebinding.
+func GuardPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+
+	return nil
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, block := range reservedBlocks {
+		if block.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reservedBlocks are non-public ranges not covered by the net.IP helpers
+var reservedBlocks = func() []*net.IPNet {
+	var blocks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",       // "this" network
+		"100.64.0.0/10",   // carrier-grade NAT
+		"192.0.0.0/24",    // IETF protocol assignments
+		"192.0.2.0/24",    // documentation
+		"198.18.0.0/15",   // benchmarking
+		"198.51.100.0/24", // documentation
+		"203.0.113.0/24",  // documentation
+		"240.0.0.0/4",     // reserved
+		"64:ff9b::/96",    // NAT64, may map to private IPv4
+		"2001:db8::/32",   // documentation
+	} {
+		_, block, err := net.ParseCIDR(cidr)
+		if err == nil {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}()
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor is a position in a list ordered by time, then ID, for keyset pagination
+type Cursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+// EncodeCursor turns a position into an opaque string for clients
+func EncodeCursor(c *Cursor) string {
+	raw := c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a string made by EncodeCursor
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	timePart, idPart, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, timePart)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor{At: at, ID: id}, nil
+}
+
+// ParseCursor decodes an optional cursor sent by a client; an empty one is nil
+func ParseCursor(rawCursor string) (*Cursor, error) {
+	if rawCursor == "" {
+		return nil, nil
+	}
+
+	cursor, err := DecodeCursor(rawCursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
@@ -0,0 +1,161 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// recordSize is the record size announced in the encrypted content header. The
+// whole payload fits in a single record.
+const recordSize = 4096
+
+// headerSize is the size of the aes128gcm header: salt, record size, key ID
+// length and the 65 bytes of the sender's public key used as key ID
+const headerSize = 16 + 4 + 1 + 65
+
+// MaxPayloadSize is the largest payload that fits in the 4096 bytes push
+// services accept: the header, the GCM tag and the padding delimiter take the rest
+const MaxPayloadSize = recordSize - headerSize - aes.BlockSize - 1
+
+// Encrypt encrypts a payload for a subscription as defined by RFC 8291, using
+// the aes128gcm content coding of RFC 8188. p256dh is the subscription's public
+// key and auth its authentication secret.
+func Encrypt(payload, p256dh, auth []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, fmt.Errorf("payload too large")
+	}
+
+	clientKey, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	if len(auth) != 16 {
+		return nil, fmt.Errorf("invalid subscription auth secret")
+	}
+
+	// A new key pair and salt for every message
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	secret, err := serverKey.ECDH(clientKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+
+	serverPublic := serverKey.PublicKey().Bytes()
+	gcm, nonce, err := contentCipher(secret, auth, salt, clientKey.Bytes(), serverPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// The 0x02 delimiter marks the last (and only) record
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// Decrypt reverses Encrypt for the holder of the subscription's private key, as
+// a browser does. It is used by the local stand-in push service.
+func Decrypt(body []byte, clientKey *ecdh.PrivateKey, auth []byte) ([]byte, error) {
+	if len(body) < headerSize+aes.BlockSize {
+		return nil, fmt.Errorf("message too short")
+	}
+
+	salt := body[:16]
+	if size := binary.BigEndian.Uint32(body[16:20]); size < uint32(len(body)-headerSize) {
+		return nil, fmt.Errorf("multiple records are not supported")
+	}
+	if body[20] != 65 {
+		return nil, fmt.Errorf("invalid key ID length")
+	}
+
+	serverKey, err := ecdh.P256().NewPublicKey(body[21:headerSize])
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender key: %w", err)
+	}
+
+	secret, err := clientKey.ECDH(serverKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+
+	gcm, nonce, err := contentCipher(secret, auth, salt, clientKey.PublicKey().Bytes(), serverKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, body[headerSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt message: %w", err)
+	}
+
+	// Strip the padding, then the delimiter
+	plaintext = bytes.TrimRight(plaintext, "\x00")
+	if len(plaintext) == 0 || plaintext[len(plaintext)-1] != 0x02 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return plaintext[:len(plaintext)-1], nil
+}
+
+// contentCipher derives the content encryption key and nonce of a message from
+// the ECDH shared secret, as defined by RFC 8291 section 3.4 and RFC 8188
+// section 2.2
+func contentCipher(secret, auth, salt, clientPublic, serverPublic []byte) (cipher.AEAD, []byte, error) {
+	prkKey, err := hkdf.Extract(sha256.New, secret, auth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyInfo := "WebPush: info\x00" + string(clientPublic) + string(serverPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gcm, nonce, nil
+}
@@ -0,0 +1,231 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// StandIn is a local push service for development and testing. It hands out
+// subscriptions the way a browser would, then checks the VAPID signature of
+// the messages pushed to them, decrypts them and keeps them for inspection.
+//
+//	POST   /subscriptions               creates a subscription, returned as a PushSubscription JSON
+//	DELETE /subscriptions/{id}          expires a subscription; pushes to it then get 410 Gone
+//	POST   /push/{id}                   receives a push message
+//	GET    /subscriptions/{id}/messages lists the decrypted messages received
+type StandIn struct {
+	baseURL       string
+	origin        string // Audience expected in VAPID tokens
+	mu            sync.Mutex
+	subscriptions map[string]*standInSubscription
+	mux           *http.ServeMux
+}
+
+type standInSubscription struct {
+	key      *ecdh.PrivateKey
+	auth     []byte
+	expired  bool
+	messages []StandInMessage
+}
+
+// StandInMessage is a push message received by the stand-in
+type StandInMessage struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Urgency    string          `json:"urgency"`
+	TTL        string          `json:"ttl"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewStandIn creates a stand-in whose subscription endpoints start with baseURL
+func NewStandIn(baseURL string) *StandIn {
+	origin := baseURL
+	if u, err := url.Parse(baseURL); err == nil {
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	s := &StandIn{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		origin:        origin,
+		subscriptions: make(map[string]*standInSubscription),
+		mux:           http.NewServeMux(),
+	}
+	s.mux.HandleFunc("POST /subscriptions", s.subscribe)
+	s.mux.HandleFunc("DELETE /subscriptions/{id}", s.expire)
+	s.mux.HandleFunc("GET /subscriptions/{id}/messages", s.messages)
+	s.mux.HandleFunc("POST /push/{id}", s.push)
+	return s
+}
+
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *StandIn) subscribe(w http.ResponseWriter, r *http.Request) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	id := uuid.NewString()
+	s.mu.Lock()
+	s.subscriptions[id] = &standInSubscription{key: key, auth: auth}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"endpoint": s.baseURL + "/push/" + id,
+		"keys": map[string]string{
+			"p256dh": base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+			"auth":   base64.RawURLEncoding.EncodeToString(auth),
+		},
+	})
+}
+
+func (s *StandIn) expire(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	sub.expired = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *StandIn) messages(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, sub.messages)
+}
+
+func (s *StandIn) push(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	sub, ok := s.subscriptions[id]
+	expired := ok && sub.expired
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		http.NotFound(w, r)
+		return
+	case expired:
+		http.Error(w, "subscription expired", http.StatusGone)
+		return
+	}
+
+	if err := verifyVAPID(r.Header.Get("Authorization"), s.origin); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, recordSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > recordSize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	payload, err := Decrypt(body, sub.key, sub.auth)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	message := StandInMessage{
+		ReceivedAt: time.Now(),
+		Urgency:    r.Header.Get("Urgency"),
+		TTL:        r.Header.Get("TTL"),
+		Payload:    payload,
+	}
+	if !json.Valid(payload) {
+		message.Payload, _ = json.Marshal(string(payload))
+	}
+
+	s.mu.Lock()
+	sub.messages = append(sub.messages, message)
+	s.mu.Unlock()
+
+	log.Printf("Push to %s: %s", id, payload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// verifyVAPID checks the "vapid t=..., k=..." Authorization header of a push:
+// the token must be signed with the given key, unexpired and addressed to this
+// push service
+func verifyVAPID(header, audience string) error {
+	params := map[string]string{}
+	scheme, rest, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "vapid") {
+		return fmt.Errorf("missing VAPID authorization")
+	}
+	for _, param := range strings.Split(rest, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		params[name] = value
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(trimPadding(params["k"]))
+	if err != nil || len(raw) != 65 || raw[0] != 4 {
+		return fmt.Errorf("invalid VAPID key")
+	}
+	if _, err := ecdh.P256().NewPublicKey(raw); err != nil {
+		return fmt.Errorf("invalid VAPID key")
+	}
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(raw[1:33]),
+		Y:     new(big.Int).SetBytes(raw[33:]),
+	}
+
+	token, err := jwt.Parse(params["t"], func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}), jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return fmt.Errorf("invalid VAPID token: %v", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
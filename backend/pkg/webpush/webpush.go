@@ -0,0 +1,260 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Aolakije/City-Buzz/pkg/config"
+	"github.com/Aolakije/City-Buzz/pkg/netguard"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrGone is returned when the push service reports that a subscription
+// expired or was removed; it should be deleted
+var ErrGone = errors.New("push subscription gone")
+
+// vapidExpiry is the lifetime of the VAPID tokens, below the 24 hours allowed by RFC 8292
+const vapidExpiry = 12 * time.Hour
+
+// Urgency values of RFC 8030, telling push services how soon to wake the device
+const (
+	UrgencyNormal = "normal"
+	UrgencyHigh   = "high"
+)
+
+// Subscription is where and how to deliver messages to a browser, as given by
+// its PushSubscription
+type Subscription struct {
+	Endpoint string
+	P256dh   string // Public key, base64url encoded
+	Auth     string // Authentication secret, base64url encoded
+}
+
+// Keys decodes the subscription's public key and authentication secret
+func (sub *Subscription) Keys() (p256dh, auth []byte, err error) {
+	p256dh, err = base64.RawURLEncoding.DecodeString(trimPadding(sub.P256dh))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	if _, err := ecdh.P256().NewPublicKey(p256dh); err != nil {
+		return nil, nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+
+	auth, err = base64.RawURLEncoding.DecodeString(trimPadding(sub.Auth))
+	if err != nil || len(auth) != 16 {
+		return nil, nil, fmt.Errorf("invalid subscription auth secret")
+	}
+
+	return p256dh, auth, nil
+}
+
+// Sender delivers encrypted messages to push services, identifying itself with
+// VAPID (RFC 8292)
+type Sender struct {
+	client        *http.Client
+	key           *ecdsa.PrivateKey
+	publicKey     string
+	subject       string
+	ttl           time.Duration
+	allowInsecure bool
+}
+
+// NewSender creates a sender from the configured VAPID key pair. It returns nil
+// when no key is configured, which disables Web Push.
+func NewSender(cfg *config.Config) (*Sender, error) {
+	if cfg.Push.VAPIDPrivateKey == "" {
+		return nil, nil
+	}
+
+	key, err := parsePrivateKey(cfg.Push.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := base64.RawURLEncoding.EncodeToString(publicKeyBytes(key))
+	if cfg.Push.VAPIDPublicKey != "" && cfg.Push.VAPIDPublicKey != publicKey {
+		return nil, fmt.Errorf("VAPID public key does not match the private key")
+	}
+
+	// Push services are public; private addresses are only reachable when
+	// insecure endpoints are allowed, for the local stand-in
+	dialer := &net.Dialer{Timeout: cfg.Push.Timeout}
+	if !cfg.Push.AllowInsecure {
+		dialer.Control = netguard.GuardPublicAddress
+	}
+
+	client := &http.Client{
+		Timeout: cfg.Push.Timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: cfg.Push.Timeout,
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &Sender{
+		client:        client,
+		key:           key,
+		publicKey:     publicKey,
+		subject:       cfg.Push.Subject,
+		ttl:           cfg.Push.TTL,
+		allowInsecure: cfg.Push.AllowInsecure,
+	}, nil
+}
+
+// PublicKey returns the VAPID public key browsers subscribe with, base64url encoded
+func (s *Sender) PublicKey() string {
+	return s.publicKey
+}
+
+// ValidateEndpoint checks that a subscription endpoint can be delivered to
+func (s *Sender) ValidateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid push endpoint")
+	}
+	if u.Scheme != "https" && !(u.Scheme == "http" && s.allowInsecure) {
+		return fmt.Errorf("invalid push endpoint")
+	}
+	return nil
+}
+
+// Send encrypts a payload for a subscription and delivers it to its push
+// service. It returns ErrGone when the subscription no longer exists.
+func (s *Sender) Send(ctx context.Context, sub *Subscription, payload []byte, urgency string) error {
+	if err := s.ValidateEndpoint(sub.Endpoint); err != nil {
+		return err
+	}
+
+	p256dh, auth, err := sub.Keys()
+	if err != nil {
+		return err
+	}
+
+	body, err := Encrypt(payload, p256dh, auth)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := s.vapidAuthorization(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(s.ttl.Seconds())))
+	req.Header.Set("Urgency", urgency)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver push message: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("push service responded %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// vapidAuthorization returns the Authorization header identifying this server
+// to the push service of an endpoint
+func (s *Sender) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid push endpoint")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpiry).Unix(),
+		"sub": s.subject,
+	})
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	return "vapid t=" + signed + ", k=" + s.publicKey, nil
+}
+
+// GenerateVAPIDKeys creates a VAPID key pair, base64url encoded as expected in the configuration
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+// parsePrivateKey decodes a base64url P-256 private key
+func parsePrivateKey(encoded string) (*ecdsa.PrivateKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(trimPadding(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	public := key.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}, nil
+}
+
+// publicKeyBytes encodes a public key in the uncompressed form used by Web Push
+func publicKeyBytes(key *ecdsa.PrivateKey) []byte {
+	public := make([]byte, 65)
+	public[0] = 4
+	key.X.FillBytes(public[1:33])
+	key.Y.FillBytes(public[33:])
+	return public
+}
+
+// trimPadding accepts base64url values with or without padding
+func trimPadding(value string) string {
+	for len(value) > 0 && value[len(value)-1] == '=' {
+		value = value[:len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,384 @@
+// Package websocket implements the server side of the WebSocket protocol
+// (RFC 6455) for Fiber handlers, without extensions or subprotocols.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Message types, as frame opcodes
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	closeMessage  = 8
+	pingMessage   = 9
+	pongMessage   = 10
+
+	continuationFrame = 0
+)
+
+// Close codes of RFC 6455 section 7.4.1
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseTooBig          = 1009
+	CloseInternalError   = 1011
+)
+
+// acceptGUID is appended to the client's key to compute Sec-WebSocket-Accept
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// writeTimeout bounds every frame written, so a stalled client cannot block its writers
+const writeTimeout = 10 * time.Second
+
+// defaultReadLimit is the largest message accepted unless SetReadLimit says otherwise
+const defaultReadLimit = 64 * 1024
+
+// ErrClosed is returned when writing after the connection was closed
+var ErrClosed = errors.New("websocket closed")
+
+// CloseError is returned by ReadMessage when the connection is closed, by the
+// client or because it broke the protocol
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed with code %d: %s", e.Code, e.Reason)
+}
+
+// Conn is an established WebSocket connection. Reads must happen on a single
+// goroutine; writes are safe from several.
+type Conn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	readLimit   int64
+	idleTimeout time.Duration
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// IsUpgrade reports whether a request asks to open a WebSocket connection
+func IsUpgrade(c *fiber.Ctx) bool {
+	return hasToken(c.Get(fiber.HeaderConnection), "upgrade") && strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket")
+}
+
+// Upgrade answers a WebSocket opening handshake, then serves the connection
+// with handler once the response is sent. The connection is closed when
+// handler returns. Handler runs after the Fiber handler returned, so it must
+// not use c. It returns the error to answer with when the handshake is invalid.
+func Upgrade(c *fiber.Ctx, handler func(*Conn)) *fiber.Error {
+	if c.Method() != fiber.MethodGet {
+		return fiber.NewError(fiber.StatusMethodNotAllowed, "WebSocket handshake must use GET")
+	}
+
+	if !IsUpgrade(c) {
+		c.Set(fiber.HeaderUpgrade, "websocket")
+		return fiber.NewError(fiber.StatusUpgradeRequired, "WebSocket upgrade required")
+	}
+
+	if c.Get("Sec-WebSocket-Version") != "13" {
+		c.Set("Sec-WebSocket-Version", "13")
+		return fiber.NewError(fiber.StatusUpgradeRequired, "Unsupported WebSocket version")
+	}
+
+	key := c.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid WebSocket key")
+	}
+
+	c.Status(fiber.StatusSwitchingProtocols)
+	c.Set(fiber.HeaderUpgrade, "websocket")
+	c.Set(fiber.HeaderConnection, "Upgrade")
+	c.Set("Sec-WebSocket-Accept", acceptKey(key))
+
+	c.Context().Hijack(func(netConn net.Conn) {
+		// Server timeouts no longer apply to the hijacked connection
+		netConn.SetDeadline(time.Time{})
+
+		handler(&Conn{
+			conn:      netConn,
+			reader:    bufio.NewReader(netConn),
+			readLimit: defaultReadLimit,
+		})
+	})
+
+	return nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value proving the handshake was understood
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// hasToken reports whether a comma-separated header contains a token, ignoring case
+func hasToken(header, token string) bool {
+	for _, value := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetReadLimit sets the size of the largest message accepted; larger ones close the connection
+func (c *Conn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+// SetIdleTimeout makes reads fail when nothing, not even a pong, arrives for
+// the given duration. Zero disables it.
+func (c *Conn) SetIdleTimeout(timeout time.Duration) {
+	c.idleTimeout = timeout
+}
+
+// RemoteAddr returns the address of the client
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage waits for the next text or binary message, answering pings and
+// reassembling fragmented messages meanwhile. It returns a *CloseError once
+// the connection is closed.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	messageType := 0
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := c.readFrame(int64(len(message)))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case pingMessage:
+			if err := c.write(pongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case pongMessage:
+			continue
+		case closeMessage:
+			return 0, nil, c.handleClose(payload)
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(CloseProtocolError, "expected a continuation frame")
+			}
+			messageType = opcode
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, c.fail(CloseProtocolError, "unknown opcode")
+		}
+
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+
+		if messageType == TextMessage && !utf8.Valid(message) {
+			return 0, nil, c.fail(CloseInvalidPayload, "invalid UTF-8")
+		}
+		return messageType, message, nil
+	}
+}
+
+// readFrame reads one frame and unmasks its payload. read is the size of the
+// message read so far, counted against the read limit.
+func (c *Conn) readFrame(read int64) (fin bool, opcode int, payload []byte, err error) {
+	if c.idleTimeout > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, c.broken(err)
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "reserved bits set")
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, "client frames must be masked")
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.broken(err)
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, c.broken(err)
+		}
+		if extended[0]&0x80 != 0 {
+			return false, 0, nil, c.fail(CloseProtocolError, "invalid frame length")
+		}
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	if opcode >= closeMessage && (!fin || length > 125) {
+		return false, 0, nil, c.fail(CloseProtocolError, "invalid control frame")
+	}
+	if opcode < closeMessage && read+length > c.readLimit {
+		return false, 0, nil, c.fail(CloseTooBig, "message too large")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, c.broken(err)
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, c.broken(err)
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// handleClose answers a close frame from the client with the same code
+func (c *Conn) handleClose(payload []byte) error {
+	code, reason := CloseNormal, ""
+	if len(payload) >= 2 {
+		code = int(binary.BigEndian.Uint16(payload))
+		reason = string(payload[2:])
+		if !validCloseCode(code) || !utf8.ValidString(reason) {
+			return c.fail(CloseProtocolError, "invalid close frame")
+		}
+	} else if len(payload) == 1 {
+		return c.fail(CloseProtocolError, "invalid close frame")
+	}
+
+	c.Close(code, "")
+	return &CloseError{Code: code, Reason: reason}
+}
+
+// validCloseCode reports whether a client may send a close code
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1011:
+		return true
+	case code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
+
+// fail closes the connection after a protocol violation by the client
+func (c *Conn) fail(code int, reason string) error {
+	c.Close(code, reason)
+	return &CloseError{Code: code, Reason: reason}
+}
+
+// broken reports a connection that dropped or timed out without a close frame
+func (c *Conn) broken(err error) error {
+	c.writeMu.Lock()
+	c.closed = true
+	c.writeMu.Unlock()
+
+	return &CloseError{Code: CloseGoingAway, Reason: err.Error()}
+}
+
+// WriteMessage sends a text or binary message in a single frame
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("invalid message type %d", messageType)
+	}
+	return c.write(messageType, data)
+}
+
+// Ping sends a ping; the client's pong keeps the connection from going idle
+func (c *Conn) Ping() error {
+	return c.write(pingMessage, nil)
+}
+
+// Close sends a close frame with the given code and reason. Nothing can be
+// written afterwards; the connection itself is closed when the handler returns.
+func (c *Conn) Close(code int, reason string) error {
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	err := c.writeFrame(closeMessage, payload)
+	c.closed = true
+	return err
+}
+
+// write sends a frame unless the connection is closed
+func (c *Conn) write(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	if err := c.writeFrame(opcode, payload); err != nil {
+		c.closed = true
+		return err
+	}
+	return nil
+}
+
+// writeFrame writes an unmasked final frame; c.writeMu must be held
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|byte(opcode))
+
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+	frame = append(frame, payload...)
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
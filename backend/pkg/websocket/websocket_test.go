@@ -0,0 +1,490 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// connPair returns a server Conn and the client end of a loopback TCP
+// connection. TCP rather than net.Pipe so that writes don't wait for reads.
+func connPair(t *testing.T) (*Conn, net.Conn) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		accepted <- conn
+	}()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	server, ok := <-accepted
+	if !ok {
+		t.Fatal("accept failed")
+	}
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	server.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return &Conn{conn: server, reader: bufio.NewReader(server), readLimit: defaultReadLimit}, client
+}
+
+// sendFrame writes a client frame, masked unless told otherwise
+func sendFrame(t *testing.T, conn net.Conn, fin bool, opcode int, payload []byte, masked bool) {
+	t.Helper()
+
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	data := append([]byte(nil), payload...)
+	if masked {
+		mask := []byte{0x12, 0x34, 0x56, 0x78}
+		frame = append(frame, mask...)
+		for i := range data {
+			data[i] ^= mask[i%4]
+		}
+	}
+	frame = append(frame, data...)
+
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+}
+
+// receiveFrame reads a server frame, which must be final and unmasked
+func receiveFrame(t *testing.T, conn net.Conn) (int, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if header[0]&0x80 == 0 {
+		t.Fatal("server frame is not final")
+	}
+	if header[1]&0x80 != 0 {
+		t.Fatal("server frame is masked")
+	}
+
+	length := int(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		io.ReadFull(conn, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		io.ReadFull(conn, extended[:])
+		length = int(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return int(header[0] & 0x0f), payload
+}
+
+// receiveClose reads the server's close frame and returns its code
+func receiveClose(t *testing.T, conn net.Conn) int {
+	t.Helper()
+
+	opcode, payload := receiveFrame(t, conn)
+	if opcode != closeMessage {
+		t.Fatalf("got opcode %d, want a close frame", opcode)
+	}
+	if len(payload) < 2 {
+		t.Fatalf("close frame without a code")
+	}
+	return int(binary.BigEndian.Uint16(payload))
+}
+
+// closePayload builds the payload of a close frame
+func closePayload(code int, reason string) []byte {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return append(payload, reason...)
+}
+
+// expectCloseError checks that err is a *CloseError with the given code
+func expectCloseError(t *testing.T, err error, code int) {
+	t.Helper()
+
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("got error %v, want a CloseError", err)
+	}
+	if closeErr.Code != code {
+		t.Fatalf("got close code %d, want %d (%s)", closeErr.Code, code, closeErr.Reason)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example of RFC 6455 section 1.3
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Fatalf("acceptKey = %q, want %q", got, want)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", func(c *fiber.Ctx) error {
+		if err := Upgrade(c, func(conn *Conn) {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, data)
+		}); err != nil {
+			return c.Status(err.Code).SendString(err.Message)
+		}
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	handshake := func(t *testing.T, version, key string) (net.Conn, *http.Response) {
+		t.Helper()
+
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		request := "GET /ws HTTP/1.1\r\n" +
+			"Host: localhost\r\n" +
+			"Connection: keep-alive, Upgrade\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Sec-WebSocket-Version: " + version + "\r\n" +
+			"Sec-WebSocket-Key: " + key + "\r\n\r\n"
+		if _, err := conn.Write([]byte(request)); err != nil {
+			t.Fatalf("write handshake: %v", err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("read handshake response: %v", err)
+		}
+		return conn, resp
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		conn, resp := handshake(t, "13", "dGhlIHNhbXBsZSBub25jZQ==")
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("got status %d, want 101", resp.StatusCode)
+		}
+		if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Fatalf("got Sec-WebSocket-Accept %q", got)
+		}
+
+		sendFrame(t, conn, true, TextMessage, []byte("hello"), true)
+		if opcode, payload := receiveFrame(t, conn); opcode != TextMessage || string(payload) != "hello" {
+			t.Fatalf("got echo %d %q", opcode, payload)
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, resp := handshake(t, "8", "dGhlIHNhbXBsZSBub25jZQ==")
+		if resp.StatusCode != http.StatusUpgradeRequired {
+			t.Fatalf("got status %d, want 426", resp.StatusCode)
+		}
+		if got := resp.Header.Get("Sec-WebSocket-Version"); got != "13" {
+			t.Fatalf("got Sec-WebSocket-Version %q, want 13", got)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, resp := handshake(t, "13", "c2hvcnQ=")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("got status %d, want 400", resp.StatusCode)
+		}
+	})
+}
+
+func TestReadMessageFragmented(t *testing.T) {
+	server, client := connPair(t)
+
+	sendFrame(t, client, false, TextMessage, []byte("Hel"), true)
+	sendFrame(t, client, true, pingMessage, []byte("are you there"), true)
+	sendFrame(t, client, false, continuationFrame, []byte("lo, "), true)
+	sendFrame(t, client, true, pongMessage, nil, true)
+	sendFrame(t, client, true, continuationFrame, []byte("world"), true)
+
+	messageType, data, err := server.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if messageType != TextMessage || string(data) != "Hello, world" {
+		t.Fatalf("got %d %q", messageType, data)
+	}
+
+	// The ping in between is answered with the same payload
+	if opcode, payload := receiveFrame(t, client); opcode != pongMessage || string(payload) != "are you there" {
+		t.Fatalf("got %d %q, want the pong", opcode, payload)
+	}
+}
+
+func TestReadMessageProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		send func(t *testing.T, client net.Conn)
+	}{
+		{"unmasked frame", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, true, TextMessage, []byte("hi"), false)
+		}},
+		{"continuation without a message", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, true, continuationFrame, []byte("hi"), true)
+		}},
+		{"new message inside a fragmented one", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, false, TextMessage, []byte("a"), true)
+			sendFrame(t, client, true, TextMessage, []byte("b"), true)
+		}},
+		{"fragmented control frame", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, false, pingMessage, []byte("a"), true)
+		}},
+		{"oversized control frame", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, true, pingMessage, bytes.Repeat([]byte("a"), 126), true)
+		}},
+		{"reserved bits", func(t *testing.T, client net.Conn) {
+			client.Write([]byte{0x80 | 0x40 | TextMessage, 0x80, 0, 0, 0, 0})
+		}},
+		{"unknown opcode", func(t *testing.T, client net.Conn) {
+			sendFrame(t, client, true, 3, nil, true)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := connPair(t)
+			tt.send(t, client)
+
+			_, _, err := server.ReadMessage()
+			expectCloseError(t, err, CloseProtocolError)
+			if code := receiveClose(t, client); code != CloseProtocolError {
+				t.Fatalf("got close code %d, want %d", code, CloseProtocolError)
+			}
+		})
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	t.Run("single frame", func(t *testing.T) {
+		server, client := connPair(t)
+		server.SetReadLimit(10)
+
+		sendFrame(t, client, true, BinaryMessage, bytes.Repeat([]byte("a"), 11), true)
+
+		_, _, err := server.ReadMessage()
+		expectCloseError(t, err, CloseTooBig)
+		if code := receiveClose(t, client); code != CloseTooBig {
+			t.Fatalf("got close code %d, want %d", code, CloseTooBig)
+		}
+	})
+
+	t.Run("fragments adding up", func(t *testing.T) {
+		server, client := connPair(t)
+		server.SetReadLimit(10)
+
+		sendFrame(t, client, false, BinaryMessage, bytes.Repeat([]byte("a"), 6), true)
+		sendFrame(t, client, true, continuationFrame, bytes.Repeat([]byte("a"), 6), true)
+
+		_, _, err := server.ReadMessage()
+		expectCloseError(t, err, CloseTooBig)
+	})
+
+	t.Run("extended length over the limit", func(t *testing.T) {
+		server, client := connPair(t)
+
+		// Only the header is sent: the length alone must be refused
+		header := []byte{0x80 | BinaryMessage, 0x80 | 127}
+		header = binary.BigEndian.AppendUint64(header, 1<<40)
+		client.Write(header)
+
+		_, _, err := server.ReadMessage()
+		expectCloseError(t, err, CloseTooBig)
+	})
+
+	t.Run("at the limit", func(t *testing.T) {
+		server, client := connPair(t)
+		server.SetReadLimit(200)
+
+		sendFrame(t, client, true, BinaryMessage, bytes.Repeat([]byte("a"), 200), true)
+
+		if _, data, err := server.ReadMessage(); err != nil || len(data) != 200 {
+			t.Fatalf("got %d bytes, error %v", len(data), err)
+		}
+	})
+}
+
+func TestReadMessageUTF8(t *testing.T) {
+	t.Run("invalid text", func(t *testing.T) {
+		server, client := connPair(t)
+		sendFrame(t, client, true, TextMessage, []byte{'a', 0xff, 'b'}, true)
+
+		_, _, err := server.ReadMessage()
+		expectCloseError(t, err, CloseInvalidPayload)
+		if code := receiveClose(t, client); code != CloseInvalidPayload {
+			t.Fatalf("got close code %d, want %d", code, CloseInvalidPayload)
+		}
+	})
+
+	t.Run("code point split across fragments", func(t *testing.T) {
+		server, client := connPair(t)
+		euro := []byte("€")
+		sendFrame(t, client, false, TextMessage, euro[:1], true)
+		sendFrame(t, client, true, continuationFrame, euro[1:], true)
+
+		if _, data, err := server.ReadMessage(); err != nil || string(data) != "€" {
+			t.Fatalf("got %q, error %v", data, err)
+		}
+	})
+
+	t.Run("binary is not checked", func(t *testing.T) {
+		server, client := connPair(t)
+		sendFrame(t, client, true, BinaryMessage, []byte{0xff}, true)
+
+		if messageType, _, err := server.ReadMessage(); err != nil || messageType != BinaryMessage {
+			t.Fatalf("got type %d, error %v", messageType, err)
+		}
+	})
+}
+
+func TestReadMessageClose(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   []byte
+		wantCode  int
+		wantReply int
+	}{
+		{"normal", closePayload(CloseNormal, "bye"), CloseNormal, CloseNormal},
+		{"no code", nil, CloseNormal, CloseNormal},
+		{"application code", closePayload(4000, ""), 4000, 4000},
+		{"going away", closePayload(CloseGoingAway, ""), CloseGoingAway, CloseGoingAway},
+		{"truncated code", []byte{0x03}, CloseProtocolError, CloseProtocolError},
+		{"reserved code", closePayload(1004, ""), CloseProtocolError, CloseProtocolError},
+		{"no status code", closePayload(1005, ""), CloseProtocolError, CloseProtocolError},
+		{"abnormal closure", closePayload(1006, ""), CloseProtocolError, CloseProtocolError},
+		{"tls handshake", closePayload(1015, ""), CloseProtocolError, CloseProtocolError},
+		{"out of range", closePayload(5000, ""), CloseProtocolError, CloseProtocolError},
+		{"below range", closePayload(999, ""), CloseProtocolError, CloseProtocolError},
+		{"invalid reason", closePayload(CloseNormal, "\xff"), CloseProtocolError, CloseProtocolError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := connPair(t)
+			sendFrame(t, client, true, closeMessage, tt.payload, true)
+
+			_, _, err := server.ReadMessage()
+			expectCloseError(t, err, tt.wantCode)
+			if code := receiveClose(t, client); code != tt.wantReply {
+				t.Fatalf("got close reply %d, want %d", code, tt.wantReply)
+			}
+
+			// Nothing can be sent once the close frame went out
+			if err := server.WriteMessage(TextMessage, []byte("late")); err != ErrClosed {
+				t.Fatalf("write after close: got %v, want ErrClosed", err)
+			}
+		})
+	}
+}
+
+func TestReadMessageBroken(t *testing.T) {
+	server, client := connPair(t)
+	client.Close()
+
+	_, _, err := server.ReadMessage()
+	expectCloseError(t, err, CloseGoingAway)
+	if err := server.Ping(); err != ErrClosed {
+		t.Fatalf("ping after drop: got %v, want ErrClosed", err)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	server, _ := connPair(t)
+	server.SetIdleTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	_, _, err := server.ReadMessage()
+	expectCloseError(t, err, CloseGoingAway)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("idle read returned after %v", elapsed)
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	server, client := connPair(t)
+
+	for _, size := range []int{0, 125, 126, 0xffff, 0x10000} {
+		payload := bytes.Repeat([]byte("a"), size)
+		if err := server.WriteMessage(BinaryMessage, payload); err != nil {
+			t.Fatalf("WriteMessage(%d bytes): %v", size, err)
+		}
+		if opcode, got := receiveFrame(t, client); opcode != BinaryMessage || len(got) != size {
+			t.Fatalf("got opcode %d with %d bytes, want %d", opcode, len(got), size)
+		}
+	}
+
+	if err := server.WriteMessage(pingMessage, nil); err == nil {
+		t.Fatal("WriteMessage accepted a control opcode")
+	}
+
+	if err := server.Close(CloseNormal, "done"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if code := receiveClose(t, client); code != CloseNormal {
+		t.Fatalf("got close code %d, want %d", code, CloseNormal)
+	}
+	if err := server.Close(CloseNormal, ""); err != ErrClosed {
+		t.Fatalf("second Close: got %v, want ErrClosed", err)
+	}
+}